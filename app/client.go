@@ -1,36 +1,302 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/machinebox/graphql"
 )
 
 var clientInstance GraphQLClientInterface
 
+// Language of server messages, sent as the Accept-Language header
+var language = "pl"
+
+var supportedLanguages = []string{"pl", "en"}
+
+func SetLanguage(lang string) error {
+	for _, supported := range supportedLanguages {
+		if lang == supported {
+			language = lang
+			return nil
+		}
+	}
+	return fmt.Errorf("nieobsługiwany język %s. Dostępne: %s", lang, strings.Join(supportedLanguages, ", "))
+}
+
+// Languages words are translated from and into, sent as the pair argument of queries and mutations
+type LanguagePair struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// Pair chosen with the -pair option or the PAIR command, the server's default when none was chosen
+var languagePair = LanguagePair{Source: "PL", Target: "EN"}
+
+var pairLanguages = []string{"pl", "en", "de", "es"}
+
+// Sets the active pair given as source-target, e.g. de-en
+func SetLanguagePair(pair string) error {
+	usage := fmt.Sprintf("Podaj parę jako język_źródłowy-język_docelowy, np. de-en. Dostępne języki: %s", strings.Join(pairLanguages, ", "))
+
+	languages := strings.Split(strings.ToLower(pair), "-")
+	if len(languages) != 2 || !supportedPairLanguage(languages[0]) || !supportedPairLanguage(languages[1]) {
+		return fmt.Errorf("nieobsługiwana para języków %s. %s", pair, usage)
+	}
+	if languages[0] == languages[1] {
+		return fmt.Errorf("języki pary %s muszą się różnić. %s", pair, usage)
+	}
+
+	languagePair = LanguagePair{Source: strings.ToUpper(languages[0]), Target: strings.ToUpper(languages[1])}
+	return nil
+}
+
+func supportedPairLanguage(lang string) bool {
+	for _, supported := range pairLanguages {
+		if lang == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the active pair as it's given to SetLanguagePair
+func LanguagePairName() string {
+	return strings.ToLower(languagePair.Source + "-" + languagePair.Target)
+}
+
+const endpoint = "http://localhost:8080/query"
+
+// Trailer in which the server reports if the export finished
+const exportStatusTrailer = "X-Export-Status"
+
 type Client struct {
-	client *graphql.Client
+	client   *graphql.Client
+	recorder *responseRecorder
+	http     *http.Client
+	endpoint string
 }
 
 type GraphQLClientInterface interface {
 	Request(req *graphql.Request, resp interface{}) error
+	// Sends the file as the fileVariable of the query
+	Upload(query string, variables map[string]interface{}, fileVariable string, filename string, file io.Reader, resp interface{}) error
+	// Downloads words matching the filter in given format into w
+	Export(format string, filter ExportFilter, w io.Writer) error
+}
+
+// Error returned by the server together with its extensions
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Extensions map[string]interface{} `json:"extensions"`
+}
+
+func (e GraphQLError) Error() string {
+	return "graphql: " + e.Message
+}
+
+// Error codes sent by the server in the "code" extension
+const (
+	CodeWordExists          = "WORD_EXISTS"
+	CodeTranslationExists   = "TRANSLATION_EXISTS"
+	CodeSentenceExists      = "SENTENCE_EXISTS"
+	CodeWordNotFound        = "WORD_NOT_FOUND"
+	CodeEnglishWordNotFound = "ENGLISH_WORD_NOT_FOUND"
+	CodeTranslationNotFound = "TRANSLATION_NOT_FOUND"
+	CodeSentenceNotFound    = "SENTENCE_NOT_FOUND"
+	CodeInternal            = "INTERNAL_ERROR"
+)
+
+func (e GraphQLError) Code() string {
+	code, _ := e.Extensions["code"].(string)
+	return code
+}
+
+// Returns a string field of the error, e.g. the word which wasn't found
+func (e GraphQLError) Field(name string) string {
+	value, _ := e.Extensions[name].(string)
+	return value
+}
+
+// Returns words suggested by the server when the requested one doesn't exist
+func (e GraphQLError) Suggestions() []string {
+	suggestions := []string{}
+	values, _ := e.Extensions["suggestions"].([]interface{})
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			suggestions = append(suggestions, s)
+		}
+	}
+	return suggestions
+}
+
+// graphql.Client keeps only messages of returned errors, so the last response body is recorded
+// to read their extensions
+type responseRecorder struct {
+	transport http.RoundTripper
+	body      []byte
+}
+
+func (r *responseRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	//a failed request must not leave errors of the previous response behind
+	r.body = nil
+	res, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	r.body, err = io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(r.body))
+	return res, nil
+}
+
+func (r *responseRecorder) lastError() (GraphQLError, bool) {
+	var response struct {
+		Errors []GraphQLError `json:"errors"`
+	}
+	if err := json.Unmarshal(r.body, &response); err != nil || len(response.Errors) == 0 {
+		return GraphQLError{}, false
+	}
+	return response.Errors[0], true
 }
 
 func GetClientInstance() GraphQLClientInterface {
 	if clientInstance == nil {
-		clientInstance = &Client{client: graphql.NewClient("http://localhost:8080/query")}
+		recorder := &responseRecorder{transport: http.DefaultTransport}
+		clientInstance = &Client{
+			client:   graphql.NewClient(endpoint, graphql.WithHTTPClient(&http.Client{Transport: recorder})),
+			recorder: recorder,
+			http:     http.DefaultClient,
+			endpoint: endpoint,
+		}
 	}
 
 	return clientInstance
 }
 
 func (c *Client) Request(req *graphql.Request, response interface{}) error {
+	req.Header.Set("Accept-Language", language)
 	if err := c.client.Run(context.Background(), req, response); err != nil {
+		if gqlErr, ok := c.recorder.lastError(); ok {
+			return gqlErr
+		}
 		return err
 	}
 	return nil
 }
 
+// graphql.Client sends files as plain form fields, so uploads are made according to the GraphQL multipart request
+// specification: the query goes in "operations" and "map" tells which variable is the file
+func (c *Client) Upload(query string, variables map[string]interface{}, fileVariable string, filename string, file io.Reader, response interface{}) error {
+	variables[fileVariable] = nil
+	operations, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		return err
+	}
+	fileMap, err := json.Marshal(map[string][]string{"0": {"variables." + fileVariable}})
+	if err != nil {
+		return err
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	writer.WriteField("operations", string(operations))
+	writer.WriteField("map", string(fileMap))
+	part, err := writer.CreateFormFile("0", filename)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Accept-Language", language)
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []GraphQLError  `json:"errors"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return fmt.Errorf("niepoprawna odpowiedź serwera: %s", res.Status)
+	}
+	if len(result.Errors) > 0 {
+		return result.Errors[0]
+	}
+	return json.Unmarshal(result.Data, response)
+}
+
+// Limits exported words, empty fields don't limit anything
+type ExportFilter struct {
+	Prefix string
+	Tag    string
+}
+
+// The export endpoint streams the dictionary and reports in a trailer whether it was sent completely,
+// because the status code is sent before the first word
+func (c *Client) Export(format string, filter ExportFilter, w io.Writer) error {
+	params := url.Values{"format": {format}, "source": {languagePair.Source}, "target": {languagePair.Target}}
+	if filter.Prefix != "" {
+		params.Set("prefix", filter.Prefix)
+	}
+	if filter.Tag != "" {
+		params.Set("tag", filter.Tag)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(c.endpoint, "/query")+"/export?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept-Language", language)
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("%s", strings.TrimSpace(string(message)))
+	}
+	if _, err := io.Copy(w, res.Body); err != nil {
+		return err
+	}
+	if res.Trailer.Get(exportStatusTrailer) != "ok" {
+		return fmt.Errorf("eksport został przerwany przez serwer, plik jest niekompletny")
+	}
+	return nil
+}
+
+// Returns the full address of a path served by the server, e.g. of an audio clip
+func ServerURL(path string) string {
+	return strings.TrimSuffix(endpoint, "/query") + path
+}
+
 func SetClientInstance(client GraphQLClientInterface) {
 	clientInstance = client
 }
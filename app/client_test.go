@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetLanguage_SupportedLanguage(t *testing.T) {
+	defer SetLanguage("pl")
+
+	err := SetLanguage("en")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "en", language)
+}
+
+func TestSetLanguage_UnsupportedLanguage(t *testing.T) {
+	err := SetLanguage("de")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "nieobsługiwany język")
+	assert.Equal(t, "pl", language)
+}
+
+func TestSetLanguagePair_SupportedPair(t *testing.T) {
+	defer SetLanguagePair("pl-en")
+
+	err := SetLanguagePair("DE-es")
+
+	assert.NoError(t, err)
+	assert.Equal(t, LanguagePair{Source: "DE", Target: "ES"}, languagePair)
+	assert.Equal(t, "de-es", LanguagePairName())
+}
+
+func TestSetLanguagePair_InvalidPair(t *testing.T) {
+	for _, pair := range []string{"de", "de-fr", "de-de", "pl-en-de"} {
+		err := SetLanguagePair(pair)
+
+		assert.Error(t, err)
+		assert.Equal(t, LanguagePair{Source: "PL", Target: "EN"}, languagePair)
+	}
+}
+
+func TestClientUpload_ShouldSendMultipartRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "pl", r.Header.Get("Accept-Language"))
+		assert.NoError(t, r.ParseMultipartForm(1<<20))
+
+		var operations struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		assert.NoError(t, json.Unmarshal([]byte(r.FormValue("operations")), &operations))
+		assert.Equal(t, "mutation", operations.Query)
+		assert.Contains(t, operations.Variables, "file")
+		assert.Nil(t, operations.Variables["file"])
+		assert.JSONEq(t, `{"0": ["variables.file"]}`, r.FormValue("map"))
+
+		file, header, err := r.FormFile("0")
+		assert.NoError(t, err)
+		content, _ := io.ReadAll(file)
+		assert.Equal(t, "words.csv", header.Filename)
+		assert.Equal(t, "polish,english\nrower,bike\n", string(content))
+
+		w.Write([]byte(`{"data": {"importFile": {"created": 1}}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{http: server.Client(), endpoint: server.URL}
+
+	var response ImportResponse
+	err := client.Upload("mutation", map[string]interface{}{}, "file", "words.csv", strings.NewReader("polish,english\nrower,bike\n"), &response)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, response.ImportFile.Created)
+}
+
+func TestClientExport_ShouldStreamFileAndCheckTrailer(t *testing.T) {
+	complete := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/export", r.URL.Path)
+		assert.Equal(t, "ndjson", r.URL.Query().Get("format"))
+		assert.Equal(t, "ro", r.URL.Query().Get("prefix"))
+		assert.Equal(t, languagePair.Source, r.URL.Query().Get("source"))
+		assert.Equal(t, languagePair.Target, r.URL.Query().Get("target"))
+		w.Header().Set("Trailer", exportStatusTrailer)
+		w.Write([]byte(`{"polish":"rower"}` + "\n"))
+		if complete {
+			w.Header().Set(exportStatusTrailer, "ok")
+		} else {
+			w.Header().Set(exportStatusTrailer, "error")
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{http: server.Client(), endpoint: server.URL + "/query"}
+
+	var out strings.Builder
+	err := client.Export("ndjson", ExportFilter{Prefix: "ro"}, &out)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"polish":"rower"}`+"\n", out.String())
+
+	complete = false
+	err = client.Export("ndjson", ExportFilter{Prefix: "ro"}, &strings.Builder{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niekompletny")
+}
+
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, io.ErrUnexpectedEOF
+}
+
+func TestResponseRecorder_FailedRequest_ShouldForgetPreviousError(t *testing.T) {
+	recorder := &responseRecorder{transport: failingTransport{}, body: []byte(`{"errors":[{"message":"stale"}]}`)}
+
+	_, err := recorder.RoundTrip(httptest.NewRequest(http.MethodPost, "http://localhost/query", nil))
+
+	assert.Error(t, err)
+	_, ok := recorder.lastError()
+	assert.False(t, ok)
+}
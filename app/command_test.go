@@ -1,6 +1,11 @@
 package main
 
 import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/machinebox/graphql"
@@ -17,13 +22,23 @@ func (m *MockGraphQLClient) Request(req *graphql.Request, response interface{})
 	return args.Error(0)
 }
 
+func (m *MockGraphQLClient) Upload(query string, variables map[string]interface{}, fileVariable string, filename string, file io.Reader, response interface{}) error {
+	args := m.Called(query, variables, fileVariable, filename, file, response)
+	return args.Error(0)
+}
+
+func (m *MockGraphQLClient) Export(format string, filter ExportFilter, w io.Writer) error {
+	args := m.Called(format, filter, w)
+	return args.Error(0)
+}
+
 func TestUpdateTranslationCommand_Execute_ValidInput(t *testing.T) {
 	mockClient := new(MockGraphQLClient)
 	SetClientInstance(mockClient)
 
 	cmd := UpdateTranslationCommand{request: graphql.NewRequest(
-		`mutation UpdateTranslation($polish: String!, $english: String!, $newEnglish: String!) 
-	{updateTranslation(polish: $polish, english: $english, newEnglish: $newEnglish)}`)}
+		`mutation RenameTranslation($text: String!, $translation: String!, $newTranslation: String!, $pair: LanguagePair) 
+	{renameTranslation(text: $text, translation: $translation, newTranslation: $newTranslation, pair: $pair){outcome word{text}}}`)}
 
 	input := []string{"kot", "cst", "cat"}
 
@@ -40,8 +55,8 @@ func TestUpdateTranslationCommand_Execute_InvalidInput(t *testing.T) {
 	SetClientInstance(mockClient)
 
 	cmd := UpdateTranslationCommand{request: graphql.NewRequest(
-		`mutation UpdateTranslation($polish: String!, $english: String!, $newEnglish: String!) 
-	{updateTranslation(polish: $polish, english: $english, newEnglish: $newEnglish)}`)}
+		`mutation RenameTranslation($text: String!, $translation: String!, $newTranslation: String!, $pair: LanguagePair) 
+	{renameTranslation(text: $text, translation: $translation, newTranslation: $newTranslation, pair: $pair){outcome word{text}}}`)}
 
 	input := []string{"kot", "cst"}
 
@@ -54,8 +69,8 @@ func TestUpdateWordCommand_Execute_ValidInput(t *testing.T) {
 	mockClient := new(MockGraphQLClient)
 	SetClientInstance(mockClient)
 
-	cmd := UpdateWordCommand{request: graphql.NewRequest(`mutation UpdateWord($polish: String!, $newPolish: String!) 
-	{updateWord(polish: $polish, newPolish: $newPolish)}`)}
+	cmd := UpdateWordCommand{request: graphql.NewRequest(`mutation RenameWord($text: String!, $newText: String!, $pair: LanguagePair) 
+	{renameWord(text: $text, newText: $newText, pair: $pair){outcome word{text}}}`)}
 
 	input := []string{"kst", "kot"}
 
@@ -71,8 +86,8 @@ func TestUpdateWordCommand_Execute_InvalidInput(t *testing.T) {
 	mockClient := new(MockGraphQLClient)
 	SetClientInstance(mockClient)
 
-	cmd := UpdateWordCommand{request: graphql.NewRequest(`mutation UpdateWord($polish: String!, $newPolish: String!) 
-	{updateWord(polish: $polish, newPolish: $newPolish)}`)}
+	cmd := UpdateWordCommand{request: graphql.NewRequest(`mutation RenameWord($text: String!, $newText: String!, $pair: LanguagePair) 
+	{renameWord(text: $text, newText: $newText, pair: $pair){outcome word{text}}}`)}
 
 	input := []string{"kst", "kot", "(kot zdanie)"}
 
@@ -86,8 +101,8 @@ func TestDeleteWordCommand_Execute_ValidInput(t *testing.T) {
 	SetClientInstance(mockClient)
 
 	cmd := DeleteWordCommand{request: graphql.NewRequest(
-		`mutation DeleteWord($polish: String!) 
-	{deleteWord(polish: $polish)}`)}
+		`mutation RemoveWord($text: String!, $pair: LanguagePair) 
+	{removeWord(text: $text, pair: $pair){outcome word{text}}}`)}
 
 	input := []string{"kot"}
 
@@ -104,8 +119,8 @@ func TestDeleteWordCommand_Execute_InvalidInput(t *testing.T) {
 	SetClientInstance(mockClient)
 
 	cmd := DeleteWordCommand{request: graphql.NewRequest(
-		`mutation DeleteWord($polish: String!) 
-	{deleteWord(polish: $polish)}`)}
+		`mutation RemoveWord($text: String!, $pair: LanguagePair) 
+	{removeWord(text: $text, pair: $pair){outcome word{text}}}`)}
 
 	input := []string{"kot", "cat"}
 	err := cmd.Execute(input)
@@ -119,8 +134,8 @@ func TestDeleteTranslationCommand_Execute_ValidInput(t *testing.T) {
 	SetClientInstance(mockClient)
 
 	cmd := DeleteTranslationCommand{request: graphql.NewRequest(`
-	mutation deleteTranslation($polish: String!, $english: String!) 
-	{deleteTranslation(polish: $polish, english: $english)}`)}
+	mutation removeTranslation($text: String!, $translation: String!, $pair: LanguagePair) 
+	{removeTranslation(text: $text, translation: $translation, pair: $pair){outcome word{text}}}`)}
 
 	input := []string{"kot", "cat"}
 
@@ -137,8 +152,8 @@ func TestDeleteTranslationCommand_Execute_InvalidInput(t *testing.T) {
 	SetClientInstance(mockClient)
 
 	cmd := DeleteTranslationCommand{request: graphql.NewRequest(`
-	mutation deleteTranslation($polish: String!, $english: String!) 
-	{deleteTranslation(polish: $polish, english: $english)}`)}
+	mutation removeTranslation($text: String!, $translation: String!, $pair: LanguagePair) 
+	{removeTranslation(text: $text, translation: $translation, pair: $pair){outcome word{text}}}`)}
 
 	input := []string{"kot", "cat", "(zdanie zdanie)"}
 
@@ -152,8 +167,8 @@ func TestDeleteSentenceCommand_Execute_ValidInput(t *testing.T) {
 	SetClientInstance(mockClient)
 
 	cmd := DeleteSentenceCommand{request: graphql.NewRequest(`
-	mutation deleteSentence($polish: String!, $english: String!, $sentence: String!) {
-	deleteSentence(polish: $polish, english: $english, sentence: $sentence)}`)}
+	mutation removeSentence($text: String!, $translation: String!, $sentence: String!, $pair: LanguagePair) {
+	removeSentence(text: $text, translation: $translation, sentence: $sentence, pair: $pair){outcome word{text}}}`)}
 
 	input := []string{"kot", "cat", "I hate my cat"}
 
@@ -170,8 +185,8 @@ func TestDeleteSentenceCommand_Execute_InvalidInput(t *testing.T) {
 	SetClientInstance(mockClient)
 
 	cmd := DeleteSentenceCommand{request: graphql.NewRequest(`
-	mutation deleteSentence($polish: String!, $english: String!, $sentence: String!) {
-	deleteSentence(polish: $polish, english: $english, sentence: $sentence)}`)}
+	mutation removeSentence($text: String!, $translation: String!, $sentence: String!, $pair: LanguagePair) {
+	removeSentence(text: $text, translation: $translation, sentence: $sentence, pair: $pair){outcome word{text}}}`)}
 
 	input := []string{"kot", "cat"}
 
@@ -185,8 +200,8 @@ func TestAddSentenceCommand_Execute_ValidInput(t *testing.T) {
 	SetClientInstance(mockClient)
 
 	cmd := AddSentenceCommand{request: graphql.NewRequest(`
-	mutation createSentence($polish: String!, $english: String!, $sentence: String!) {
-	createSentence(polish: $polish, english: $english, sentence: $sentence)}`)}
+	mutation addSentence($text: String!, $translation: String!, $sentence: String!, $pair: LanguagePair) {
+	addSentence(text: $text, translation: $translation, sentence: $sentence, pair: $pair){outcome word{text}}}`)}
 
 	input := []string{"kot", "cat", "I hate my cat"}
 
@@ -203,8 +218,8 @@ func TestAddSentenceCommand_Execute_InvalidInput(t *testing.T) {
 	SetClientInstance(mockClient)
 
 	cmd := AddSentenceCommand{request: graphql.NewRequest(`
-	mutation createSentence($polish: String!, $english: String!, $sentence: String!) {
-	createSentence(polish: $polish, english: $english, sentence: $sentence)}`)}
+	mutation addSentence($text: String!, $translation: String!, $sentence: String!, $pair: LanguagePair) {
+	addSentence(text: $text, translation: $translation, sentence: $sentence, pair: $pair){outcome word{text}}}`)}
 
 	input := []string{"kot", "cat", "I hate my cat", "I like my cat"}
 
@@ -218,8 +233,8 @@ func TestAddTranslationCommand_Execute_ValidInput(t *testing.T) {
 	SetClientInstance(mockClient)
 
 	cmd := AddTranslationCommand{request: graphql.NewRequest(`
-	mutation CreateTranslation($polish: String!, $translation: NewTranslation!) {
-	createTranslation(polish: $polish, translation: $translation)}`)}
+	mutation AddTranslation($text: String!, $translation: TranslationInput!, $pair: LanguagePair) {
+	addTranslation(text: $text, translation: $translation, pair: $pair){outcome word{text}}}`)}
 
 	input := []string{"kot", "cat", "I hate my cat", "I love my cat"}
 
@@ -236,8 +251,8 @@ func TestAddTranslationCommand_Execute_InvalidInput(t *testing.T) {
 	SetClientInstance(mockClient)
 
 	cmd := AddTranslationCommand{request: graphql.NewRequest(`
-	mutation CreateTranslation($polish: String!, $translation: NewTranslation!) {
-	createTranslation(polish: $polish, translation: $translation)}`)}
+	mutation AddTranslation($text: String!, $translation: TranslationInput!, $pair: LanguagePair) {
+	addTranslation(text: $text, translation: $translation, pair: $pair){outcome word{text}}}`)}
 
 	input := []string{"kot"}
 
@@ -251,8 +266,8 @@ func TestAddWordCommand_Execute_ValidInput(t *testing.T) {
 	SetClientInstance(mockClient)
 
 	cmd := AddWordCommand{request: graphql.NewRequest(`
-	mutation CreateTranslation($polish: String!, $translation: NewTranslation!) {
-	createTranslation(polish: $polish, translation: $translation)}`)}
+	mutation AddWord($text: String!, $translation: TranslationInput!, $pair: LanguagePair) {
+	addWord(text: $text, translation: $translation, pair: $pair){outcome word{text}}}`)}
 
 	input := []string{"kot", "cat", "I hate my cat", "I love my cat"}
 
@@ -269,8 +284,8 @@ func TestAddWordCommand_Execute_InvalidInput(t *testing.T) {
 	SetClientInstance(mockClient)
 
 	cmd := AddWordCommand{request: graphql.NewRequest(`
-	mutation CreateTranslation($polish: String!, $translation: NewTranslation!) {
-	createTranslation(polish: $polish, translation: $translation)}`)}
+	mutation AddWord($text: String!, $translation: TranslationInput!, $pair: LanguagePair) {
+	addWord(text: $text, translation: $translation, pair: $pair){outcome word{text}}}`)}
 
 	input := []string{"kot"}
 
@@ -278,3 +293,911 @@ func TestAddWordCommand_Execute_InvalidInput(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "niepoprawna liczba argumentów")
 }
+
+type MockReader struct {
+	mock.Mock
+}
+
+func (m *MockReader) Read() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func TestListWordsCommand_Execute_ValidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := ListWordsCommand{request: graphql.NewRequest(`query words($first: Int, $after: String, $prefix: String, $order: SortOrder, $pair: LanguagePair) 
+	{words(first: $first, after: $after, prefix: $prefix, order: $order, pair: $pair){edges{node{text translations{text}}} pageInfo{endCursor hasNextPage}}}`)}
+
+	input := []string{"ko", "DESC"}
+
+	mockClient.On("Request", mock.Anything, mock.Anything).Return(nil)
+
+	err := cmd.Execute(input)
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestListWordsCommand_Execute_NextPage_ShouldAskBeforeFetching(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+	mockReader := new(MockReader)
+	SetReaderInstance(mockReader)
+
+	cmd := ListWordsCommand{request: graphql.NewRequest(`query words($first: Int, $after: String, $prefix: String, $order: SortOrder, $pair: LanguagePair) 
+	{words(first: $first, after: $after, prefix: $prefix, order: $order, pair: $pair){edges{node{text translations{text}}} pageInfo{endCursor hasNextPage}}}`)}
+
+	mockClient.On("Request", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		response := args.Get(1).(*ListResponse)
+		response.Words.PageInfo.HasNextPage = true
+	})
+	mockReader.On("Read").Return("n")
+
+	err := cmd.Execute([]string{})
+
+	assert.NoError(t, err)
+	mockClient.AssertNumberOfCalls(t, "Request", 1)
+	mockReader.AssertExpectations(t)
+}
+
+func TestListWordsCommand_Execute_InvalidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := ListWordsCommand{request: graphql.NewRequest(`query words($first: Int, $after: String, $prefix: String, $order: SortOrder, $pair: LanguagePair) 
+	{words(first: $first, after: $after, prefix: $prefix, order: $order, pair: $pair){edges{node{text translations{text}}} pageInfo{endCursor hasNextPage}}}`)}
+
+	input := []string{"ko", "DESC", "kot"}
+
+	err := cmd.Execute(input)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawna liczba argumentów")
+}
+
+func TestSelectByEnglishCommand_Execute_ValidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := SelectByEnglishCommand{request: graphql.NewRequest(`query wordsByTranslation($text: String!, $pair: LanguagePair) 
+	{wordsByTranslation(text: $text, pair: $pair){text translations{text sentences{sentence}}}}`)}
+
+	input := []string{"cat"}
+
+	mockClient.On("Request", mock.Anything, mock.Anything).Return(nil)
+
+	err := cmd.Execute(input)
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestSelectByEnglishCommand_Execute_InvalidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := SelectByEnglishCommand{request: graphql.NewRequest(`query wordsByTranslation($text: String!, $pair: LanguagePair) 
+	{wordsByTranslation(text: $text, pair: $pair){text translations{text sentences{sentence}}}}`)}
+
+	input := []string{"cat", "dog"}
+
+	err := cmd.Execute(input)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawna liczba argumentów")
+}
+
+func TestSearchCommand_Execute_ValidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := SearchCommand{request: graphql.NewRequest(`query search($text: String!, $scope: SearchScope) 
+	{search(text: $text, scope: $scope){__typename ... on WordHit{polish rank snippet}}}`)}
+
+	input := []string{"my bike", "SENTENCES"}
+
+	mockClient.On("Request", mock.Anything, mock.Anything).Return(nil)
+
+	err := cmd.Execute(input)
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestSearchCommand_Execute_InvalidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := SearchCommand{request: graphql.NewRequest(`query search($text: String!, $scope: SearchScope) 
+	{search(text: $text, scope: $scope){__typename ... on WordHit{polish rank snippet}}}`)}
+
+	err := cmd.Execute([]string{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawna liczba argumentów")
+
+	err = cmd.Execute([]string{"bike", "EVERYWHERE"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawny zakres wyszukiwania")
+}
+
+func TestSelectWordCommand_Execute_WordDoesntExist_ShouldSelectChosenSuggestion(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+	mockReader := new(MockReader)
+	SetReaderInstance(mockReader)
+
+	cmd := SelectWordCommand{request: graphql.NewRequest(`query word($text: String!, $pair: LanguagePair) 
+	{word(text: $text, pair: $pair){text translations{text sentences{sentence}}}}`)}
+
+	suggestionsErr := GraphQLError{
+		Message:    "słowa rowr nie ma w słowniku",
+		Extensions: map[string]interface{}{"code": CodeWordNotFound, "word": "rowr", "suggestions": []interface{}{"rower", "rowek"}},
+	}
+
+	mockClient.On("Request", mock.Anything, mock.Anything).Return(suggestionsErr).Once()
+	mockClient.On("Request", mock.Anything, mock.Anything).Return(nil).Once()
+	mockReader.On("Read").Return("1")
+
+	err := cmd.Execute([]string{"rowr"})
+
+	assert.NoError(t, err)
+	mockClient.AssertNumberOfCalls(t, "Request", 2)
+	mockClient.AssertExpectations(t)
+	mockReader.AssertExpectations(t)
+}
+
+func TestSelectWordCommand_Execute_OtherErrorCode_ShouldReturnError(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := SelectWordCommand{request: graphql.NewRequest(`query word($text: String!, $pair: LanguagePair) 
+	{word(text: $text, pair: $pair){text translations{text sentences{sentence}}}}`)}
+
+	internalErr := GraphQLError{
+		Message:    "wewnętrzny błąd serwera (id: 0123456789abcdef)",
+		Extensions: map[string]interface{}{"code": CodeInternal, "errorId": "0123456789abcdef", "suggestions": []interface{}{"rower"}},
+	}
+
+	mockClient.On("Request", mock.Anything, mock.Anything).Return(internalErr).Once()
+
+	err := cmd.Execute([]string{"rowr"})
+
+	var gqlErr GraphQLError
+	assert.ErrorAs(t, err, &gqlErr)
+	assert.Equal(t, CodeInternal, gqlErr.Code())
+	assert.Equal(t, "0123456789abcdef", gqlErr.Field("errorId"))
+	mockClient.AssertExpectations(t)
+}
+
+type MockSubscriptionClient struct {
+	mock.Mock
+}
+
+func (m *MockSubscriptionClient) Subscribe(query string, variables map[string]interface{}, onEvent func(data json.RawMessage)) (func(), error) {
+	args := m.Called(query, variables, onEvent)
+	return args.Get(0).(func()), args.Error(1)
+}
+
+func TestWatchCommand_Execute_ValidInput(t *testing.T) {
+	mockSubscriptions := new(MockSubscriptionClient)
+	SetSubscriptionClientInstance(mockSubscriptions)
+	mockReader := new(MockReader)
+	SetReaderInstance(mockReader)
+
+	cmd := WatchCommand{query: `subscription wordChanged($polish: String) 
+	{wordChanged(polish: $polish){kind polish previousPolish word{polish translations{english sentences{sentence}}}}}`}
+
+	stopped := false
+	mockSubscriptions.On("Subscribe", mock.Anything, map[string]interface{}{"polish": "rower", "pair": languagePair}, mock.Anything).
+		Return(func() { stopped = true }, nil).
+		Run(func(args mock.Arguments) {
+			onEvent := args.Get(2).(func(data json.RawMessage))
+			onEvent(json.RawMessage(`{"wordChanged":{"kind":"DELETED","polish":"rower"}}`))
+		})
+	mockReader.On("Read").Return("")
+
+	err := cmd.Execute([]string{"rower"})
+
+	assert.NoError(t, err)
+	assert.True(t, stopped)
+	mockSubscriptions.AssertExpectations(t)
+}
+
+func TestWatchCommand_Execute_InvalidInput(t *testing.T) {
+	cmd := WatchCommand{query: `subscription wordChanged($polish: String) 
+	{wordChanged(polish: $polish){kind polish}}`}
+
+	err := cmd.Execute([]string{"rower", "kot"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawna liczba argumentów")
+}
+
+func TestImportCommand_Execute_ValidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	path := filepath.Join(t.TempDir(), "words.tsv")
+	os.WriteFile(path, []byte("polish\tenglish\nrower\tbike\n"), 0644)
+
+	cmd := ImportCommand{query: `mutation importFile($file: Upload!, $options: FileImportOptions) 
+	{importFile(file: $file, options: $options){created merged overwritten skipped rejected rows{line polish english status errorCode}}}`}
+
+	expectedVariables := map[string]interface{}{"options": map[string]interface{}{"delimiter": "\t", "conflict": "OVERWRITE"}, "pair": languagePair}
+	mockClient.On("Upload", mock.Anything, expectedVariables, "file", "words.tsv", mock.Anything, mock.Anything).Return(nil)
+
+	err := cmd.Execute([]string{path, "OVERWRITE"})
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestImportCommand_Execute_InvalidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := ImportCommand{query: `mutation importFile($file: Upload!, $options: FileImportOptions) 
+	{importFile(file: $file, options: $options){created merged overwritten skipped rejected rows{line polish english status errorCode}}}`}
+
+	err := cmd.Execute([]string{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawna liczba argumentów")
+
+	err = cmd.Execute([]string{filepath.Join(t.TempDir(), "missing.csv")})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "nie można otworzyć pliku")
+	mockClient.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestExportCommand_Execute_ValidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := ExportCommand{}
+	dir := t.TempDir()
+
+	mockClient.On("Export", "csv", ExportFilter{}, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(2).(io.Writer).Write([]byte("polish,english,sentence\nrower,bike,\n"))
+	})
+	mockClient.On("Export", "ndjson", ExportFilter{}, mock.Anything).Return(nil)
+
+	err := cmd.Execute([]string{filepath.Join(dir, "words.csv")})
+	assert.NoError(t, err)
+	content, _ := os.ReadFile(filepath.Join(dir, "words.csv"))
+	assert.Equal(t, "polish,english,sentence\nrower,bike,\n", string(content))
+
+	err = cmd.Execute([]string{filepath.Join(dir, "backup"), "--format", "ndjson"})
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestExportCommand_Execute_InvalidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := ExportCommand{}
+
+	err := cmd.Execute([]string{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawna liczba argumentów")
+
+	err = cmd.Execute([]string{"words.txt", "json"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawna liczba argumentów")
+
+	err = cmd.Execute([]string{filepath.Join(t.TempDir(), "words.xml")})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "nieobsługiwany format eksportu")
+	mockClient.AssertNotCalled(t, "Export", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestExportAnkiCommand_Execute_ValidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := ExportAnkiCommand{}
+	dir := t.TempDir()
+
+	mockClient.On("Export", "apkg", ExportFilter{Prefix: "ro"}, mock.Anything).Return(nil)
+	mockClient.On("Export", "anki", ExportFilter{}, mock.Anything).Return(nil)
+	mockClient.On("Export", "anki", ExportFilter{Prefix: "bi", Tag: "travel"}, mock.Anything).Return(nil)
+
+	err := cmd.Execute([]string{filepath.Join(dir, "deck.apkg"), "--prefix", "ro"})
+	assert.NoError(t, err)
+
+	err = cmd.Execute([]string{filepath.Join(dir, "travel.txt"), "--tag", "travel", "--prefix", "bi"})
+	assert.NoError(t, err)
+
+	err = cmd.Execute([]string{filepath.Join(dir, "deck.txt")})
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestExportAnkiCommand_Execute_InvalidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := ExportAnkiCommand{}
+
+	err := cmd.Execute([]string{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawna liczba argumentów")
+
+	err = cmd.Execute([]string{"deck.apkg", "ro"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawna liczba argumentów")
+
+	err = cmd.Execute([]string{"deck.apkg", "--format", "apkg"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "nieznana opcja --format")
+	mockClient.AssertNotCalled(t, "Export", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestStudyCommand_Execute_ValidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+	mockReader := new(MockReader)
+	SetReaderInstance(mockReader)
+
+	cmd := StudyCommand{
+		dueCards: graphql.NewRequest(`query dueCards($limit: Int, $pair: LanguagePair) 
+		{dueCards(limit: $limit, pair: $pair){translationId text translation{text sentences{sentence}} interval}}`),
+		gradeCard: graphql.NewRequest(`mutation gradeCard($translationId: ID!, $grade: Int!) 
+		{gradeCard(translationId: $translationId, grade: $grade){translationId interval due}}`)}
+
+	mockClient.On("Request", cmd.dueCards, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		response := args.Get(1).(*DueCardsResponse)
+		response.DueCards = make([]CardResponse, 2)
+		response.DueCards[0].TranslationID = "7"
+		response.DueCards[0].Text = "rower"
+		response.DueCards[0].Translation.Text = "bike"
+		response.DueCards[1].TranslationID = "8"
+		response.DueCards[1].Text = "kot"
+	})
+	mockClient.On("Request", cmd.gradeCard, mock.Anything).Return(nil)
+	mockReader.On("Read").Return("bike").Once()
+	mockReader.On("Read").Return("9").Once()
+	mockReader.On("Read").Return("4").Once()
+	mockReader.On("Read").Return("q").Once()
+
+	err := cmd.Execute([]string{"2"})
+
+	assert.NoError(t, err)
+	mockClient.AssertNumberOfCalls(t, "Request", 2)
+	mockReader.AssertExpectations(t)
+}
+
+func TestStudyCommand_Execute_InvalidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := StudyCommand{
+		dueCards: graphql.NewRequest(`query dueCards($limit: Int, $pair: LanguagePair) 
+		{dueCards(limit: $limit, pair: $pair){translationId text translation{text sentences{sentence}} interval}}`),
+		gradeCard: graphql.NewRequest(`mutation gradeCard($translationId: ID!, $grade: Int!) 
+		{gradeCard(translationId: $translationId, grade: $grade){translationId interval due}}`)}
+
+	err := cmd.Execute([]string{"10", "20"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawna liczba argumentów")
+
+	err = cmd.Execute([]string{"dużo"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "musi być liczbą")
+	mockClient.AssertNotCalled(t, "Request", mock.Anything, mock.Anything)
+}
+
+func TestQuizCommand_Execute_ValidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+	mockReader := new(MockReader)
+	SetReaderInstance(mockReader)
+
+	cmd := QuizCommand{
+		generateQuiz: graphql.NewRequest(`query generateQuiz($size: Int, $direction: QuizDirection, $kinds: [QuestionKind!], $pair: LanguagePair) 
+		{generateQuiz(size: $size, direction: $direction, kinds: $kinds, pair: $pair){id kind prompt choices}}`),
+		submitQuiz: graphql.NewRequest(`mutation submitQuiz($answers: [QuizAnswer!]!) 
+		{submitQuiz(answers: $answers){score total answers{questionId answer correct acceptedAnswers}}}`)}
+
+	mockClient.On("Request", cmd.generateQuiz, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*QuizResponse).GenerateQuiz = []QuizQuestionResponse{
+			{ID: "q1", Kind: "MULTIPLE_CHOICE", Prompt: "rower", Choices: []string{"cat", "bike"}},
+			{ID: "q2", Kind: "TYPING", Prompt: "kot", Choices: []string{}},
+		}
+	})
+	mockClient.On("Request", cmd.submitQuiz, mock.Anything).Return(nil)
+	mockReader.On("Read").Return("2").Once()
+	mockReader.On("Read").Return("cat").Once()
+
+	err := cmd.Execute([]string{"2", "EN_PL", "TYPING", "MULTIPLE_CHOICE"})
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+	mockReader.AssertExpectations(t)
+}
+
+func TestQuizCommand_Execute_InvalidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := QuizCommand{
+		generateQuiz: graphql.NewRequest(`query generateQuiz($size: Int, $direction: QuizDirection, $kinds: [QuestionKind!], $pair: LanguagePair) 
+		{generateQuiz(size: $size, direction: $direction, kinds: $kinds, pair: $pair){id kind prompt choices}}`),
+		submitQuiz: graphql.NewRequest(`mutation submitQuiz($answers: [QuizAnswer!]!) 
+		{submitQuiz(answers: $answers){score total answers{questionId answer correct acceptedAnswers}}}`)}
+
+	err := cmd.Execute([]string{"FLASHCARDS"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawny argument")
+
+	err = cmd.Execute([]string{"TYPING", "10"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawny argument")
+	mockClient.AssertNotCalled(t, "Request", mock.Anything, mock.Anything)
+}
+
+func TestGrammarCommand_Execute_ValidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := GrammarCommand{request: graphql.NewRequest(`mutation SetGrammar($polish: String!, $grammar: GrammarInput!) 
+	{setGrammar(polish: $polish, grammar: $grammar){outcome word{text}}}`)}
+
+	mockClient.On("Request", mock.Anything, mock.Anything).Return(nil)
+
+	err := cmd.Execute([]string{"robić", "VERB", "IMPERFECTIVE", "zrobić"})
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGrammarCommand_Execute_InvalidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := GrammarCommand{request: graphql.NewRequest(`mutation SetGrammar($polish: String!, $grammar: GrammarInput!) 
+	{setGrammar(polish: $polish, grammar: $grammar){outcome word{text}}}`)}
+
+	err := cmd.Execute([]string{"robić"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawna liczba argumentów")
+
+	err = cmd.Execute([]string{"robić", "FEMININE"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawna część mowy")
+
+	err = cmd.Execute([]string{"robić", "VERB", "zrobić", "uczynić"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawne argumenty")
+
+	mockClient.AssertNotCalled(t, "Request", mock.Anything, mock.Anything)
+}
+
+func TestCountabilityCommand_Execute_ValidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := CountabilityCommand{request: graphql.NewRequest(`mutation SetCountability($polish: String!, $english: String!, $countability: Countability) 
+	{setCountability(polish: $polish, english: $english, countability: $countability){outcome word{text}}}`)}
+
+	mockClient.On("Request", mock.Anything, mock.Anything).Return(nil)
+
+	err := cmd.Execute([]string{"rada", "advice", "UNCOUNTABLE"})
+	assert.NoError(t, err)
+
+	err = cmd.Execute([]string{"rada", "advice", "NONE"})
+	assert.NoError(t, err)
+
+	mockClient.AssertNumberOfCalls(t, "Request", 2)
+}
+
+func TestCountabilityCommand_Execute_InvalidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := CountabilityCommand{request: graphql.NewRequest(`mutation SetCountability($polish: String!, $english: String!, $countability: Countability) 
+	{setCountability(polish: $polish, english: $english, countability: $countability){outcome word{text}}}`)}
+
+	err := cmd.Execute([]string{"rada", "advice"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawna liczba argumentów")
+
+	err = cmd.Execute([]string{"rada", "advice", "NOUN"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawna policzalność")
+}
+
+func TestListWordsCommand_Execute_GrammarFilter(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := ListWordsCommand{request: graphql.NewRequest(`query words($first: Int, $after: String, $prefix: String, $order: SortOrder, $grammar: GrammarFilter, $pair: LanguagePair) 
+	{words(first: $first, after: $after, prefix: $prefix, order: $order, grammar: $grammar, pair: $pair){edges{node{text translations{text}}} pageInfo{endCursor hasNextPage}}}`)}
+
+	mockClient.On("Request", mock.Anything, mock.Anything).Return(nil)
+
+	err := cmd.Execute([]string{"ko", "NOUN", "DESC", "FEMININE"})
+	assert.NoError(t, err)
+
+	err = cmd.Execute([]string{"NOUN", "VERB"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "podany więcej niż raz")
+
+	mockClient.AssertNumberOfCalls(t, "Request", 1)
+}
+
+func TestAddInflectionCommand_Execute_ValidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := AddInflectionCommand{request: graphql.NewRequest(`mutation AddInflection($polish: String!, $form: String!, $tags: [String!]!) 
+	{addInflection(polish: $polish, form: $form, tags: $tags){outcome word{text}}}`)}
+
+	mockClient.On("Request", mock.Anything, mock.Anything).Return(nil)
+
+	err := cmd.Execute([]string{"rower", "rowerem", "instrumental", "singular"})
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestAddInflectionCommand_Execute_InvalidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := AddInflectionCommand{request: graphql.NewRequest(`mutation AddInflection($polish: String!, $form: String!, $tags: [String!]!) 
+	{addInflection(polish: $polish, form: $form, tags: $tags){outcome word{text}}}`)}
+
+	err := cmd.Execute([]string{"rower"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawna liczba argumentów")
+}
+
+func TestDeleteInflectionCommand_Execute_ValidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := DeleteInflectionCommand{request: graphql.NewRequest(`mutation DeleteInflection($polish: String!, $form: String!, $tags: [String!]) 
+	{deleteInflection(polish: $polish, form: $form, tags: $tags){outcome word{text}}}`)}
+
+	mockClient.On("Request", mock.Anything, mock.Anything).Return(nil)
+
+	err := cmd.Execute([]string{"kot", "kota"})
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestDeleteInflectionCommand_Execute_InvalidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := DeleteInflectionCommand{request: graphql.NewRequest(`mutation DeleteInflection($polish: String!, $form: String!, $tags: [String!]) 
+	{deleteInflection(polish: $polish, form: $form, tags: $tags){outcome word{text}}}`)}
+
+	err := cmd.Execute([]string{"kot"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawna liczba argumentów")
+}
+
+func TestImportInflectionsCommand_Execute_ValidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	path := filepath.Join(t.TempDir(), "forms.tsv")
+	os.WriteFile(path, []byte("lemma\tform\ttags\nrower\trowerem\tinstrumental singular\nkot\tkotów\tgenitive\tplural\n"), 0644)
+
+	cmd := ImportInflectionsCommand{request: graphql.NewRequest(`mutation ImportInflections($entries: [NewInflection!]!, $mode: ImportMode) 
+	{importInflections(entries: $entries, mode: $mode){index lemma form status errorCode}}`)}
+
+	mockClient.On("Request", mock.Anything, mock.Anything).Return(nil)
+
+	err := cmd.Execute([]string{path, "BEST_EFFORT"})
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestImportInflectionsCommand_Execute_InvalidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := ImportInflectionsCommand{request: graphql.NewRequest(`mutation ImportInflections($entries: [NewInflection!]!, $mode: ImportMode) 
+	{importInflections(entries: $entries, mode: $mode){index lemma form status errorCode}}`)}
+
+	err := cmd.Execute([]string{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawna liczba argumentów")
+
+	err = cmd.Execute([]string{filepath.Join(t.TempDir(), "missing.csv")})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "nie można otworzyć pliku")
+
+	path := filepath.Join(t.TempDir(), "forms.csv")
+	os.WriteFile(path, []byte("rower\n"), 0644)
+	err = cmd.Execute([]string{path})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "wiersz 1")
+
+	mockClient.AssertNotCalled(t, "Request", mock.Anything, mock.Anything)
+}
+
+func TestReadInflections_ShouldSkipHeaderAndSplitTags(t *testing.T) {
+	entries, lines, err := readInflections(strings.NewReader("lemma,form,tags\nrower,rowerem,instrumental singular\nkot,kotów,genitive,plural\n"), ',')
+
+	assert.NoError(t, err)
+	assert.Equal(t, []NewInflection{
+		{Lemma: "rower", Form: "rowerem", Tags: []string{"instrumental", "singular"}},
+		{Lemma: "kot", Form: "kotów", Tags: []string{"genitive", "plural"}},
+	}, entries)
+	assert.Equal(t, []int{2, 3}, lines)
+}
+
+func TestPairCommand_Execute_ShouldSwitchPairOfLaterRequests(t *testing.T) {
+	defer SetLanguagePair("pl-en")
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := SelectWordCommand{request: graphql.NewRequest(`query word($text: String!, $pair: LanguagePair) 
+	{word(text: $text, pair: $pair){text translations{text sentences{sentence}}}}`)}
+
+	mockClient.On("Request", mock.Anything, mock.Anything).Return(nil)
+
+	assert.NoError(t, PairCommand{}.Execute([]string{"de-en"}))
+	assert.NoError(t, cmd.Execute([]string{"Katze"}))
+
+	body, err := json.Marshal(map[string]interface{}{"pair": languagePair})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"pair": {"source": "DE", "target": "EN"}}`, string(body))
+	mockClient.AssertExpectations(t)
+}
+
+func TestPairCommand_Execute_InvalidInput(t *testing.T) {
+	err := PairCommand{}.Execute([]string{"de", "en"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawna liczba argumentów")
+
+	err = PairCommand{}.Execute([]string{"en-en"})
+	assert.Error(t, err)
+	assert.Equal(t, "pl-en", LanguagePairName())
+}
+
+func TestLinkCommand_Execute_ValidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := LinkCommand{request: graphql.NewRequest(`mutation AddRelation($kind: RelationKind!, $from: RelationEnd!, $to: RelationEnd!, $pair: LanguagePair) 
+	{addRelation(kind: $kind, from: $from, to: $to, pair: $pair){outcome word{text}}}`)}
+
+	mockClient.On("Request", mock.Anything, mock.Anything).Return(nil)
+
+	assert.NoError(t, cmd.Execute([]string{"synonym", "rower", "bicykl"}))
+	assert.NoError(t, cmd.Execute([]string{"SYNONYM", "rower", "bicycle", "rower", "bike"}))
+
+	mockClient.AssertNumberOfCalls(t, "Request", 2)
+}
+
+func TestLinkCommand_Execute_InvalidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := LinkCommand{request: graphql.NewRequest(`mutation AddRelation($kind: RelationKind!, $from: RelationEnd!, $to: RelationEnd!, $pair: LanguagePair) 
+	{addRelation(kind: $kind, from: $from, to: $to, pair: $pair){outcome word{text}}}`)}
+
+	err := cmd.Execute([]string{"SYNONYM", "rower", "bicycle", "bicykl"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawna liczba argumentów")
+
+	err = cmd.Execute([]string{"COUSIN", "rower", "bicykl"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawny rodzaj relacji")
+
+	mockClient.AssertNotCalled(t, "Request", mock.Anything, mock.Anything)
+}
+
+func TestUnlinkCommand_Execute_ValidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := UnlinkCommand{request: graphql.NewRequest(`mutation RemoveRelation($kind: RelationKind!, $from: RelationEnd!, $to: RelationEnd!, $pair: LanguagePair) 
+	{removeRelation(kind: $kind, from: $from, to: $to, pair: $pair){outcome word{text}}}`)}
+
+	mockClient.On("Request", mock.Anything, mock.Anything).Return(nil)
+
+	err := cmd.Execute([]string{"DERIVED_FROM", "rowerzysta", "rower"})
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestRelatedEntries_ShouldNameRelationsFromTheWordSide(t *testing.T) {
+	bike := "bike"
+	related := []RelationResponse{
+		{Kind: "SYNONYM", Word: "bicykl"},
+		{Kind: "DERIVED_FROM", Word: "rowerzysta", Incoming: true},
+		{Kind: "SYNONYM", Word: "rower", Translation: &bike},
+	}
+
+	assert.Equal(t, "bicykl (synonim), rowerzysta (wyraz pochodny), rower - bike (synonim)", relatedEntries(related))
+}
+
+func TestTagCommand_Execute_ValidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := TagCommand{request: graphql.NewRequest(`mutation TagWord($text: String!, $tags: [String!]!, $pair: LanguagePair) 
+	{tagWord(text: $text, tags: $tags, pair: $pair){outcome word{text}}}`)}
+
+	mockClient.On("Request", mock.Anything, mock.Anything).Return(nil)
+
+	err := cmd.Execute([]string{"garnek", "kuchnia", "lekcja 5"})
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestTagCommand_Execute_InvalidInput(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := TagCommand{request: graphql.NewRequest(`mutation TagWord($text: String!, $tags: [String!]!, $pair: LanguagePair) 
+	{tagWord(text: $text, tags: $tags, pair: $pair){outcome word{text}}}`)}
+
+	err := cmd.Execute([]string{"garnek"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawna liczba argumentów")
+	mockClient.AssertNotCalled(t, "Request", mock.Anything, mock.Anything)
+}
+
+func TestTagsCommand_Execute_ShouldPickRequestBySubcommand(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := NewCommandFactory().commands["TAGS"].(*TagsCommand)
+
+	mockClient.On("Request", cmd.tags, mock.Anything).Return(nil).Once()
+	mockClient.On("Request", cmd.renameTag, mock.Anything).Return(nil).Once()
+	mockClient.On("Request", cmd.deleteTag, mock.Anything).Return(nil).Once()
+
+	assert.NoError(t, cmd.Execute([]string{}))
+	assert.NoError(t, cmd.Execute([]string{"rename", "kuchnia", "dom"}))
+	assert.NoError(t, cmd.Execute([]string{"DELETE", "dom"}))
+
+	err := cmd.Execute([]string{"DELETE", "dom", "kuchnia"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawne argumenty")
+	mockClient.AssertExpectations(t)
+}
+
+func TestCollectionCommand_Execute_ShouldPickRequestBySubcommand(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := NewCommandFactory().commands["COLLECTION"].(*CollectionCommand)
+
+	mockClient.On("Request", cmd.collections, mock.Anything).Return(nil).Once()
+	mockClient.On("Request", cmd.createCollection, mock.Anything).Return(nil).Once()
+	mockClient.On("Request", cmd.addItem, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		response := args.Get(1).(*CollectionResponse)
+		response.Collection.Name = "Lekcja 5"
+		response.Collection.Items = []CollectionItemResponse{{Position: 1, Text: "garnek", Translation: "pot"}}
+	}).Once()
+	mockClient.On("Request", cmd.removeItem, mock.Anything).Return(nil).Once()
+
+	assert.NoError(t, cmd.Execute([]string{}))
+	assert.NoError(t, cmd.Execute([]string{"CREATE", "Lekcja 5"}))
+	assert.NoError(t, cmd.Execute([]string{"add", "Lekcja 5", "garnek", "pot"}))
+	assert.NoError(t, cmd.Execute([]string{"REMOVE", "Lekcja 5", "garnek", "pot"}))
+
+	err := cmd.Execute([]string{"ADD", "Lekcja 5", "garnek"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawne argumenty")
+	mockClient.AssertExpectations(t)
+}
+
+func TestParseTagFilter_ShouldTakeOutTagArgument(t *testing.T) {
+	rest, tag, err := parseTagFilter([]string{"ko", "tag:kuchnia", "DESC"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ko", "DESC"}, rest)
+	assert.Equal(t, "kuchnia", *tag)
+
+	_, _, err = parseTagFilter([]string{"tag:kuchnia", "tag:dom"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "podany więcej niż raz")
+
+	_, _, err = parseTagFilter([]string{"tag:"})
+	assert.Error(t, err)
+}
+
+func TestPronunciationEntry_ShouldShowTranscriptionAndStress(t *testing.T) {
+	ipa, stress := "ˈrɔ.vɛr", 1
+
+	assert.Equal(t, "/ˈrɔ.vɛr/ (akcent na 1. sylabie)", pronunciationEntry(PronunciationResponse{IPA: &ipa, Stress: &stress}))
+	assert.Equal(t, "/ˈrɔ.vɛr/", pronunciationEntry(PronunciationResponse{IPA: &ipa}))
+	assert.Equal(t, "", pronunciationEntry(PronunciationResponse{}))
+	assert.Equal(t, "http://localhost:8080/audio/3f2a.mp3", ServerURL("/audio/3f2a.mp3"))
+}
+
+func TestParseDomainFilter_ShouldAddDomainToGrammarFilter(t *testing.T) {
+	rest, grammar, err := parseDomainFilter([]string{"za", "domain:medical"}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"za"}, rest)
+	assert.Equal(t, map[string]string{"domain": "medical"}, grammar)
+
+	_, grammar, err = parseDomainFilter([]string{"za"}, map[string]string{"label": "FORMAL"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"label": "FORMAL"}, grammar)
+
+	_, _, err = parseDomainFilter([]string{"domain:"}, nil)
+	assert.Error(t, err)
+}
+
+func TestTranslationQualifiers_ShouldListCountabilityLabelsAndDomain(t *testing.T) {
+	countability, domain := "UNCOUNTABLE", "medical"
+
+	assert.Equal(t, "niepoliczalny, formalny, dziedzina: medical", translationQualifiers(&countability, []string{"FORMAL"}, &domain))
+	assert.Equal(t, "", translationQualifiers(nil, []string{}, nil))
+}
+
+func TestSentenceEntry_ShouldShowOriginalAndAttribution(t *testing.T) {
+	original, license := "Lubię mój rower", "CC BY 2.0 FR"
+
+	assert.Equal(t, "I like my bike - Lubię mój rower (CC BY 2.0 FR)", sentenceEntry(SentenceResponse{Sentence: "I like my bike", Original: &original, License: &license}))
+	assert.Equal(t, "I like my bike", sentenceEntry(SentenceResponse{Sentence: "I like my bike"}))
+}
+
+func TestAddSentenceCommand_Execute_InvalidPair(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := AddSentenceCommand{request: graphql.NewRequest(`
+	mutation addSentence($text: String!, $translation: String!, $sentence: String!, $original: String, $attribution: String, $license: String, $pair: LanguagePair) {
+	addSentence(text: $text, translation: $translation, sentence: $sentence, original: $original, attribution: $attribution, license: $license, pair: $pair){outcome word{text}}}`)}
+
+	err := cmd.Execute([]string{"rower", "bike", "| Lubię mój rower"})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawne zdanie")
+	mockClient.AssertNotCalled(t, "Request", mock.Anything, mock.Anything)
+}
+
+func TestMoveItem(t *testing.T) {
+	items := []string{"zipper", "lock", "castle"}
+
+	assert.Equal(t, []string{"castle", "zipper", "lock"}, moveItem(items, 2, 1))
+	assert.Equal(t, []string{"lock", "castle", "zipper"}, moveItem(items, 0, 5))
+	assert.Equal(t, []string{"zipper", "lock", "castle"}, items)
+}
+
+func TestMoveCommand_Execute_ShouldReadWordAndReorderTranslations(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := NewCommandFactory().commands["MOVE"].(*MoveCommand)
+
+	mockClient.On("Request", cmd.word, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		response := args.Get(1).(*SelectResponse)
+		response.Word.Text = "zamek"
+		response.Word.Translations = []TranslationResponse{{Text: "zipper"}, {Text: "lock"}, {Text: "castle"}}
+	})
+	mockClient.On("Request", cmd.translations, mock.Anything).Return(nil)
+
+	err := cmd.Execute([]string{"zamek", "castle", "1"})
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestMoveCommand_Execute_InvalidPosition(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	cmd := NewCommandFactory().commands["MOVE"].(*MoveCommand)
+
+	err := cmd.Execute([]string{"zamek", "castle", "0"})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "niepoprawna pozycja")
+	mockClient.AssertNotCalled(t, "Request", mock.Anything, mock.Anything)
+}
@@ -1,7 +1,16 @@
 package main
 
 import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/machinebox/graphql"
 )
@@ -38,6 +47,40 @@ type SelectWordCommand struct {
 	request *graphql.Request
 }
 
+type SelectByEnglishCommand struct {
+	request *graphql.Request
+}
+
+type SearchCommand struct {
+	request *graphql.Request
+}
+
+type WatchCommand struct {
+	query string
+}
+
+type ImportCommand struct {
+	query string
+}
+
+type StudyCommand struct {
+	dueCards  *graphql.Request
+	gradeCard *graphql.Request
+}
+
+type QuizCommand struct {
+	generateQuiz *graphql.Request
+	submitQuiz   *graphql.Request
+}
+
+type ExportCommand struct{}
+
+type ExportAnkiCommand struct{}
+
+type ListWordsCommand struct {
+	request *graphql.Request
+}
+
 type UpdateWordCommand struct {
 	request *graphql.Request
 }
@@ -50,53 +93,204 @@ type UpdateSentenceCommand struct {
 	request *graphql.Request
 }
 
+type GrammarCommand struct {
+	request *graphql.Request
+}
+
+type CountabilityCommand struct {
+	request *graphql.Request
+}
+
+type AddInflectionCommand struct {
+	request *graphql.Request
+}
+
+type DeleteInflectionCommand struct {
+	request *graphql.Request
+}
+
+type ImportInflectionsCommand struct {
+	request *graphql.Request
+}
+
+type PairCommand struct{}
+
+type LinkCommand struct {
+	request *graphql.Request
+}
+
+type UnlinkCommand struct {
+	request *graphql.Request
+}
+
+type TagCommand struct {
+	request *graphql.Request
+}
+
+type UntagCommand struct {
+	request *graphql.Request
+}
+
+type TagsCommand struct {
+	tags      *graphql.Request
+	renameTag *graphql.Request
+	deleteTag *graphql.Request
+}
+
+type CollectionCommand struct {
+	collections      *graphql.Request
+	collection       *graphql.Request
+	createCollection *graphql.Request
+	renameCollection *graphql.Request
+	deleteCollection *graphql.Request
+	addItem          *graphql.Request
+	removeItem       *graphql.Request
+}
+
+type MoveCommand struct {
+	word         *graphql.Request
+	translations *graphql.Request
+	sentences    *graphql.Request
+}
+
 type CommandFactory struct {
 	commands map[string]ICommand
 }
 
+const listPageSize = 10
+
 type NewTranslation struct {
-	English   string   `json:"english"`
-	Sentences []string `json:"sentences"`
+	Text          string          `json:"text"`
+	Sentences     []string        `json:"sentences"`
+	SentencePairs []SentenceInput `json:"sentencePairs,omitempty"`
+}
+
+// Example sentence with its original in the language of the word and attribution, nil parts weren't given
+type SentenceInput struct {
+	Sentence    string  `json:"sentence"`
+	Original    *string `json:"original"`
+	Attribution *string `json:"attribution"`
+	License     *string `json:"license"`
 }
 
 func NewCommandFactory() *CommandFactory {
 	return &CommandFactory{
 		commands: map[string]ICommand{
 			"ADD_TRANSLATION": &AddTranslationCommand{request: graphql.NewRequest(`
-				mutation CreateTranslation($polish: String!, $translation: NewTranslation!) {
-			createTranslation(polish: $polish, translation: $translation)}`)},
+				mutation AddTranslation($text: String!, $translation: TranslationInput!, $pair: LanguagePair) {
+			addTranslation(text: $text, translation: $translation, pair: $pair){outcome word{text}}}`)},
 
 			"ADD": &AddWordCommand{request: graphql.NewRequest(`
-			mutation CreateWord($polish: String!, $translation: NewTranslation!) {
-		createWord(polish: $polish, translation: $translation)}`)},
+			mutation AddWord($text: String!, $translation: TranslationInput!, $pair: LanguagePair) {
+		addWord(text: $text, translation: $translation, pair: $pair){outcome word{text}}}`)},
 
 			"DELETE_TRANSLATION": &DeleteTranslationCommand{request: graphql.NewRequest(`
-				mutation deleteTranslation($polish: String!, $english: String!) 
-				{deleteTranslation(polish: $polish, english: $english)}`)},
+				mutation removeTranslation($text: String!, $translation: String!, $pair: LanguagePair) 
+				{removeTranslation(text: $text, translation: $translation, pair: $pair){outcome word{text}}}`)},
 
 			"ADD_SENTENCE": &AddSentenceCommand{request: graphql.NewRequest(`
-			mutation createSentence($polish: String!, $english: String!, $sentence: String!) {
-			createSentence(polish: $polish, english: $english, sentence: $sentence)}`)},
+			mutation addSentence($text: String!, $translation: String!, $sentence: String!, $original: String, $attribution: String, $license: String, $pair: LanguagePair) {
+			addSentence(text: $text, translation: $translation, sentence: $sentence, original: $original, attribution: $attribution, license: $license, pair: $pair){outcome word{text}}}`)},
 
 			"DELETE_SENTENCE": &DeleteSentenceCommand{request: graphql.NewRequest(`
-			mutation deleteSentence($polish: String!, $english: String!, $sentence: String!) {
-			deleteSentence(polish: $polish, english: $english, sentence: $sentence)}`)},
+			mutation removeSentence($text: String!, $translation: String!, $sentence: String!, $pair: LanguagePair) {
+			removeSentence(text: $text, translation: $translation, sentence: $sentence, pair: $pair){outcome word{text}}}`)},
 
 			"DELETE": &DeleteWordCommand{request: graphql.NewRequest(
-				`mutation DeleteWord($polish: String!) 
-			{deleteWord(polish: $polish)}`)},
+				`mutation RemoveWord($text: String!, $pair: LanguagePair) 
+			{removeWord(text: $text, pair: $pair){outcome word{text}}}`)},
+
+			"SELECT": &SelectWordCommand{request: graphql.NewRequest(`query word($text: String!, $pair: LanguagePair) 
+			{word(text: $text, pair: $pair){text partOfSpeech gender aspect aspectPartner tags pronunciation{ipa stress audioUrl} translations{text countability labels domain note pronunciation{ipa stress audioUrl} sentences{sentence original attribution license} related{kind word translation incoming}} inflections{form tags} matchedForms{form tags} related{kind word translation incoming}}}`)},
+
+			"SELECT_EN": &SelectByEnglishCommand{request: graphql.NewRequest(`query wordsByTranslation($text: String!, $pair: LanguagePair) 
+			{wordsByTranslation(text: $text, pair: $pair){text partOfSpeech gender aspect aspectPartner tags pronunciation{ipa stress audioUrl} translations{text countability labels domain note pronunciation{ipa stress audioUrl} sentences{sentence original attribution license} related{kind word translation incoming}} inflections{form tags} matchedForms{form tags} related{kind word translation incoming}}}`)},
+
+			"SEARCH": &SearchCommand{request: graphql.NewRequest(`query search($text: String!, $scope: SearchScope, $grammar: GrammarFilter, $tag: String, $pair: LanguagePair) 
+			{search(text: $text, scope: $scope, grammar: $grammar, tag: $tag, pair: $pair){__typename 
+			... on WordHit{polish rank snippet} 
+			... on TranslationHit{polish english rank snippet} 
+			... on SentenceHit{polish english sentence rank snippet}}}`)},
+
+			"WATCH": &WatchCommand{query: `subscription wordChanged($polish: String, $pair: LanguagePair) 
+			{wordChanged(polish: $polish, pair: $pair){kind polish previousPolish word{text partOfSpeech gender aspect aspectPartner translations{text countability sentences{sentence}} inflections{form tags}}}}`},
+
+			"IMPORT": &ImportCommand{query: `mutation importFile($file: Upload!, $options: FileImportOptions, $pair: LanguagePair) 
+			{importFile(file: $file, options: $options, pair: $pair){created merged overwritten skipped rejected rows{line polish english status errorCode}}}`},
+
+			"STUDY": &StudyCommand{
+				dueCards: graphql.NewRequest(`query dueCards($limit: Int, $pair: LanguagePair) 
+				{dueCards(limit: $limit, pair: $pair){translationId text translation{text sentences{sentence}} interval}}`),
+				gradeCard: graphql.NewRequest(`mutation gradeCard($translationId: ID!, $grade: Int!) 
+				{gradeCard(translationId: $translationId, grade: $grade){translationId interval due}}`)},
+
+			"QUIZ": &QuizCommand{
+				generateQuiz: graphql.NewRequest(`query generateQuiz($size: Int, $direction: QuizDirection, $kinds: [QuestionKind!], $pair: LanguagePair) 
+				{generateQuiz(size: $size, direction: $direction, kinds: $kinds, pair: $pair){id kind prompt choices}}`),
+				submitQuiz: graphql.NewRequest(`mutation submitQuiz($answers: [QuizAnswer!]!) 
+				{submitQuiz(answers: $answers){score total answers{questionId answer correct acceptedAnswers}}}`)},
+
+			"EXPORT": &ExportCommand{},
 
-			"SELECT": &SelectWordCommand{request: graphql.NewRequest(`query selectWord($polish: String!) 
-			{selectWord(polish: $polish){translations{english sentences{sentence}}}}`)},
+			"EXPORT_ANKI": &ExportAnkiCommand{},
 
-			"UPDATE": &UpdateWordCommand{request: graphql.NewRequest(`mutation UpdateWord($polish: String!, $newPolish: String!) 
-			{updateWord(polish: $polish, newPolish: $newPolish)}`)},
+			"LIST": &ListWordsCommand{request: graphql.NewRequest(`query words($first: Int, $after: String, $prefix: String, $order: SortOrder, $grammar: GrammarFilter, $tag: String, $pair: LanguagePair) 
+			{words(first: $first, after: $after, prefix: $prefix, order: $order, grammar: $grammar, tag: $tag, pair: $pair){edges{node{text translations{text}}} pageInfo{endCursor hasNextPage}}}`)},
+
+			"UPDATE": &UpdateWordCommand{request: graphql.NewRequest(`mutation RenameWord($text: String!, $newText: String!, $pair: LanguagePair) 
+			{renameWord(text: $text, newText: $newText, pair: $pair){outcome word{text}}}`)},
 			"UPDATE_TRANSLATION": &UpdateTranslationCommand{request: graphql.NewRequest(
-				`mutation UpdateTranslation($polish: String!, $english: String!, $newEnglish: String!) 
-			{updateTranslation(polish: $polish, english: $english, newEnglish: $newEnglish)}`)},
+				`mutation RenameTranslation($text: String!, $translation: String!, $newTranslation: String!, $pair: LanguagePair) 
+			{renameTranslation(text: $text, translation: $translation, newTranslation: $newTranslation, pair: $pair){outcome word{text}}}`)},
 			"UPDATE_SENTENCE": &UpdateSentenceCommand{request: graphql.NewRequest(
-				`mutation UpdateSentence($polish: String!, $english: String!, $sentence: String! ,$newSentence: String!) 
-			{updateSentence(polish: $polish, english: $english, sentence: $sentence ,newSentence: $newSentence)}`)},
+				`mutation EditSentence($text: String!, $translation: String!, $sentence: String! ,$newSentence: String!, $original: String, $attribution: String, $license: String, $pair: LanguagePair) 
+			{editSentence(text: $text, translation: $translation, sentence: $sentence ,newSentence: $newSentence, original: $original, attribution: $attribution, license: $license, pair: $pair){outcome word{text}}}`)},
+			"GRAMMAR": &GrammarCommand{request: graphql.NewRequest(`mutation SetGrammar($polish: String!, $grammar: GrammarInput!, $pair: LanguagePair) 
+			{setGrammar(polish: $polish, grammar: $grammar, pair: $pair){outcome word{text}}}`)},
+			"COUNTABILITY": &CountabilityCommand{request: graphql.NewRequest(`mutation SetCountability($polish: String!, $english: String!, $countability: Countability, $pair: LanguagePair) 
+			{setCountability(polish: $polish, english: $english, countability: $countability, pair: $pair){outcome word{text}}}`)},
+			"INFLECT": &AddInflectionCommand{request: graphql.NewRequest(`mutation AddInflection($polish: String!, $form: String!, $tags: [String!]!, $pair: LanguagePair) 
+			{addInflection(polish: $polish, form: $form, tags: $tags, pair: $pair){outcome word{text}}}`)},
+			"DELETE_INFLECTION": &DeleteInflectionCommand{request: graphql.NewRequest(`mutation DeleteInflection($polish: String!, $form: String!, $tags: [String!], $pair: LanguagePair) 
+			{deleteInflection(polish: $polish, form: $form, tags: $tags, pair: $pair){outcome word{text}}}`)},
+			"PAIR": &PairCommand{},
+			"LINK": &LinkCommand{request: graphql.NewRequest(`mutation AddRelation($kind: RelationKind!, $from: RelationEnd!, $to: RelationEnd!, $pair: LanguagePair) 
+			{addRelation(kind: $kind, from: $from, to: $to, pair: $pair){outcome word{text}}}`)},
+			"UNLINK": &UnlinkCommand{request: graphql.NewRequest(`mutation RemoveRelation($kind: RelationKind!, $from: RelationEnd!, $to: RelationEnd!, $pair: LanguagePair) 
+			{removeRelation(kind: $kind, from: $from, to: $to, pair: $pair){outcome word{text}}}`)},
+			"TAG": &TagCommand{request: graphql.NewRequest(`mutation TagWord($text: String!, $tags: [String!]!, $pair: LanguagePair) 
+			{tagWord(text: $text, tags: $tags, pair: $pair){outcome word{text}}}`)},
+			"UNTAG": &UntagCommand{request: graphql.NewRequest(`mutation UntagWord($text: String!, $tags: [String!]!, $pair: LanguagePair) 
+			{untagWord(text: $text, tags: $tags, pair: $pair){outcome word{text}}}`)},
+			"TAGS": &TagsCommand{
+				tags: graphql.NewRequest(`query tags {tags{name words}}`),
+				renameTag: graphql.NewRequest(`mutation RenameTag($name: String!, $newName: String!) 
+				{tag: renameTag(name: $name, newName: $newName){name words}}`),
+				deleteTag: graphql.NewRequest(`mutation DeleteTag($name: String!) 
+				{tag: deleteTag(name: $name){name words}}`)},
+			"MOVE": &MoveCommand{
+				word: graphql.NewRequest(`query order($text: String!, $pair: LanguagePair) 
+				{word(text: $text, pair: $pair){text translations{text sentences{sentence original}}}}`),
+				translations: graphql.NewRequest(`mutation ReorderTranslations($polish: String!, $english: [String!]!, $pair: LanguagePair) 
+				{reorderTranslations(polish: $polish, english: $english, pair: $pair){outcome word{text}}}`),
+				sentences: graphql.NewRequest(`mutation ReorderSentences($polish: String!, $english: String!, $sentences: [String!]!, $pair: LanguagePair) 
+				{reorderSentences(polish: $polish, english: $english, sentences: $sentences, pair: $pair){outcome word{text}}}`)},
+			"COLLECTION": &CollectionCommand{
+				collections: graphql.NewRequest(`query collections {collections{name items{position}}}`),
+				collection: graphql.NewRequest(`query collection($name: String!) 
+				{collection(name: $name){name items{position text translation}}}`),
+				createCollection: graphql.NewRequest(`mutation CreateCollection($name: String!) 
+				{collection: createCollection(name: $name){name items{position text translation}}}`),
+				renameCollection: graphql.NewRequest(`mutation RenameCollection($name: String!, $newName: String!) 
+				{collection: renameCollection(name: $name, newName: $newName){name items{position text translation}}}`),
+				deleteCollection: graphql.NewRequest(`mutation DeleteCollection($name: String!) 
+				{collection: deleteCollection(name: $name){name items{position text translation}}}`),
+				addItem: graphql.NewRequest(`mutation AddToCollection($name: String!, $text: String!, $translation: String!, $pair: LanguagePair) 
+				{collection: addToCollection(name: $name, text: $text, translation: $translation, pair: $pair){name items{position text translation}}}`),
+				removeItem: graphql.NewRequest(`mutation RemoveFromCollection($name: String!, $text: String!, $translation: String!, $pair: LanguagePair) 
+				{collection: removeFromCollection(name: $name, text: $text, translation: $translation, pair: $pair){name items{position text translation}}}`)},
+			"IMPORT_INFLECTIONS": &ImportInflectionsCommand{request: graphql.NewRequest(`mutation ImportInflections($entries: [NewInflection!]!, $mode: ImportMode, $pair: LanguagePair) 
+			{importInflections(entries: $entries, mode: $mode, pair: $pair){index lemma form status errorCode}}`)},
 		},
 	}
 }
@@ -106,239 +300,1416 @@ func (f *CommandFactory) GetCommand(action string) (ICommand, bool) {
 	return command, exists
 }
 
+func (f *CommandFactory) CommandNames() []string {
+	names := make([]string, 0, len(f.commands))
+	for name := range f.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (s SelectWordCommand) Execute(input []string) error {
 
 	if len(input) != 1 {
-		return fmt.Errorf(`niepoprawna liczba argumentów dla operacji select. Użycie: SELECT polskie_słowo`)
+		return fmt.Errorf(`niepoprawna liczba argumentów dla operacji select. Użycie: SELECT słowo`)
 	}
 
-	polish := input[0]
+	text := input[0]
 
 	graphqlClient := GetClientInstance()
 
-	s.request.Var("polish", polish)
+	s.request.Var("text", text)
+	s.request.Var("pair", languagePair)
 
 	var graphqlResponse SelectResponse
 
 	if err := graphqlClient.Request(s.request, &graphqlResponse); err != nil {
+		var gqlErr GraphQLError
+		if errors.As(err, &gqlErr) && gqlErr.Code() == CodeWordNotFound && len(gqlErr.Suggestions()) > 0 {
+			if chosen, ok := ChooseSuggestion(gqlErr.Message, gqlErr.Suggestions()); ok {
+				return s.Execute([]string{chosen})
+			}
+			return nil
+		}
 		return err
 	}
 
-	PrintSelectOutput(graphqlResponse, polish)
+	//word may have been found ignoring diacritics, so the stored spelling is shown
+	if graphqlResponse.Word.Text != "" {
+		text = graphqlResponse.Word.Text
+	}
+
+	PrintSelectOutput(graphqlResponse, text)
 
 	return nil
 }
 
-func (u UpdateSentenceCommand) Execute(input []string) error {
+func (s SelectByEnglishCommand) Execute(input []string) error {
 
-	if len(input) != 4 {
-		return fmt.Errorf("niepoprawna liczba argumentów dla operacji zmodyfikuj zdanie. Użycie: UPDATE_SENTENCE polskie_słowo tłumaczenie stare_zdanie nowe_zdanie")
+	if len(input) != 1 {
+		return fmt.Errorf(`niepoprawna liczba argumentów dla operacji select_en. Użycie: SELECT_EN tłumaczenie`)
 	}
 
-	polish := input[0]
-	English := input[1]
-	Sentence := input[2]
-	newSentence := input[3]
+	translation := input[0]
 
 	graphqlClient := GetClientInstance()
-	u.request.Var("polish", polish)
-	u.request.Var("english", English)
-	u.request.Var("sentence", Sentence)
-	u.request.Var("newSentence", newSentence)
 
-	var graphqlResponse interface{}
+	s.request.Var("text", translation)
+	s.request.Var("pair", languagePair)
 
-	if err := graphqlClient.Request(u.request, &graphqlResponse); err != nil {
+	var graphqlResponse SelectByEnglishResponse
+
+	if err := graphqlClient.Request(s.request, &graphqlResponse); err != nil {
 		return err
 	}
 
+	PrintSelectByEnglishOutput(graphqlResponse, translation)
+
 	return nil
 }
 
-func (u UpdateTranslationCommand) Execute(input []string) error {
+func (s SearchCommand) Execute(input []string) error {
 
-	if len(input) != 3 {
-		return fmt.Errorf("niepoprawna liczba argumentów dla operacji zmodyfikuj tłumaczenie. Użycie: UPDATE_TRANSLATION polskie_słowo stare_tłumaczenie nowe_tłumaczenie")
+	if len(input) < 1 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji search. Użycie: SEARCH (szukany tekst) [ALL|WORDS|TRANSLATIONS|SENTENCES] [filtry gramatyczne] [tag:nazwa] [domain:dziedzina]")
+	}
+
+	//the searched text is never taken for a filter
+	filters, grammar, err := parseGrammarFilter(input[1:])
+	if err != nil {
+		return err
+	}
+	filters, tag, err := parseTagFilter(filters)
+	if err != nil {
+		return err
+	}
+	filters, grammar, err = parseDomainFilter(filters, grammar)
+	if err != nil {
+		return err
+	}
+	input = append([]string{input[0]}, filters...)
+
+	if len(input) > 2 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji search. Użycie: SEARCH (szukany tekst) [ALL|WORDS|TRANSLATIONS|SENTENCES] [filtry gramatyczne] [tag:nazwa] [domain:dziedzina]")
+	}
+
+	scope := "ALL"
+	if len(input) == 2 {
+		scope = input[1]
+	}
+
+	switch scope {
+	case "ALL", "WORDS", "TRANSLATIONS", "SENTENCES":
+	default:
+		return fmt.Errorf("niepoprawny zakres wyszukiwania %s. Dostępne: ALL, WORDS, TRANSLATIONS, SENTENCES", scope)
 	}
 
-	polish := input[0]
-	English := input[1]
-	newEnglish := input[2]
 	graphqlClient := GetClientInstance()
-	u.request.Var("polish", polish)
-	u.request.Var("english", English)
-	u.request.Var("newEnglish", newEnglish)
 
-	var graphqlResponse interface{}
+	s.request.Var("text", input[0])
+	s.request.Var("scope", scope)
+	s.request.Var("grammar", grammar)
+	s.request.Var("tag", tag)
+	s.request.Var("pair", languagePair)
 
-	if err := graphqlClient.Request(u.request, &graphqlResponse); err != nil {
+	var graphqlResponse SearchResponse
+
+	if err := graphqlClient.Request(s.request, &graphqlResponse); err != nil {
 		return err
 	}
 
+	PrintSearchOutput(graphqlResponse)
+
 	return nil
 }
 
-func (u UpdateWordCommand) Execute(input []string) error {
+func (w WatchCommand) Execute(input []string) error {
 
-	if len(input) != 2 {
-		return fmt.Errorf("niepoprawna liczba argumentów dla operacji zmodyfikuj słowo. Użycie: UPDATE stare_polskie_słowo nowe_polskie_słowo")
+	if len(input) > 1 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji watch. Użycie: WATCH [słowo]")
 	}
 
-	graphqlClient := GetClientInstance()
-	polish := input[0]
-	newPolish := input[1]
-	u.request.Var("polish", polish)
-	u.request.Var("newPolish", newPolish)
-
-	var graphqlResponse interface{}
+	variables := map[string]interface{}{"pair": languagePair}
+	if len(input) == 1 {
+		variables["polish"] = input[0]
+	}
 
-	if err := graphqlClient.Request(u.request, &graphqlResponse); err != nil {
+	stop, err := GetSubscriptionClientInstance().Subscribe(w.query, variables, func(data json.RawMessage) {
+		var event WordChangedResponse
+		if err := json.Unmarshal(data, &event); err == nil {
+			PrintWordChangedOutput(event)
+		}
+	})
+	if err != nil {
 		return err
 	}
+	defer stop()
+
+	fmt.Println("Obserwowanie zmian. Naciśnij enter, aby zakończyć")
+	GetReaderInstance().Read()
 
 	return nil
 }
 
-func (d DeleteWordCommand) Execute(input []string) error {
+func (i ImportCommand) Execute(input []string) error {
 
-	if len(input) != 1 {
-		return fmt.Errorf("niepoprawna liczba argumentów dla operacji usuń słowo. Użycie: DELETE polskie_słowo")
+	if len(input) < 1 || len(input) > 3 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji import. Użycie: IMPORT ścieżka_do_pliku [SKIP|MERGE|OVERWRITE] [separator]")
 	}
 
-	graphqlClient := GetClientInstance()
-	polish := input[0]
-	d.request.Var("polish", polish)
+	path := input[0]
+	options := map[string]interface{}{"delimiter": ","}
+	if strings.EqualFold(filepath.Ext(path), ".tsv") {
+		options["delimiter"] = "\t"
+	}
 
-	var graphqlResponse interface{}
+	for _, arg := range input[1:] {
+		switch arg {
+		case "SKIP", "MERGE", "OVERWRITE":
+			options["conflict"] = arg
+		default:
+			options["delimiter"] = arg
+		}
+	}
 
-	if err := graphqlClient.Request(d.request, &graphqlResponse); err != nil {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("nie można otworzyć pliku %s: %v", path, err)
+	}
+	defer file.Close()
+
+	var graphqlResponse ImportResponse
+
+	variables := map[string]interface{}{"options": options, "pair": languagePair}
+	if err := GetClientInstance().Upload(i.query, variables, "file", filepath.Base(path), file, &graphqlResponse); err != nil {
 		return err
 	}
 
+	PrintImportOutput(graphqlResponse)
+
 	return nil
 }
 
-func (a AddWordCommand) Execute(input []string) error {
+// Shows due cards one by one: the polish word first, then after the user answers the translation with
+// example sentences, and sends the grade the user gives to the answer
+func (s StudyCommand) Execute(input []string) error {
 
-	if len(input) < 2 {
-		return fmt.Errorf("niepoprawna liczba argumentów dla operacji dodaj słowo. Użycie: ADD polskie_słowo tłumaczenie przykładowe_zdanie_1, przykładowe_zdanie_2 .... przykładowe_zdanie_N")
+	if len(input) > 1 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji study. Użycie: STUDY [liczba_fiszek]")
 	}
 
-	sentences := []string{}
+	//requests are reused, so variables from the previous run are cleared
+	s.dueCards.Var("limit", nil)
+	s.dueCards.Var("pair", languagePair)
+	if len(input) == 1 {
+		limit, err := strconv.Atoi(input[0])
+		if err != nil {
+			return fmt.Errorf("liczba fiszek musi być liczbą, podano %s", input[0])
+		}
+		s.dueCards.Var("limit", limit)
+	}
 
 	graphqlClient := GetClientInstance()
-	polish := input[0]
-	translation := input[1]
-	for i := 2; i < len(input); i++ {
-		sentences = append(sentences, input[i])
+	reader := GetReaderInstance()
+
+	var dueCards DueCardsResponse
+	if err := graphqlClient.Request(s.dueCards, &dueCards); err != nil {
+		return err
 	}
 
-	a.request.Var("polish", polish)
-	newTran := NewTranslation{English: translation, Sentences: sentences}
-	a.request.Var("translation", newTran)
+	if len(dueCards.DueCards) == 0 {
+		fmt.Println("Brak fiszek do powtórki")
+		return nil
+	}
+
+	studied := 0
+	for i, card := range dueCards.DueCards {
+		PrintCardQuestion(card, i+1, len(dueCards.DueCards))
+		answer := reader.Read()
+		if answer == "q" {
+			break
+		}
+		PrintCardAnswer(card, answer)
+
+		grade, ok := readGrade(reader)
+		if !ok {
+			break
+		}
+
+		s.gradeCard.Var("translationId", card.TranslationID)
+		s.gradeCard.Var("grade", grade)
+
+		var graded GradeCardResponse
+		if err := graphqlClient.Request(s.gradeCard, &graded); err != nil {
+			return err
+		}
+		studied++
+		fmt.Printf("Następna powtórka za %d dni\n", graded.GradeCard.Interval)
+	}
 
-	var graphqlResponse interface{}
+	fmt.Printf("\nKoniec nauki, powtórzono fiszek: %d\n\n", studied)
 
-	if err := graphqlClient.Request(a.request, &graphqlResponse); err != nil {
+	return nil
+}
+
+// Asks for a grade until a valid one is given. Returns false if the user ends studying
+func readGrade(reader IReader) (int, bool) {
+	for {
+		fmt.Println("Oceń odpowiedź od 0 (nie pamiętam) do 5 (bez wahania), q kończy naukę:")
+		input := strings.TrimSpace(reader.Read())
+		if input == "q" {
+			return 0, false
+		}
+		if grade, err := strconv.Atoi(input); err == nil && grade >= 0 && grade <= 5 {
+			return grade, true
+		}
+	}
+}
+
+// Asks all questions first and sends the answers together, the server checks them and returns the score
+func (q QuizCommand) Execute(input []string) error {
+
+	usage := "Użycie: QUIZ [liczba_pytań] [PL_EN|EN_PL] [MULTIPLE_CHOICE|TYPING|CLOZE ...]"
+	q.generateQuiz.Var("size", nil)
+	q.generateQuiz.Var("direction", nil)
+	q.generateQuiz.Var("kinds", nil)
+	q.generateQuiz.Var("pair", languagePair)
+
+	kinds := []string{}
+	for i, arg := range input {
+		switch arg {
+		case "PL_EN", "EN_PL":
+			q.generateQuiz.Var("direction", arg)
+		case "MULTIPLE_CHOICE", "TYPING", "CLOZE":
+			kinds = append(kinds, arg)
+		default:
+			size, err := strconv.Atoi(arg)
+			if err != nil || i != 0 {
+				return fmt.Errorf("niepoprawny argument %s dla operacji quiz. %s", arg, usage)
+			}
+			q.generateQuiz.Var("size", size)
+		}
+	}
+	if len(kinds) > 0 {
+		q.generateQuiz.Var("kinds", kinds)
+	}
+
+	graphqlClient := GetClientInstance()
+	reader := GetReaderInstance()
+
+	var quiz QuizResponse
+	if err := graphqlClient.Request(q.generateQuiz, &quiz); err != nil {
+		return err
+	}
+
+	if len(quiz.GenerateQuiz) == 0 {
+		fmt.Println("Słownik jest pusty, nie ma z czego ułożyć quizu")
+		return nil
+	}
+
+	answers := []map[string]string{}
+	for i, question := range quiz.GenerateQuiz {
+		PrintQuizQuestion(question, i+1, len(quiz.GenerateQuiz))
+		answer := strings.TrimSpace(reader.Read())
+
+		//multiple choice questions can be answered with the number of the option
+		if choice, err := strconv.Atoi(answer); err == nil && choice >= 1 && choice <= len(question.Choices) {
+			answer = question.Choices[choice-1]
+		}
+		answers = append(answers, map[string]string{"questionId": question.ID, "answer": answer})
+	}
+
+	q.submitQuiz.Var("answers", answers)
+
+	var result QuizResultResponse
+	if err := graphqlClient.Request(q.submitQuiz, &result); err != nil {
 		return err
 	}
 
+	PrintQuizResult(result, quiz)
+
 	return nil
 }
 
-func (d DeleteTranslationCommand) Execute(input []string) error {
+var exportFormats = []string{"json", "ndjson", "csv", "anki", "apkg"}
 
-	if len(input) != 2 {
-		return fmt.Errorf("niepoprawna liczba argumentów dla operacji usuń tłumaczenie. Użycie: DELETE_TRANSLATION polskie_słowo tłumaczenie")
+func (e ExportCommand) Execute(input []string) error {
+
+	usage := "Użycie: EXPORT ścieżka_do_pliku [--format json|ndjson|csv|anki|apkg]"
+	if len(input) != 1 && !(len(input) == 3 && input[1] == "--format") {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji export. %s", usage)
+	}
+
+	path := input[0]
+	format := "json"
+	if len(input) == 3 {
+		format = input[2]
+	} else if ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), ".")); ext != "" {
+		format = ext
+	}
+
+	supported := false
+	for _, f := range exportFormats {
+		supported = supported || f == format
+	}
+	if !supported {
+		return fmt.Errorf("nieobsługiwany format eksportu %s. %s", format, usage)
 	}
-	polish := input[0]
-	translation := input[1]
-	graphqlClient := GetClientInstance()
 
-	d.request.Var("polish", polish)
-	d.request.Var("english", translation)
+	return exportToFile(path, format, ExportFilter{})
+}
 
-	var graphqlResponse interface{}
+// Files with .apkg extension are Anki packages, other files use Anki's text import format
+func (e ExportAnkiCommand) Execute(input []string) error {
 
-	if err := graphqlClient.Request(d.request, &graphqlResponse); err != nil {
+	usage := "Użycie: EXPORT_ANKI ścieżka_do_pliku [--prefix prefiks] [--tag nazwa]"
+	if len(input) == 0 || len(input)%2 == 0 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji export_anki. %s", usage)
+	}
+
+	path := input[0]
+	filter := ExportFilter{}
+	for i := 1; i < len(input); i += 2 {
+		switch input[i] {
+		case "--prefix":
+			filter.Prefix = input[i+1]
+		case "--tag":
+			filter.Tag = input[i+1]
+		default:
+			return fmt.Errorf("nieznana opcja %s dla operacji export_anki. %s", input[i], usage)
+		}
+	}
+
+	format := "anki"
+	if strings.EqualFold(filepath.Ext(path), ".apkg") {
+		format = "apkg"
+	}
+
+	return exportToFile(path, format, filter)
+}
+
+func exportToFile(path string, format string, filter ExportFilter) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("nie można utworzyć pliku %s: %v", path, err)
+	}
+	defer file.Close()
+
+	if err := GetClientInstance().Export(format, filter, file); err != nil {
+		//an incomplete file would look like a valid export
+		file.Close()
+		os.Remove(path)
 		return err
 	}
 
+	fmt.Printf("Słownik zapisano do pliku %s\n", path)
+
 	return nil
 }
 
-func (a AddTranslationCommand) Execute(input []string) error {
+// Without arguments shows the active language pair, otherwise switches to the given one
+func (p PairCommand) Execute(input []string) error {
 
-	if len(input) < 2 {
-		return fmt.Errorf("niepoprawna liczba argumentów dla operacji dodaj tłumaczenie. Użycie: ADD_TRANSLATION polskie_słowo tłumaczenie przykładowe_zdanie_1, przykładowe_zdanie_2 .... przykładowe_zdanie_N")
+	if len(input) > 1 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji pair. Użycie: PAIR [język_źródłowy-język_docelowy]")
+	}
+
+	if len(input) == 1 {
+		if err := SetLanguagePair(input[0]); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Aktywna para języków: %s\n", LanguagePairName())
+
+	return nil
+}
+
+func (l ListWordsCommand) Execute(input []string) error {
+
+	prefix := ""
+	order := "ASC"
+
+	input, grammar, err := parseGrammarFilter(input)
+	if err != nil {
+		return err
+	}
+	input, tag, err := parseTagFilter(input)
+	if err != nil {
+		return err
+	}
+	input, grammar, err = parseDomainFilter(input, grammar)
+	if err != nil {
+		return err
+	}
+
+	if len(input) > 2 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji list. Użycie: LIST [prefiks] [ASC|DESC] [filtry gramatyczne] [tag:nazwa] [domain:dziedzina]")
+	}
+
+	for _, arg := range input {
+		if arg == "ASC" || arg == "DESC" {
+			order = arg
+		} else if prefix == "" {
+			prefix = arg
+		} else {
+			return fmt.Errorf("niepoprawne argumenty dla operacji list. Użycie: LIST [prefiks] [ASC|DESC] [filtry gramatyczne] [tag:nazwa] [domain:dziedzina]")
+		}
 	}
 
-	sentences := []string{}
-	polish := input[0]
 	graphqlClient := GetClientInstance()
+	var after *string
+
+	for {
+		l.request.Var("first", listPageSize)
+		l.request.Var("after", after)
+		l.request.Var("prefix", prefix)
+		l.request.Var("order", order)
+		l.request.Var("grammar", grammar)
+		l.request.Var("tag", tag)
+		l.request.Var("pair", languagePair)
+
+		var graphqlResponse ListResponse
+
+		if err := graphqlClient.Request(l.request, &graphqlResponse); err != nil {
+			return err
+		}
+
+		PrintListOutput(graphqlResponse)
+
+		if !graphqlResponse.Words.PageInfo.HasNextPage {
+			return nil
+		}
+
+		fmt.Println("Pokazać kolejną stronę? (t/n)")
+		if GetReaderInstance().Read() != "t" {
+			return nil
+		}
+		after = graphqlResponse.Words.PageInfo.EndCursor
+	}
+}
+
+func (u UpdateSentenceCommand) Execute(input []string) error {
+
+	if len(input) != 4 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji zmodyfikuj zdanie. Użycie: UPDATE_SENTENCE słowo tłumaczenie stare_zdanie (nowe_zdanie | oryginał | źródło | licencja)")
+	}
 
+	text := input[0]
 	translation := input[1]
-	for i := 2; i < len(input); i++ {
-		sentences = append(sentences, input[i])
+	sentence, err := parseSentencePair(input[2])
+	if err != nil {
+		return err
+	}
+	//parts left out of the new sentence are kept, empty ones are cleared
+	newSentence, err := parseSentencePair(input[3])
+	if err != nil {
+		return err
 	}
 
-	a.request.Var("polish", polish)
-	newTran := NewTranslation{English: translation, Sentences: sentences}
-	a.request.Var("translation", newTran)
+	graphqlClient := GetClientInstance()
+	u.request.Var("text", text)
+	u.request.Var("translation", translation)
+	u.request.Var("sentence", sentence.Sentence)
+	u.request.Var("newSentence", newSentence.Sentence)
+	u.request.Var("original", newSentence.Original)
+	u.request.Var("attribution", newSentence.Attribution)
+	u.request.Var("license", newSentence.License)
+	u.request.Var("pair", languagePair)
 
-	var graphqlResponse interface{}
+	var graphqlResponse MutationResponse
 
-	if err := graphqlClient.Request(a.request, &graphqlResponse); err != nil {
+	if err := graphqlClient.Request(u.request, &graphqlResponse); err != nil {
 		return err
 	}
 
+	PrintMutationOutput(graphqlResponse)
+
 	return nil
 }
 
-func (d DeleteSentenceCommand) Execute(input []string) error {
+func (u UpdateTranslationCommand) Execute(input []string) error {
 
 	if len(input) != 3 {
-		return fmt.Errorf("niepoprawna liczba argumentów dla operacji usuń zdanie. Użycie: DELETE_SENTENCE polskie_słowo tłumaczenie przykładowe_zdanie")
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji zmodyfikuj tłumaczenie. Użycie: UPDATE_TRANSLATION słowo stare_tłumaczenie nowe_tłumaczenie")
 	}
 
-	polish := input[0]
+	text := input[0]
 	translation := input[1]
-	sentence := input[2]
+	newTranslation := input[2]
 	graphqlClient := GetClientInstance()
+	u.request.Var("text", text)
+	u.request.Var("translation", translation)
+	u.request.Var("newTranslation", newTranslation)
+	u.request.Var("pair", languagePair)
+
+	var graphqlResponse MutationResponse
 
-	d.request.Var("polish", polish)
-	d.request.Var("english", translation)
-	d.request.Var("sentence", sentence)
+	if err := graphqlClient.Request(u.request, &graphqlResponse); err != nil {
+		return err
+	}
 
-	var graphqlResponse interface{}
+	PrintMutationOutput(graphqlResponse)
 
-	if err := graphqlClient.Request(d.request, &graphqlResponse); err != nil {
+	return nil
+}
+
+func (u UpdateWordCommand) Execute(input []string) error {
+
+	if len(input) != 2 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji zmodyfikuj słowo. Użycie: UPDATE stare_słowo nowe_słowo")
+	}
+
+	graphqlClient := GetClientInstance()
+	text := input[0]
+	newText := input[1]
+	u.request.Var("text", text)
+	u.request.Var("newText", newText)
+	u.request.Var("pair", languagePair)
+
+	var graphqlResponse MutationResponse
+
+	if err := graphqlClient.Request(u.request, &graphqlResponse); err != nil {
 		return err
 	}
 
+	PrintMutationOutput(graphqlResponse)
+
 	return nil
 }
 
-func (a AddSentenceCommand) Execute(input []string) error {
+func (d DeleteWordCommand) Execute(input []string) error {
 
-	if len(input) != 3 {
-		return fmt.Errorf("niepoprawna liczba argumentów dla operacji dodaj zdanie. Użycie: ADD_SENTENCE polskie_słowo tłumaczenie przykładowe_zdanie")
+	if len(input) != 1 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji usuń słowo. Użycie: DELETE słowo")
 	}
 
-	polish := input[0]
-	translation := input[1]
-	sentence := input[2]
 	graphqlClient := GetClientInstance()
+	text := input[0]
+	d.request.Var("text", text)
+	d.request.Var("pair", languagePair)
+
+	var graphqlResponse MutationResponse
 
-	a.request.Var("polish", polish)
-	a.request.Var("english", translation)
-	a.request.Var("sentence", sentence)
+	if err := graphqlClient.Request(d.request, &graphqlResponse); err != nil {
+		return err
+	}
 
-	var graphqlResponse interface{}
+	PrintMutationOutput(graphqlResponse)
 
-	if err := graphqlClient.Request(a.request, &graphqlResponse); err != nil {
+	return nil
+}
+
+func (a AddWordCommand) Execute(input []string) error {
+
+	if len(input) < 2 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji dodaj słowo. Użycie: ADD słowo tłumaczenie przykładowe_zdanie_1, przykładowe_zdanie_2 .... przykładowe_zdanie_N")
+	}
+
+	graphqlClient := GetClientInstance()
+	text := input[0]
+	translation := input[1]
+	newTran, err := newTranslation(translation, input[2:])
+	if err != nil {
 		return err
 	}
 
+	a.request.Var("text", text)
+	a.request.Var("translation", newTran)
+	a.request.Var("pair", languagePair)
+
+	var graphqlResponse MutationResponse
+
+	if err := graphqlClient.Request(a.request, &graphqlResponse); err != nil {
+		return err
+	}
+
+	PrintMutationOutput(graphqlResponse)
+
+	return nil
+}
+
+func (d DeleteTranslationCommand) Execute(input []string) error {
+
+	if len(input) != 2 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji usuń tłumaczenie. Użycie: DELETE_TRANSLATION słowo tłumaczenie")
+	}
+	text := input[0]
+	translation := input[1]
+	graphqlClient := GetClientInstance()
+
+	d.request.Var("text", text)
+	d.request.Var("translation", translation)
+	d.request.Var("pair", languagePair)
+
+	var graphqlResponse MutationResponse
+
+	if err := graphqlClient.Request(d.request, &graphqlResponse); err != nil {
+		return err
+	}
+
+	PrintMutationOutput(graphqlResponse)
+
+	return nil
+}
+
+func (a AddTranslationCommand) Execute(input []string) error {
+
+	if len(input) < 2 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji dodaj tłumaczenie. Użycie: ADD_TRANSLATION słowo tłumaczenie przykładowe_zdanie_1, przykładowe_zdanie_2 .... przykładowe_zdanie_N")
+	}
+
+	text := input[0]
+	graphqlClient := GetClientInstance()
+
+	translation := input[1]
+	newTran, err := newTranslation(translation, input[2:])
+	if err != nil {
+		return err
+	}
+
+	a.request.Var("text", text)
+	a.request.Var("translation", newTran)
+	a.request.Var("pair", languagePair)
+
+	var graphqlResponse MutationResponse
+
+	if err := graphqlClient.Request(a.request, &graphqlResponse); err != nil {
+		return err
+	}
+
+	PrintMutationOutput(graphqlResponse)
+
+	return nil
+}
+
+func (d DeleteSentenceCommand) Execute(input []string) error {
+
+	if len(input) != 3 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji usuń zdanie. Użycie: DELETE_SENTENCE słowo tłumaczenie przykładowe_zdanie")
+	}
+
+	text := input[0]
+	translation := input[1]
+	//the server finds the sentence by itself or by its original, so either part of a pair is enough
+	sentence, err := parseSentencePair(input[2])
+	if err != nil {
+		return err
+	}
+	graphqlClient := GetClientInstance()
+
+	d.request.Var("text", text)
+	d.request.Var("translation", translation)
+	d.request.Var("sentence", sentence.Sentence)
+	d.request.Var("pair", languagePair)
+
+	var graphqlResponse MutationResponse
+
+	if err := graphqlClient.Request(d.request, &graphqlResponse); err != nil {
+		return err
+	}
+
+	PrintMutationOutput(graphqlResponse)
+
+	return nil
+}
+
+func (a AddSentenceCommand) Execute(input []string) error {
+
+	if len(input) != 3 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji dodaj zdanie. Użycie: ADD_SENTENCE słowo tłumaczenie (przykładowe_zdanie | oryginał | źródło | licencja)")
+	}
+
+	text := input[0]
+	translation := input[1]
+	sentence, err := parseSentencePair(input[2])
+	if err != nil {
+		return err
+	}
+	graphqlClient := GetClientInstance()
+
+	a.request.Var("text", text)
+	a.request.Var("translation", translation)
+	a.request.Var("sentence", sentence.Sentence)
+	a.request.Var("original", sentence.Original)
+	a.request.Var("attribution", sentence.Attribution)
+	a.request.Var("license", sentence.License)
+	a.request.Var("pair", languagePair)
+
+	var graphqlResponse MutationResponse
+
+	if err := graphqlClient.Request(a.request, &graphqlResponse); err != nil {
+		return err
+	}
+
+	PrintMutationOutput(graphqlResponse)
+
+	return nil
+}
+
+// Separates the parts of a sentence pair in a parenthesised argument, e.g. (I like my bike | Lubię mój rower | Tatoeba | CC BY 2.0 FR).
+// A separator preceded by a backslash is a part of the text, e.g. (Use \| to join commands)
+const sentencePairSeparator = "|"
+
+// Splits an argument on separators which aren't escaped and unescapes the parts
+func splitSentencePair(argument string) []string {
+	parts := []string{}
+	var part strings.Builder
+	for i := 0; i < len(argument); i++ {
+		switch {
+		case argument[i] == '\\' && strings.HasPrefix(argument[i+1:], sentencePairSeparator):
+			part.WriteString(sentencePairSeparator)
+			i += len(sentencePairSeparator)
+		case strings.HasPrefix(argument[i:], sentencePairSeparator):
+			parts = append(parts, part.String())
+			part.Reset()
+			i += len(sentencePairSeparator) - 1
+		default:
+			part.WriteByte(argument[i])
+		}
+	}
+	return append(parts, part.String())
+}
+
+// Splits an argument into the sentence, its original, attribution and license. Parts which aren't given are nil,
+// parts given empty are empty, e.g. (I like my bike | | Tatoeba) has an empty original
+func parseSentencePair(argument string) (SentenceInput, error) {
+	parts := splitSentencePair(argument)
+	if len(parts) > 4 {
+		return SentenceInput{}, fmt.Errorf("niepoprawne zdanie %s. Użycie: (zdanie | oryginał | źródło | licencja)", argument)
+	}
+
+	sentence := SentenceInput{Sentence: strings.TrimSpace(parts[0])}
+	if sentence.Sentence == "" {
+		return SentenceInput{}, fmt.Errorf("niepoprawne zdanie %s, brak zdania przed %s", argument, sentencePairSeparator)
+	}
+	for i, field := range []**string{&sentence.Original, &sentence.Attribution, &sentence.License} {
+		if i+1 < len(parts) {
+			part := strings.TrimSpace(parts[i+1])
+			*field = &part
+		}
+	}
+	return sentence, nil
+}
+
+// Builds a new translation from sentence arguments, sentences given as pairs are sent separately
+func newTranslation(text string, arguments []string) (NewTranslation, error) {
+	translation := NewTranslation{Text: text, Sentences: []string{}}
+	for _, argument := range arguments {
+		if parts := splitSentencePair(argument); len(parts) == 1 {
+			translation.Sentences = append(translation.Sentences, parts[0])
+			continue
+		}
+		pair, err := parseSentencePair(argument)
+		if err != nil {
+			return NewTranslation{}, err
+		}
+		translation.SentencePairs = append(translation.SentencePairs, pair)
+	}
+	return translation, nil
+}
+
+// Values of the grammar enums by the field of GrammarFilter and GrammarInput they belong to
+var grammarValues = map[string][]string{
+	"partOfSpeech": {"NOUN", "VERB", "ADJECTIVE", "ADVERB", "PRONOUN", "PREPOSITION", "CONJUNCTION", "NUMERAL", "PARTICLE", "INTERJECTION", "PHRASE"},
+	"gender":       {"MASCULINE_PERSONAL", "MASCULINE_ANIMATE", "MASCULINE_INANIMATE", "FEMININE", "NEUTER"},
+	"aspect":       {"IMPERFECTIVE", "PERFECTIVE"},
+	"countability": {"COUNTABLE", "UNCOUNTABLE", "BOTH"},
+	"label":        {"FORMAL", "COLLOQUIAL", "VULGAR", "ARCHAIC", "REGIONAL"},
+}
+
+// Returns the field which value belongs to, or "" if it isn't a value of any grammar enum
+func grammarField(value string) string {
+	for field, values := range grammarValues {
+		for _, v := range values {
+			if v == value {
+				return field
+			}
+		}
+	}
+	return ""
+}
+
+// Takes values of the grammar enums (e.g. NOUN FEMININE) out of command arguments and returns them as a GrammarFilter,
+// which is nil if none was given. The remaining arguments keep their order
+func parseGrammarFilter(input []string) ([]string, map[string]string, error) {
+	var filter map[string]string
+	rest := []string{}
+
+	for _, arg := range input {
+		field := grammarField(arg)
+		if field == "" {
+			rest = append(rest, arg)
+			continue
+		}
+		if filter == nil {
+			filter = map[string]string{}
+		}
+		if _, ok := filter[field]; ok {
+			return nil, nil, fmt.Errorf("niepoprawne argumenty, filtr %s podany więcej niż raz", field)
+		}
+		filter[field] = arg
+	}
+	return rest, filter, nil
+}
+
+// Prefixes of the arguments limiting LIST and SEARCH to words with a tag or with a translation of a domain, e.g. tag:kuchnia
+const (
+	tagFilterPrefix    = "tag:"
+	domainFilterPrefix = "domain:"
+)
+
+// Takes the argument starting with prefix out of command arguments and returns its value, which is nil if none was given
+func parsePrefixedFilter(input []string, prefix string) ([]string, *string, error) {
+	var value *string
+	rest := []string{}
+
+	for _, arg := range input {
+		if !strings.HasPrefix(arg, prefix) {
+			rest = append(rest, arg)
+			continue
+		}
+		if value != nil {
+			return nil, nil, fmt.Errorf("niepoprawne argumenty, filtr %s podany więcej niż raz", strings.TrimSuffix(prefix, ":"))
+		}
+		name := strings.TrimPrefix(arg, prefix)
+		if name == "" {
+			return nil, nil, fmt.Errorf("niepoprawne argumenty, brak wartości po %s", prefix)
+		}
+		value = &name
+	}
+	return rest, value, nil
+}
+
+// Takes the tag filter out of command arguments and returns the tag, which is nil if none was given
+func parseTagFilter(input []string) ([]string, *string, error) {
+	return parsePrefixedFilter(input, tagFilterPrefix)
+}
+
+// Takes the domain filter out of command arguments and adds it to the grammar filter, creating the filter if needed
+func parseDomainFilter(input []string, grammar map[string]string) ([]string, map[string]string, error) {
+	rest, domain, err := parsePrefixedFilter(input, domainFilterPrefix)
+	if err != nil || domain == nil {
+		return rest, grammar, err
+	}
+	if grammar == nil {
+		grammar = map[string]string{}
+	}
+	grammar["domain"] = *domain
+	return rest, grammar, nil
+}
+
+func (g GrammarCommand) Execute(input []string) error {
+
+	if len(input) < 2 || len(input) > 4 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji grammar. Użycie: GRAMMAR polskie_słowo część_mowy [rodzaj|aspekt] [para_aspektowa]")
+	}
+
+	polish := input[0]
+	if grammarField(input[1]) != "partOfSpeech" {
+		return fmt.Errorf("niepoprawna część mowy %s. Dostępne: %s", input[1], strings.Join(grammarValues["partOfSpeech"], ", "))
+	}
+
+	grammar := map[string]interface{}{"partOfSpeech": input[1]}
+	for _, arg := range input[2:] {
+		field := grammarField(arg)
+		switch {
+		case (field == "gender" || field == "aspect") && grammar[field] == nil:
+			grammar[field] = arg
+		case field == "" && grammar["aspectPartner"] == nil:
+			grammar["aspectPartner"] = arg
+		default:
+			return fmt.Errorf("niepoprawne argumenty dla operacji grammar. Użycie: GRAMMAR polskie_słowo część_mowy [rodzaj|aspekt] [para_aspektowa]")
+		}
+	}
+
+	graphqlClient := GetClientInstance()
+	g.request.Var("polish", polish)
+	g.request.Var("grammar", grammar)
+	g.request.Var("pair", languagePair)
+
+	var graphqlResponse MutationResponse
+
+	if err := graphqlClient.Request(g.request, &graphqlResponse); err != nil {
+		return err
+	}
+
+	PrintMutationOutput(graphqlResponse)
+
+	return nil
+}
+
+func (c CountabilityCommand) Execute(input []string) error {
+
+	if len(input) != 3 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji countability. Użycie: COUNTABILITY polskie_słowo tłumaczenie COUNTABLE|UNCOUNTABLE|BOTH|NONE")
+	}
+
+	//NONE clears countability of the translation
+	var countability *string
+	if input[2] != "NONE" {
+		if grammarField(input[2]) != "countability" {
+			return fmt.Errorf("niepoprawna policzalność %s. Dostępne: COUNTABLE, UNCOUNTABLE, BOTH, NONE", input[2])
+		}
+		countability = &input[2]
+	}
+
+	graphqlClient := GetClientInstance()
+	c.request.Var("polish", input[0])
+	c.request.Var("english", input[1])
+	c.request.Var("countability", countability)
+	c.request.Var("pair", languagePair)
+
+	var graphqlResponse MutationResponse
+
+	if err := graphqlClient.Request(c.request, &graphqlResponse); err != nil {
+		return err
+	}
+
+	PrintMutationOutput(graphqlResponse)
+
+	return nil
+}
+
+func (a AddInflectionCommand) Execute(input []string) error {
+
+	if len(input) < 2 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji inflect. Użycie: INFLECT polskie_słowo forma [tagi...]")
+	}
+
+	graphqlClient := GetClientInstance()
+	a.request.Var("polish", input[0])
+	a.request.Var("form", input[1])
+	a.request.Var("tags", append([]string{}, input[2:]...))
+	a.request.Var("pair", languagePair)
+
+	var graphqlResponse MutationResponse
+
+	if err := graphqlClient.Request(a.request, &graphqlResponse); err != nil {
+		return err
+	}
+
+	PrintMutationOutput(graphqlResponse)
+
+	return nil
+}
+
+func (d DeleteInflectionCommand) Execute(input []string) error {
+
+	if len(input) < 2 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji delete_inflection. Użycie: DELETE_INFLECTION polskie_słowo forma [tagi...]")
+	}
+
+	//without tags every entry of the form is deleted
+	var tags []string
+	if len(input) > 2 {
+		tags = input[2:]
+	}
+
+	graphqlClient := GetClientInstance()
+	d.request.Var("polish", input[0])
+	d.request.Var("form", input[1])
+	d.request.Var("tags", tags)
+	d.request.Var("pair", languagePair)
+
+	var graphqlResponse MutationResponse
+
+	if err := graphqlClient.Request(d.request, &graphqlResponse); err != nil {
+		return err
+	}
+
+	PrintMutationOutput(graphqlResponse)
+
+	return nil
+}
+
+type RelationEnd struct {
+	Text        string  `json:"text"`
+	Translation *string `json:"translation,omitempty"`
+}
+
+var relationKinds = []string{"SYNONYM", "ANTONYM", "SEE_ALSO", "FALSE_FRIEND", "DERIVED_FROM"}
+
+func relationKind(kind string) bool {
+	for _, k := range relationKinds {
+		if kind == k {
+			return true
+		}
+	}
+	return false
+}
+
+// Reads arguments of LINK and UNLINK: the kind and two words, or two words each followed by its translation
+func parseRelation(operation string, input []string) (string, RelationEnd, RelationEnd, error) {
+	usage := fmt.Errorf("niepoprawna liczba argumentów dla operacji %s. Użycie: %s rodzaj słowo [tłumaczenie] powiązane_słowo [powiązane_tłumaczenie]",
+		strings.ToLower(operation), operation)
+
+	if len(input) != 3 && len(input) != 5 {
+		return "", RelationEnd{}, RelationEnd{}, usage
+	}
+
+	kind := strings.ToUpper(input[0])
+	if !relationKind(kind) {
+		return "", RelationEnd{}, RelationEnd{}, fmt.Errorf("niepoprawny rodzaj relacji %s. Dostępne: %s", input[0], strings.Join(relationKinds, ", "))
+	}
+
+	if len(input) == 3 {
+		return kind, RelationEnd{Text: input[1]}, RelationEnd{Text: input[2]}, nil
+	}
+	return kind, RelationEnd{Text: input[1], Translation: &input[2]}, RelationEnd{Text: input[3], Translation: &input[4]}, nil
+}
+
+func (l LinkCommand) Execute(input []string) error {
+
+	kind, from, to, err := parseRelation("LINK", input)
+	if err != nil {
+		return err
+	}
+
+	graphqlClient := GetClientInstance()
+	l.request.Var("kind", kind)
+	l.request.Var("from", from)
+	l.request.Var("to", to)
+	l.request.Var("pair", languagePair)
+
+	var graphqlResponse MutationResponse
+
+	if err := graphqlClient.Request(l.request, &graphqlResponse); err != nil {
+		return err
+	}
+
+	PrintMutationOutput(graphqlResponse)
+
+	return nil
+}
+
+func (u UnlinkCommand) Execute(input []string) error {
+
+	kind, from, to, err := parseRelation("UNLINK", input)
+	if err != nil {
+		return err
+	}
+
+	graphqlClient := GetClientInstance()
+	u.request.Var("kind", kind)
+	u.request.Var("from", from)
+	u.request.Var("to", to)
+	u.request.Var("pair", languagePair)
+
+	var graphqlResponse MutationResponse
+
+	if err := graphqlClient.Request(u.request, &graphqlResponse); err != nil {
+		return err
+	}
+
+	PrintMutationOutput(graphqlResponse)
+
+	return nil
+}
+
+type NewInflection struct {
+	Lemma string   `json:"lemma"`
+	Form  string   `json:"form"`
+	Tags  []string `json:"tags"`
+}
+
+// Reads inflected forms from a CSV/TSV file with columns lemma, form and tags. Tags may be separated by spaces
+// or put in further columns. A first row naming the columns is skipped. Returns line numbers of the entries too
+func readInflections(file io.Reader, delimiter rune) ([]NewInflection, []int, error) {
+	reader := csv.NewReader(file)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+
+	entries := []NewInflection{}
+	lines := []int{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return entries, lines, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("niepoprawny plik: %v", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		if line == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "lemma") {
+			continue
+		}
+		if len(record) < 2 {
+			return nil, nil, fmt.Errorf("wiersz %d pliku powinien mieć co najmniej kolumny lemma i form", line)
+		}
+
+		tags := []string{}
+		for _, column := range record[2:] {
+			tags = append(tags, strings.Fields(column)...)
+		}
+		entries = append(entries, NewInflection{Lemma: record[0], Form: record[1], Tags: tags})
+		lines = append(lines, line)
+	}
+}
+
+func (i ImportInflectionsCommand) Execute(input []string) error {
+
+	if len(input) < 1 || len(input) > 2 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji import_inflections. Użycie: IMPORT_INFLECTIONS ścieżka_do_pliku [ATOMIC|BEST_EFFORT]")
+	}
+
+	path := input[0]
+	delimiter := ','
+	if strings.EqualFold(filepath.Ext(path), ".tsv") {
+		delimiter = '\t'
+	}
+
+	mode := "ATOMIC"
+	if len(input) == 2 {
+		if input[1] != "ATOMIC" && input[1] != "BEST_EFFORT" {
+			return fmt.Errorf("niepoprawny tryb importu %s. Dostępne: ATOMIC, BEST_EFFORT", input[1])
+		}
+		mode = input[1]
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("nie można otworzyć pliku %s: %v", path, err)
+	}
+	defer file.Close()
+
+	entries, lines, err := readInflections(file, delimiter)
+	if err != nil {
+		return err
+	}
+
+	graphqlClient := GetClientInstance()
+	i.request.Var("entries", entries)
+	i.request.Var("mode", mode)
+	i.request.Var("pair", languagePair)
+
+	var graphqlResponse ImportInflectionsResponse
+
+	if err := graphqlClient.Request(i.request, &graphqlResponse); err != nil {
+		return err
+	}
+
+	PrintImportInflectionsOutput(graphqlResponse, lines)
+
+	return nil
+}
+
+func (t TagCommand) Execute(input []string) error {
+
+	if len(input) < 2 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji tag. Użycie: TAG słowo tag [kolejne_tagi]")
+	}
+
+	graphqlClient := GetClientInstance()
+	t.request.Var("text", input[0])
+	t.request.Var("tags", input[1:])
+	t.request.Var("pair", languagePair)
+
+	var graphqlResponse MutationResponse
+
+	if err := graphqlClient.Request(t.request, &graphqlResponse); err != nil {
+		return err
+	}
+
+	PrintMutationOutput(graphqlResponse)
+
+	return nil
+}
+
+func (u UntagCommand) Execute(input []string) error {
+
+	if len(input) < 2 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji untag. Użycie: UNTAG słowo tag [kolejne_tagi]")
+	}
+
+	graphqlClient := GetClientInstance()
+	u.request.Var("text", input[0])
+	u.request.Var("tags", input[1:])
+	u.request.Var("pair", languagePair)
+
+	var graphqlResponse MutationResponse
+
+	if err := graphqlClient.Request(u.request, &graphqlResponse); err != nil {
+		return err
+	}
+
+	PrintMutationOutput(graphqlResponse)
+
+	return nil
+}
+
+// Without arguments lists tags with the number of their words, RENAME and DELETE change a tag on every word
+func (t TagsCommand) Execute(input []string) error {
+	usage := fmt.Errorf("niepoprawne argumenty dla operacji tags. Użycie: TAGS [RENAME tag nowa_nazwa | DELETE tag]")
+
+	graphqlClient := GetClientInstance()
+
+	if len(input) == 0 {
+		var graphqlResponse TagsResponse
+		if err := graphqlClient.Request(t.tags, &graphqlResponse); err != nil {
+			return err
+		}
+		PrintTagsOutput(graphqlResponse)
+		return nil
+	}
+
+	var request *graphql.Request
+	switch {
+	case strings.ToUpper(input[0]) == "RENAME" && len(input) == 3:
+		request = t.renameTag
+		request.Var("newName", input[2])
+	case strings.ToUpper(input[0]) == "DELETE" && len(input) == 2:
+		request = t.deleteTag
+	default:
+		return usage
+	}
+	request.Var("name", input[1])
+
+	var graphqlResponse TagResponse
+	if err := graphqlClient.Request(request, &graphqlResponse); err != nil {
+		return err
+	}
+
+	if request == t.deleteTag {
+		fmt.Printf("Usunięto tag %s ze słów: %d\n", graphqlResponse.Tag.Name, graphqlResponse.Tag.Words)
+	} else {
+		fmt.Printf("Zmieniono nazwę tagu na %s\n", graphqlResponse.Tag.Name)
+	}
+
+	return nil
+}
+
+// Without arguments lists collections, otherwise runs one of the subcommands on the collection named after it
+func (c CollectionCommand) Execute(input []string) error {
+	usage := fmt.Errorf("niepoprawne argumenty dla operacji collection. Użycie: COLLECTION [SHOW nazwa | CREATE nazwa | RENAME nazwa nowa_nazwa | DELETE nazwa | ADD nazwa słowo tłumaczenie | REMOVE nazwa słowo tłumaczenie]")
+
+	graphqlClient := GetClientInstance()
+
+	if len(input) == 0 {
+		var graphqlResponse CollectionsResponse
+		if err := graphqlClient.Request(c.collections, &graphqlResponse); err != nil {
+			return err
+		}
+		PrintCollectionsOutput(graphqlResponse)
+		return nil
+	}
+
+	var request *graphql.Request
+	switch subcommand := strings.ToUpper(input[0]); {
+	case subcommand == "SHOW" && len(input) == 2:
+		request = c.collection
+	case subcommand == "CREATE" && len(input) == 2:
+		request = c.createCollection
+	case subcommand == "RENAME" && len(input) == 3:
+		request = c.renameCollection
+		request.Var("newName", input[2])
+	case subcommand == "DELETE" && len(input) == 2:
+		request = c.deleteCollection
+	case (subcommand == "ADD" || subcommand == "REMOVE") && len(input) == 4:
+		request = c.addItem
+		if subcommand == "REMOVE" {
+			request = c.removeItem
+		}
+		request.Var("text", input[2])
+		request.Var("translation", input[3])
+		request.Var("pair", languagePair)
+	default:
+		return usage
+	}
+	request.Var("name", input[1])
+
+	var graphqlResponse CollectionResponse
+	if err := graphqlClient.Request(request, &graphqlResponse); err != nil {
+		return err
+	}
+
+	if request == c.deleteCollection {
+		fmt.Printf("Usunięto kolekcję %s\n", graphqlResponse.Collection.Name)
+		return nil
+	}
+	PrintCollectionOutput(graphqlResponse)
+
+	return nil
+}
+
+// Returns items with the one at index moved to position counted from 1, positions past the end move it to the end
+func moveItem(items []string, index int, position int) []string {
+	item := items[index]
+	rest := append(append([]string{}, items[:index]...), items[index+1:]...)
+	position = min(position, len(items))
+	return append(append(append([]string{}, rest[:position-1]...), item), rest[position-1:]...)
+}
+
+func (m MoveCommand) Execute(input []string) error {
+
+	if len(input) != 3 && len(input) != 4 {
+		return fmt.Errorf("niepoprawna liczba argumentów dla operacji move. Użycie: MOVE słowo tłumaczenie [zdanie] pozycja")
+	}
+	position, err := strconv.Atoi(input[len(input)-1])
+	if err != nil || position < 1 {
+		return fmt.Errorf("niepoprawna pozycja %s, pozycje liczone są od 1", input[len(input)-1])
+	}
+
+	//the whole new order is sent, so the current one is read first
+	graphqlClient := GetClientInstance()
+	m.word.Var("text", input[0])
+	m.word.Var("pair", languagePair)
+
+	var word SelectResponse
+
+	if err := graphqlClient.Request(m.word, &word); err != nil {
+		return err
+	}
+
+	translations := []string{}
+	index := -1
+	for i, t := range word.Word.Translations {
+		translations = append(translations, t.Text)
+		if t.Text == input[1] {
+			index = i
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("słowo %s nie ma tłumaczenia %s", word.Word.Text, input[1])
+	}
+
+	request := m.translations
+	if len(input) == 3 {
+		request.Var("english", moveItem(translations, index, position))
+	} else {
+		sentence, err := parseSentencePair(input[2])
+		if err != nil {
+			return err
+		}
+
+		sentences := []string{}
+		moved := -1
+		for i, s := range word.Word.Translations[index].Sentences {
+			sentences = append(sentences, s.Sentence)
+			if s.Sentence == sentence.Sentence || (s.Original != nil && *s.Original == sentence.Sentence) {
+				moved = i
+			}
+		}
+		if moved < 0 {
+			return fmt.Errorf("tłumaczenie %s nie ma zdania %s", input[1], sentence.Sentence)
+		}
+
+		request = m.sentences
+		request.Var("english", input[1])
+		request.Var("sentences", moveItem(sentences, moved, position))
+	}
+	request.Var("polish", word.Word.Text)
+	request.Var("pair", languagePair)
+
+	var graphqlResponse MutationResponse
+
+	if err := graphqlClient.Request(request, &graphqlResponse); err != nil {
+		return err
+	}
+
+	PrintMutationOutput(graphqlResponse)
+
 	return nil
 }
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/machinebox/graphql"
+)
+
+const completionsLimit = 20
+
+// Positional arguments are headwords in the source language of the pair or translations in its target language
+const (
+	headword    = "source"
+	translation = "target"
+)
+
+// Kind of each positional argument of a command which can be completed from the dictionary
+var argumentLanguages = map[string][]string{
+	"ADD":                {headword},
+	"ADD_TRANSLATION":    {headword},
+	"ADD_SENTENCE":       {headword, translation},
+	"DELETE":             {headword},
+	"DELETE_TRANSLATION": {headword, translation},
+	"DELETE_SENTENCE":    {headword, translation},
+	"SELECT":             {headword},
+	"SELECT_EN":          {translation},
+	"UPDATE":             {headword},
+	"UPDATE_TRANSLATION": {headword, translation},
+	"UPDATE_SENTENCE":    {headword, translation},
+	"LIST":               {headword},
+	"WATCH":              {headword},
+	"TAG":                {headword},
+	"UNTAG":              {headword},
+	"MOVE":               {headword, translation},
+}
+
+type AutocompleteResponse struct {
+	Autocomplete []string `json:"autocomplete"`
+}
+
+// Completes command names and, after a command, words stored in the dictionary
+type Completer struct {
+	commands *CommandFactory
+	request  *graphql.Request
+}
+
+func NewCompleter(commands *CommandFactory) Completer {
+	return Completer{
+		commands: commands,
+		request: graphql.NewRequest(`query autocomplete($prefix: String!, $language: Language, $limit: Int, $pair: LanguagePair) 
+		{autocomplete(prefix: $prefix, language: $language, limit: $limit, pair: $pair)}`),
+	}
+}
+
+func (c Completer) Complete(line string, pos int) (string, []string, string) {
+	runes := []rune(line)
+	head, tail := string(runes[:pos]), string(runes[pos:])
+
+	start := strings.LastIndex(head, " ") + 1
+	prefix := head[start:]
+	head = head[:start]
+
+	completions := []string{}
+	fields := strings.Fields(head)
+
+	if len(fields) == 0 {
+		for _, name := range c.commands.CommandNames() {
+			if strings.HasPrefix(name, prefix) {
+				completions = append(completions, name+" ")
+			}
+		}
+		return head, completions, tail
+	}
+
+	languages := argumentLanguages[fields[0]]
+	argument := len(fields) - 1
+	if argument >= len(languages) || strings.HasPrefix(prefix, "(") {
+		return head, completions, tail
+	}
+
+	language := languagePair.Source
+	if languages[argument] == translation {
+		language = languagePair.Target
+	}
+
+	c.request.Var("prefix", prefix)
+	c.request.Var("language", language)
+	c.request.Var("limit", completionsLimit)
+	c.request.Var("pair", languagePair)
+
+	var graphqlResponse AutocompleteResponse
+
+	//completion is only a convenience, so when the server can't be reached nothing is suggested
+	if err := GetClientInstance().Request(c.request, &graphqlResponse); err != nil {
+		return head, completions, tail
+	}
+
+	for _, word := range graphqlResponse.Autocomplete {
+		completions = append(completions, word+" ")
+	}
+	return head, completions, tail
+}
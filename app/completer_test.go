@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCompleter_CommandName_ShouldCompleteMatchingCommands(t *testing.T) {
+	completer := NewCompleter(NewCommandFactory())
+
+	head, completions, tail := completer.Complete("UPDATE_", 7)
+
+	assert.Equal(t, "", head)
+	assert.Equal(t, []string{"UPDATE_SENTENCE ", "UPDATE_TRANSLATION "}, completions)
+	assert.Equal(t, "", tail)
+}
+
+func TestCompleter_CommandArgument_ShouldCompleteFromDictionary(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	completer := NewCompleter(NewCommandFactory())
+
+	mockClient.On("Request", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		response := args.Get(1).(*AutocompleteResponse)
+		response.Autocomplete = []string{"żółw", "żółty"}
+	})
+
+	head, completions, tail := completer.Complete("SELECT żó", 9)
+
+	assert.Equal(t, "SELECT ", head)
+	assert.Equal(t, []string{"żółw ", "żółty "}, completions)
+	assert.Equal(t, "", tail)
+	mockClient.AssertExpectations(t)
+}
+
+func TestCompleter_SentenceArgument_ShouldNotComplete(t *testing.T) {
+	mockClient := new(MockGraphQLClient)
+	SetClientInstance(mockClient)
+
+	completer := NewCompleter(NewCommandFactory())
+
+	_, completions, _ := completer.Complete("ADD_SENTENCE kot cat (I l", 25)
+
+	assert.Empty(t, completions)
+	mockClient.AssertNotCalled(t, "Request", mock.Anything, mock.Anything)
+}
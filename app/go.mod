@@ -4,7 +4,9 @@ go 1.24.0
 
 require (
 	github.com/99designs/gqlgen v0.17.66
+	github.com/gorilla/websocket v1.5.0
 	github.com/machinebox/graphql v0.2.2
+	github.com/peterh/liner v1.2.2
 	github.com/stretchr/testify v1.10.0
 )
 
@@ -14,6 +16,7 @@ require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/matryer/is v1.4.1 // indirect
+	github.com/mattn/go-runewidth v0.0.3 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/russross/blackfriday/v2 v2.1.0 // indirect
@@ -24,6 +27,7 @@ require (
 	github.com/xrash/smetrics v0.0.0-20240521201337-686a1a2994c1 // indirect
 	golang.org/x/mod v0.23.0 // indirect
 	golang.org/x/sync v0.11.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
 	golang.org/x/text v0.22.0 // indirect
 	golang.org/x/tools v0.30.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
@@ -1,41 +1,668 @@
 package main
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
-	"os"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
+type WordResponse struct {
+	Text          string                 `json:"text"`
+	PartOfSpeech  *string                `json:"partOfSpeech"`
+	Gender        *string                `json:"gender"`
+	Aspect        *string                `json:"aspect"`
+	AspectPartner *string                `json:"aspectPartner"`
+	Tags          []string               `json:"tags"`
+	Pronunciation *PronunciationResponse `json:"pronunciation"`
+	Translations  []TranslationResponse  `json:"translations"`
+	Inflections   []InflectionResponse   `json:"inflections"`
+	MatchedForms  []InflectionResponse   `json:"matchedForms"`
+	Related       []RelationResponse     `json:"related"`
+}
+
+type TranslationResponse struct {
+	Text          string                 `json:"text"`
+	Countability  *string                `json:"countability"`
+	Labels        []string               `json:"labels"`
+	Domain        *string                `json:"domain"`
+	Note          *string                `json:"note"`
+	Pronunciation *PronunciationResponse `json:"pronunciation"`
+	Sentences     []SentenceResponse     `json:"sentences"`
+	Related       []RelationResponse     `json:"related"`
+}
+
+type SentenceResponse struct {
+	Sentence    string  `json:"sentence"`
+	Original    *string `json:"original"`
+	Attribution *string `json:"attribution"`
+	License     *string `json:"license"`
+}
+
+type PronunciationResponse struct {
+	IPA      *string `json:"ipa"`
+	Stress   *int    `json:"stress"`
+	AudioURL *string `json:"audioUrl"`
+}
+
+type RelationResponse struct {
+	Kind        string  `json:"kind"`
+	Word        string  `json:"word"`
+	Translation *string `json:"translation"`
+	Incoming    bool    `json:"incoming"`
+}
+
+type InflectionResponse struct {
+	Form string   `json:"form"`
+	Tags []string `json:"tags"`
+}
+
 type SelectResponse struct {
-	SelectWord struct {
-		Translations []struct {
-			English   string `json:"english"`
-			Sentences []struct {
-				Sentence string `json:"sentence"`
-			} `json:"sentences"`
-		} `json:"translations"`
-	} `json:"selectWord"`
-}
-
-func PrintSelectOutput(response SelectResponse, polish string) {
-	fmt.Printf("\n\nTłumaczenia dla słowa %s\n\n", polish)
-	for _, t := range response.SelectWord.Translations {
-		fmt.Printf("%s\n\n", t.English)
+	Word WordResponse `json:"word"`
+}
+
+type SelectByEnglishResponse struct {
+	WordsByTranslation []WordResponse `json:"wordsByTranslation"`
+}
+
+func PrintSelectOutput(response SelectResponse, text string) {
+	PrintMatchedForms(response.Word)
+	PrintWord(response.Word, text)
+}
+
+// Tells which word an inflected form was resolved to, e.g. "rowerem → rower (instrumental singular)"
+func PrintMatchedForms(word WordResponse) {
+	if len(word.MatchedForms) == 0 {
+		return
+	}
+	tags := []string{}
+	for _, f := range word.MatchedForms {
+		tags = append(tags, strings.Join(f.Tags, " "))
+	}
+	fmt.Printf("\n%s → %s (%s)", word.MatchedForms[0].Form, word.Text, strings.Join(tags, ", "))
+}
+
+func PrintSelectByEnglishOutput(response SelectByEnglishResponse, translation string) {
+	fmt.Printf("\n\nSłowa przetłumaczone jako %s:", translation)
+	for _, w := range response.WordsByTranslation {
+		PrintWord(w, w.Text)
+	}
+}
+
+func PrintWord(word WordResponse, text string) {
+	fmt.Printf("\n\nTłumaczenia dla słowa %s\n\n", text)
+	if grammar := wordGrammar(word); grammar != "" {
+		fmt.Printf("%s\n\n", grammar)
+	}
+	if len(word.Tags) > 0 {
+		fmt.Printf("Tagi: %s\n\n", strings.Join(word.Tags, ", "))
+	}
+	PrintPronunciation(word.Pronunciation)
+	if len(word.Inflections) > 0 {
+		forms := []string{}
+		for _, i := range word.Inflections {
+			forms = append(forms, fmt.Sprintf("%s (%s)", i.Form, strings.Join(i.Tags, " ")))
+		}
+		fmt.Printf("Odmiana: %s\n\n", strings.Join(forms, ", "))
+	}
+	if len(word.Related) > 0 {
+		fmt.Printf("Powiązane słowa: %s\n\n", relatedEntries(word.Related))
+	}
+	for _, t := range word.Translations {
+		if qualifiers := translationQualifiers(t.Countability, t.Labels, t.Domain); qualifiers != "" {
+			fmt.Printf("%s (%s)\n\n", t.Text, qualifiers)
+		} else {
+			fmt.Printf("%s\n\n", t.Text)
+		}
+		if t.Note != nil {
+			fmt.Printf("Uwaga: %s\n\n", *t.Note)
+		}
+		PrintPronunciation(t.Pronunciation)
+		if len(t.Related) > 0 {
+			fmt.Printf("Powiązane tłumaczenia: %s\n\n", relatedEntries(t.Related))
+		}
 		fmt.Printf("Przykładowe zdania:\n\n")
 		for _, s := range t.Sentences {
-			fmt.Printf("%s\n", s.Sentence)
+			fmt.Printf("%s\n", sentenceEntry(s))
 		}
 	}
 	fmt.Printf("\n\n")
 }
 
+// Describes an example sentence with its original and attribution, e.g. "I like my bike - Lubię mój rower (Tatoeba, CC BY 2.0 FR)"
+func sentenceEntry(sentence SentenceResponse) string {
+	entry := sentence.Sentence
+	if sentence.Original != nil {
+		entry += " - " + *sentence.Original
+	}
+	attribution := []string{}
+	for _, part := range []*string{sentence.Attribution, sentence.License} {
+		if part != nil {
+			attribution = append(attribution, *part)
+		}
+	}
+	if len(attribution) > 0 {
+		entry += " (" + strings.Join(attribution, ", ") + ")"
+	}
+	return entry
+}
+
+func PrintPronunciation(pronunciation *PronunciationResponse) {
+	if pronunciation == nil {
+		return
+	}
+	if transcription := pronunciationEntry(*pronunciation); transcription != "" {
+		fmt.Printf("Wymowa: %s\n\n", transcription)
+	}
+	if pronunciation.AudioURL != nil {
+		fmt.Printf("Nagranie: %s\n\n", ServerURL(*pronunciation.AudioURL))
+	}
+}
+
+// Describes the IPA transcription with the stressed syllable, e.g. "/ˈrɔ.vɛr/ (akcent na 1. sylabie)"
+func pronunciationEntry(pronunciation PronunciationResponse) string {
+	parts := []string{}
+	if pronunciation.IPA != nil {
+		parts = append(parts, "/"+*pronunciation.IPA+"/")
+	}
+	if pronunciation.Stress != nil {
+		parts = append(parts, fmt.Sprintf("(akcent na %d. sylabie)", *pronunciation.Stress))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Polish names of relations, read from the end they were created from and from the other end
+var relationLabels = map[string][2]string{
+	"SYNONYM":      {"synonim", "synonim"},
+	"ANTONYM":      {"antonim", "antonim"},
+	"SEE_ALSO":     {"zobacz też", "odsyła tutaj"},
+	"FALSE_FRIEND": {"fałszywy przyjaciel", "fałszywy przyjaciel"},
+	"DERIVED_FROM": {"pochodzi od", "wyraz pochodny"},
+}
+
+// Describes related words or translations, e.g. "bicykl (synonim), rowerzysta (wyraz pochodny)"
+func relatedEntries(related []RelationResponse) string {
+	entries := []string{}
+	for _, r := range related {
+		label := relationLabels[r.Kind][0]
+		if r.Incoming {
+			label = relationLabels[r.Kind][1]
+		}
+		if r.Translation != nil {
+			entries = append(entries, fmt.Sprintf("%s - %s (%s)", r.Word, *r.Translation, label))
+		} else {
+			entries = append(entries, fmt.Sprintf("%s (%s)", r.Word, label))
+		}
+	}
+	return strings.Join(entries, ", ")
+}
+
+// Polish names of values of the grammar enums
+var grammarLabels = map[string]string{
+	"NOUN":                "rzeczownik",
+	"VERB":                "czasownik",
+	"ADJECTIVE":           "przymiotnik",
+	"ADVERB":              "przysłówek",
+	"PRONOUN":             "zaimek",
+	"PREPOSITION":         "przyimek",
+	"CONJUNCTION":         "spójnik",
+	"NUMERAL":             "liczebnik",
+	"PARTICLE":            "partykuła",
+	"INTERJECTION":        "wykrzyknik",
+	"PHRASE":              "wyrażenie",
+	"MASCULINE_PERSONAL":  "rodzaj męskoosobowy",
+	"MASCULINE_ANIMATE":   "rodzaj męskożywotny",
+	"MASCULINE_INANIMATE": "rodzaj męskorzeczowy",
+	"FEMININE":            "rodzaj żeński",
+	"NEUTER":              "rodzaj nijaki",
+	"IMPERFECTIVE":        "aspekt niedokonany",
+	"PERFECTIVE":          "aspekt dokonany",
+	"COUNTABLE":           "policzalny",
+	"UNCOUNTABLE":         "niepoliczalny",
+	"BOTH":                "policzalny i niepoliczalny",
+	"FORMAL":              "formalny",
+	"COLLOQUIAL":          "potoczny",
+	"VULGAR":              "wulgarny",
+	"ARCHAIC":             "przestarzały",
+	"REGIONAL":            "regionalny",
+}
+
+// Describes countability and usage of a translation, e.g. "niepoliczalny, potoczny, dziedzina: medical"
+func translationQualifiers(countability *string, labels []string, domain *string) string {
+	parts := []string{}
+	if countability != nil {
+		parts = append(parts, grammarLabels[*countability])
+	}
+	for _, l := range labels {
+		parts = append(parts, grammarLabels[l])
+	}
+	if domain != nil {
+		parts = append(parts, "dziedzina: "+*domain)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Describes the grammatical metadata of a word, e.g. "czasownik, aspekt niedokonany, para aspektowa: zrobić"
+func wordGrammar(word WordResponse) string {
+	parts := []string{}
+	for _, value := range []*string{word.PartOfSpeech, word.Gender, word.Aspect} {
+		if value != nil {
+			parts = append(parts, grammarLabels[*value])
+		}
+	}
+	if word.AspectPartner != nil {
+		parts = append(parts, "para aspektowa: "+*word.AspectPartner)
+	}
+	return strings.Join(parts, ", ")
+}
+
+type ListResponse struct {
+	Words struct {
+		Edges []struct {
+			Node struct {
+				Text         string `json:"text"`
+				Translations []struct {
+					Text string `json:"text"`
+				} `json:"translations"`
+			} `json:"node"`
+		} `json:"edges"`
+		PageInfo struct {
+			EndCursor   *string `json:"endCursor"`
+			HasNextPage bool    `json:"hasNextPage"`
+		} `json:"pageInfo"`
+	} `json:"words"`
+}
+
+func PrintListOutput(response ListResponse) {
+	fmt.Printf("\n")
+	for _, e := range response.Words.Edges {
+		translations := []string{}
+		for _, t := range e.Node.Translations {
+			translations = append(translations, t.Text)
+		}
+		fmt.Printf("%s - %s\n", e.Node.Text, strings.Join(translations, ", "))
+	}
+	fmt.Printf("\n")
+}
+
+type SearchResponse struct {
+	Search []struct {
+		Typename string  `json:"__typename"`
+		Polish   string  `json:"polish"`
+		English  string  `json:"english"`
+		Sentence string  `json:"sentence"`
+		Rank     float64 `json:"rank"`
+		Snippet  string  `json:"snippet"`
+	} `json:"search"`
+}
+
+// search snippets mark matched words with <b></b>, which is printed as bold text in the terminal
+var snippetHighlighter = strings.NewReplacer("<b>", "\033[1m", "</b>", "\033[0m")
+
+func PrintSearchOutput(response SearchResponse) {
+	fmt.Printf("\n")
+	if len(response.Search) == 0 {
+		fmt.Printf("Brak wyników\n\n")
+		return
+	}
+	for _, r := range response.Search {
+		snippet := snippetHighlighter.Replace(r.Snippet)
+		switch r.Typename {
+		case "TranslationHit":
+			fmt.Printf("[tłumaczenie] %s - %s\n", r.Polish, snippet)
+		case "SentenceHit":
+			fmt.Printf("[zdanie] %s - %s: %s\n", r.Polish, r.English, snippet)
+		default:
+			fmt.Printf("[słowo] %s\n", snippet)
+		}
+	}
+	fmt.Printf("\n")
+}
+
+type TagsResponse struct {
+	Tags []struct {
+		Name  string `json:"name"`
+		Words int    `json:"words"`
+	} `json:"tags"`
+}
+
+// Response of the tag mutations, which are all aliased as tag
+type TagResponse struct {
+	Tag struct {
+		Name  string `json:"name"`
+		Words int    `json:"words"`
+	} `json:"tag"`
+}
+
+func PrintTagsOutput(response TagsResponse) {
+	fmt.Printf("\n")
+	if len(response.Tags) == 0 {
+		fmt.Printf("Brak tagów\n\n")
+		return
+	}
+	for _, t := range response.Tags {
+		fmt.Printf("%s (słów: %d)\n", t.Name, t.Words)
+	}
+	fmt.Printf("\n")
+}
+
+type CollectionItemResponse struct {
+	Position    int    `json:"position"`
+	Text        string `json:"text"`
+	Translation string `json:"translation"`
+}
+
+type CollectionsResponse struct {
+	Collections []struct {
+		Name  string                   `json:"name"`
+		Items []CollectionItemResponse `json:"items"`
+	} `json:"collections"`
+}
+
+// Response of the collection query and mutations, which are all aliased as collection
+type CollectionResponse struct {
+	Collection struct {
+		Name  string                   `json:"name"`
+		Items []CollectionItemResponse `json:"items"`
+	} `json:"collection"`
+}
+
+func PrintCollectionsOutput(response CollectionsResponse) {
+	fmt.Printf("\n")
+	if len(response.Collections) == 0 {
+		fmt.Printf("Brak kolekcji\n\n")
+		return
+	}
+	for _, c := range response.Collections {
+		fmt.Printf("%s (tłumaczeń: %d)\n", c.Name, len(c.Items))
+	}
+	fmt.Printf("\n")
+}
+
+func PrintCollectionOutput(response CollectionResponse) {
+	fmt.Printf("\nKolekcja %s\n\n", response.Collection.Name)
+	if len(response.Collection.Items) == 0 {
+		fmt.Printf("Kolekcja jest pusta\n\n")
+		return
+	}
+	for _, i := range response.Collection.Items {
+		fmt.Printf("%d. %s - %s\n", i.Position, i.Text, i.Translation)
+	}
+	fmt.Printf("\n")
+}
+
+// Lets the user pick one of the words suggested by the server. Returns false if nothing was chosen
+func ChooseSuggestion(message string, suggestions []string) (string, bool) {
+	fmt.Printf("%s. Czy chodziło ci o:\n", message)
+	for i, s := range suggestions {
+		fmt.Printf("%d. %s\n", i+1, s)
+	}
+	fmt.Println("Wybierz numer słowa lub naciśnij enter, aby anulować")
+
+	choice, err := strconv.Atoi(strings.TrimSpace(GetReaderInstance().Read()))
+	if err != nil || choice < 1 || choice > len(suggestions) {
+		return "", false
+	}
+	return suggestions[choice-1], true
+}
+
+type WordChangedResponse struct {
+	WordChanged struct {
+		Kind           string        `json:"kind"`
+		Polish         string        `json:"polish"`
+		PreviousPolish *string       `json:"previousPolish"`
+		Word           *WordResponse `json:"word"`
+	} `json:"wordChanged"`
+}
+
+func PrintWordChangedOutput(response WordChangedResponse) {
+	event := response.WordChanged
+	switch event.Kind {
+	case "CREATED":
+		fmt.Printf("\n[dodano] %s", event.Polish)
+	case "DELETED":
+		fmt.Printf("\n[usunięto] %s\n", event.Polish)
+		return
+	default:
+		if event.PreviousPolish != nil {
+			fmt.Printf("\n[zmieniono] %s -> %s", *event.PreviousPolish, event.Polish)
+		} else {
+			fmt.Printf("\n[zmieniono] %s", event.Polish)
+		}
+	}
+	if event.Word != nil {
+		PrintWord(*event.Word, event.Polish)
+	}
+}
+
+type MutationResult struct {
+	Outcome string `json:"outcome"`
+	Word    *struct {
+		Text string `json:"text"`
+	} `json:"word"`
+}
+
+// MutationResponse is keyed by the mutation field name, so it fits every mutation command
+type MutationResponse map[string]MutationResult
+
+func PrintMutationOutput(response MutationResponse) {
+	for _, result := range response {
+		text := ""
+		if result.Word != nil {
+			text = result.Word.Text
+		}
+		switch result.Outcome {
+		case "CREATED":
+			fmt.Printf("dodano słowo %s\n", text)
+		case "MERGED":
+			fmt.Printf("dodano do istniejącego słowa %s\n", text)
+		case "UPDATED":
+			fmt.Printf("zmodyfikowano słowo %s\n", text)
+		case "DELETED":
+			if text != "" {
+				fmt.Printf("usunięto ze słowa %s\n", text)
+			} else {
+				fmt.Println("usunięto słowo")
+			}
+		case "CASCADE_DELETED":
+			fmt.Println("usunięto tłumaczenie i słowo, które nie miało już innych tłumaczeń")
+		case "NOOP":
+			fmt.Println("nic nie zmieniono")
+		}
+	}
+}
+
+type ImportResponse struct {
+	ImportFile struct {
+		Created     int `json:"created"`
+		Merged      int `json:"merged"`
+		Overwritten int `json:"overwritten"`
+		Skipped     int `json:"skipped"`
+		Rejected    int `json:"rejected"`
+		Rows        []struct {
+			Line      int     `json:"line"`
+			Polish    string  `json:"polish"`
+			English   string  `json:"english"`
+			Status    string  `json:"status"`
+			ErrorCode *string `json:"errorCode"`
+		} `json:"rows"`
+	} `json:"importFile"`
+}
+
+func PrintImportOutput(response ImportResponse) {
+	report := response.ImportFile
+	fmt.Printf("\nDodano: %d, połączono: %d, nadpisano: %d, pominięto: %d, odrzucono: %d\n",
+		report.Created, report.Merged, report.Overwritten, report.Skipped, report.Rejected)
+
+	for _, row := range report.Rows {
+		if row.Status == "FAILED" && row.ErrorCode != nil {
+			fmt.Printf("wiersz %d odrzucony (%s): %s %s\n", row.Line, *row.ErrorCode, row.Polish, row.English)
+		}
+	}
+	fmt.Printf("\n")
+}
+
+type ImportInflectionsResponse struct {
+	ImportInflections []struct {
+		Index     int     `json:"index"`
+		Lemma     string  `json:"lemma"`
+		Form      string  `json:"form"`
+		Status    string  `json:"status"`
+		ErrorCode *string `json:"errorCode"`
+	} `json:"importInflections"`
+}
+
+// lines are line numbers of the imported entries in the file
+func PrintImportInflectionsOutput(response ImportInflectionsResponse, lines []int) {
+	counts := map[string]int{}
+	for _, r := range response.ImportInflections {
+		counts[r.Status]++
+	}
+	fmt.Printf("\nDodano: %d, pominięto: %d, odrzucono: %d\n", counts["CREATED"], counts["SKIPPED"], counts["FAILED"])
+
+	for _, r := range response.ImportInflections {
+		if r.Status == "FAILED" && r.ErrorCode != nil {
+			fmt.Printf("wiersz %d odrzucony (%s): %s %s\n", lines[r.Index], *r.ErrorCode, r.Lemma, r.Form)
+		}
+	}
+	fmt.Printf("\n")
+}
+
+type CardResponse struct {
+	TranslationID string `json:"translationId"`
+	Text          string `json:"text"`
+	Translation   struct {
+		Text      string `json:"text"`
+		Sentences []struct {
+			Sentence string `json:"sentence"`
+		} `json:"sentences"`
+	} `json:"translation"`
+	Interval int    `json:"interval"`
+	Due      string `json:"due"`
+}
+
+type DueCardsResponse struct {
+	DueCards []CardResponse `json:"dueCards"`
+}
+
+type GradeCardResponse struct {
+	GradeCard CardResponse `json:"gradeCard"`
+}
+
+func PrintCardQuestion(card CardResponse, number int, count int) {
+	fmt.Printf("\n[%d/%d] %s\n", number, count, card.Text)
+	fmt.Println("Podaj tłumaczenie i naciśnij enter, aby zobaczyć odpowiedź (q kończy naukę):")
+}
+
+func PrintCardAnswer(card CardResponse, answer string) {
+	translation := card.Translation.Text
+	if answer != "" && strings.EqualFold(strings.TrimSpace(answer), translation) {
+		fmt.Printf("Dobrze! %s\n", translation)
+	} else {
+		fmt.Printf("Odpowiedź: %s\n", translation)
+	}
+	for _, s := range card.Translation.Sentences {
+		fmt.Printf("  %s\n", s.Sentence)
+	}
+}
+
+type QuizQuestionResponse struct {
+	ID      string   `json:"id"`
+	Kind    string   `json:"kind"`
+	Prompt  string   `json:"prompt"`
+	Choices []string `json:"choices"`
+}
+
+type QuizResponse struct {
+	GenerateQuiz []QuizQuestionResponse `json:"generateQuiz"`
+}
+
+type QuizResultResponse struct {
+	SubmitQuiz struct {
+		Score   int `json:"score"`
+		Total   int `json:"total"`
+		Answers []struct {
+			QuestionID      string   `json:"questionId"`
+			Answer          string   `json:"answer"`
+			Correct         bool     `json:"correct"`
+			AcceptedAnswers []string `json:"acceptedAnswers"`
+		} `json:"answers"`
+	} `json:"submitQuiz"`
+}
+
+func PrintQuizQuestion(question QuizQuestionResponse, number int, count int) {
+	switch question.Kind {
+	case "CLOZE":
+		fmt.Printf("\n[%d/%d] Uzupełnij zdanie: %s\n", number, count, question.Prompt)
+	default:
+		fmt.Printf("\n[%d/%d] Przetłumacz: %s\n", number, count, question.Prompt)
+	}
+	for i, c := range question.Choices {
+		fmt.Printf("%d. %s\n", i+1, c)
+	}
+}
+
+func PrintQuizResult(response QuizResultResponse, quiz QuizResponse) {
+	prompts := map[string]string{}
+	for _, q := range quiz.GenerateQuiz {
+		prompts[q.ID] = q.Prompt
+	}
+
+	result := response.SubmitQuiz
+	fmt.Printf("\n")
+	for _, a := range result.Answers {
+		if a.Correct {
+			fmt.Printf("[dobrze] %s - %s\n", prompts[a.QuestionID], a.Answer)
+		} else {
+			fmt.Printf("[źle] %s - %s, poprawnie: %s\n", prompts[a.QuestionID], a.Answer, strings.Join(a.AcceptedAnswers, ", "))
+		}
+	}
+	fmt.Printf("\nWynik: %d/%d\n\n", result.Score, result.Total)
+}
+
+// Hints printed after server errors, keyed by error code and language
+var errorHints = map[string]map[string]string{
+	CodeWordExists: {
+		"pl": "Użyj ADD_TRANSLATION, aby dodać do niego tłumaczenie",
+		"en": "Use ADD_TRANSLATION to add a translation to it",
+	},
+	CodeTranslationExists: {
+		"pl": "Użyj ADD_SENTENCE, aby dodać do niego zdanie",
+		"en": "Use ADD_SENTENCE to add a sentence to it",
+	},
+	CodeWordNotFound: {
+		"pl": "Użyj ADD, aby je dodać",
+		"en": "Use ADD to add it",
+	},
+	CodeInternal: {
+		"pl": "Zgłoś administratorowi identyfikator błędu",
+		"en": "Report the error ID to the administrator",
+	},
+}
+
+// Prints the error message, which the server sends in the chosen language, with a hint depending on its code
+func PrintError(err error) {
+	var gqlErr GraphQLError
+	if !errors.As(err, &gqlErr) {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(gqlErr.Message)
+	if hint, ok := errorHints[gqlErr.Code()][language]; ok {
+		fmt.Println(hint)
+	}
+}
+
 func ListenForInput() {
 	var action string
-	reader := Reader{bufio.NewReader(os.Stdin)}
 	commands := NewCommandFactory()
-	fmt.Println("wybierz operację:\nADD - dodaj nowe słowo i jego tłumaczenie\nDELETE - usuń słowo\nSELECT - otrzymaj informacje o tłumaczeniu\n\nPolecenia modyfikujące istniejące tłumaczenia:\nADD TRANSLATION - dodaj tłumaczenie do słowa ze słownika\nDELETE TRANSLATION - usuń tłumaczenie\nADD SENTENCE - dodaj przykładowe zdanie do tłumaczenia\nDELETE SENTENCE - usuń przykładowe zdanie z danego tłumaczenia\nUPDATE - modyfikuje polską część\nUPDATE TRANSLATION - modyfikuje angielską częśc\nUPDATE SENTENCE - modyfikuje dane zdanie przykładowe")
+	lineReader := NewLineReader(NewCompleter(commands).Complete)
+	defer lineReader.Close()
+	SetReaderInstance(lineReader)
+	reader := GetReaderInstance()
+	fmt.Println("wybierz operację:\nADD - dodaj nowe słowo i jego tłumaczenie\nDELETE - usuń słowo\nSELECT - otrzymaj informacje o tłumaczeniu\nSELECT_EN - znajdź słowa po tłumaczeniu\nLIST - przeglądaj słowa w słowniku\nSEARCH - szukaj w słowach, tłumaczeniach i zdaniach\nWATCH - obserwuj zmiany w słowniku na żywo\nIMPORT - importuj słowa z pliku CSV/TSV\nEXPORT - zapisz cały słownik do pliku JSON, NDJSON lub CSV\nEXPORT_ANKI - zapisz słownik jako talię fiszek Anki\nSTUDY - ucz się słówek z fiszkami powtarzanymi w odstępach\nQUIZ - sprawdź się w quizie ze słówek\nPAIR - pokaż lub zmień parę języków słownika\n\nPolecenia modyfikujące istniejące tłumaczenia:\nADD TRANSLATION - dodaj tłumaczenie do słowa ze słownika\nDELETE TRANSLATION - usuń tłumaczenie\nADD SENTENCE - dodaj przykładowe zdanie do tłumaczenia\nDELETE SENTENCE - usuń przykładowe zdanie z danego tłumaczenia\nUPDATE - modyfikuje słowo\nUPDATE TRANSLATION - modyfikuje tłumaczenie\nUPDATE SENTENCE - modyfikuje dane zdanie przykładowe\nMOVE - zmień kolejność tłumaczeń lub zdań przykładowych\nGRAMMAR - ustaw część mowy, rodzaj, aspekt i parę aspektową słowa\nCOUNTABILITY - ustaw policzalność angielskiego tłumaczenia\nINFLECT - dodaj odmienioną formę słowa\nDELETE_INFLECTION - usuń odmienioną formę słowa\nIMPORT_INFLECTIONS - importuj odmienione formy z pliku CSV/TSV\nLINK - powiąż dwa słowa lub tłumaczenia relacją (synonim, antonim...)\nUNLINK - usuń relację między słowami lub tłumaczeniami\nTAG - oznacz słowo tagami\nUNTAG - usuń tagi ze słowa\nTAGS - pokaż, zmień nazwę lub usuń tagi\nCOLLECTION - twórz kolekcje tłumaczeń i zarządzaj nimi\n\nTAB uzupełnia nazwy poleceń i słowa ze słownika")
 	for {
 		action = reader.Read()
 		if action == "exit" {
@@ -45,7 +672,7 @@ func ListenForInput() {
 		command, exists := commands.GetCommand(parsed[0])
 		if exists {
 			if err := command.Execute(parsed[1:]); err != nil {
-				fmt.Println(err)
+				PrintError(err)
 			}
 		} else {
 			fmt.Println("Podane działanie nie istnieje")
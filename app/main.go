@@ -1,5 +1,25 @@
 package main
 
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
 func main() {
+	lang := flag.String("lang", "pl", "język komunikatów serwera (pl, en)")
+	pair := flag.String("pair", "pl-en", "para języków słownika (np. pl-en, de-en, es-pl)")
+	flag.Parse()
+
+	if err := SetLanguage(*lang); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if err := SetLanguagePair(*pair); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
 	ListenForInput()
 }
@@ -16,6 +16,7 @@ func TestInputParsing(t *testing.T) {
 		{"delete word", []string{"delete", "word"}},
 		{"word", []string{"word"}},
 		{"(sentence sentence)", []string{"sentence sentence"}},
+		{"ADD_SENTENCE rower bike (I like my bike | Lubię mój rower)", []string{"ADD_SENTENCE", "rower", "bike", "I like my bike | Lubię mój rower"}},
 	}
 
 	for _, test := range tests {
@@ -24,3 +25,46 @@ func TestInputParsing(t *testing.T) {
 	}
 
 }
+
+func TestParseSentencePair(t *testing.T) {
+	original, attribution, empty, pipe := "Lubię mój rower", "Tatoeba", "", "Tatoeba |"
+
+	tests := []struct {
+		input    string
+		expected SentenceInput
+	}{
+		{"I like my bike", SentenceInput{Sentence: "I like my bike"}},
+		{"I like my bike | Lubię mój rower", SentenceInput{Sentence: "I like my bike", Original: &original}},
+		{"I like my bike | | Tatoeba", SentenceInput{Sentence: "I like my bike", Original: &empty, Attribution: &attribution}},
+		{`cat a \| grep b`, SentenceInput{Sentence: "cat a | grep b"}},
+		{`cat a \| grep b | Tatoeba \|`, SentenceInput{Sentence: "cat a | grep b", Original: &pipe}},
+	}
+
+	for _, test := range tests {
+		result, err := parseSentencePair(test.input)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, result)
+	}
+
+	_, err := parseSentencePair(" | Lubię mój rower")
+	assert.Error(t, err)
+	_, err = parseSentencePair("a | b | c | d | e")
+	assert.Error(t, err)
+}
+
+func TestNewTranslation_PairsShouldBeSentSeparately(t *testing.T) {
+	translation, err := newTranslation("bike", []string{"My bike is green", "I like my bike | Lubię mój rower"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"My bike is green"}, translation.Sentences)
+	assert.Len(t, translation.SentencePairs, 1)
+	assert.Equal(t, "Lubię mój rower", *translation.SentencePairs[0].Original)
+}
+
+func TestNewTranslation_EscapedSeparator_ShouldBeSentAsSentence(t *testing.T) {
+	translation, err := newTranslation("pipe", []string{`Use \| to join commands`, `a\b`})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Use | to join commands", `a\b`}, translation.Sentences)
+	assert.Empty(t, translation.SentencePairs)
+}
@@ -2,15 +2,65 @@ package main
 
 import (
 	"bufio"
+	"os"
 	"strings"
+
+	"github.com/peterh/liner"
 )
 
+var readerInstance IReader
+
+type IReader interface {
+	Read() string
+}
+
 type Reader struct {
 	reader *bufio.Reader
 }
 
+// Reads lines from the terminal with line editing, history and tab completion
+type LineReader struct {
+	state *liner.State
+}
+
+func GetReaderInstance() IReader {
+	if readerInstance == nil {
+		readerInstance = Reader{bufio.NewReader(os.Stdin)}
+	}
+
+	return readerInstance
+}
+
+func SetReaderInstance(reader IReader) {
+	readerInstance = reader
+}
+
 func (r Reader) Read() string {
 	input, _ := r.reader.ReadString('\n')
 	input = strings.Replace(input, "\n", "", -1)
 	return input
 }
+
+func NewLineReader(completer liner.WordCompleter) LineReader {
+	state := liner.NewLiner()
+	state.SetWordCompleter(completer)
+	state.SetTabCompletionStyle(liner.TabPrints)
+	return LineReader{state: state}
+}
+
+// Closing the reader restores the terminal to the mode it was in before
+func (r LineReader) Close() error {
+	return r.state.Close()
+}
+
+func (r LineReader) Read() string {
+	input, err := r.state.Prompt("")
+	if err != nil {
+		//end of input (ctrl+D) ends the session
+		return "exit"
+	}
+	if strings.TrimSpace(input) != "" {
+		r.state.AppendHistory(input)
+	}
+	return input
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var subscriptionClientInstance SubscriptionClientInterface
+
+type SubscriptionClientInterface interface {
+	// Starts a subscription and calls onEvent with the data of every event until stop is called
+	Subscribe(query string, variables map[string]interface{}, onEvent func(data json.RawMessage)) (stop func(), err error)
+}
+
+// Runs subscriptions over websocket using the graphql-transport-ws protocol
+type SubscriptionClient struct {
+	endpoint string
+}
+
+// How long stopping a subscription waits for the server to close the connection before closing it itself
+const subscriptionCloseTimeout = time.Second
+
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+func GetSubscriptionClientInstance() SubscriptionClientInterface {
+	if subscriptionClientInstance == nil {
+		subscriptionClientInstance = &SubscriptionClient{endpoint: "ws://localhost:8080/query"}
+	}
+
+	return subscriptionClientInstance
+}
+
+func SetSubscriptionClientInstance(client SubscriptionClientInterface) {
+	subscriptionClientInstance = client
+}
+
+func (c *SubscriptionClient) Subscribe(query string, variables map[string]interface{}, onEvent func(data json.RawMessage)) (func(), error) {
+	dialer := websocket.Dialer{Subprotocols: []string{"graphql-transport-ws"}}
+	conn, _, err := dialer.Dial(c.endpoint, http.Header{"Accept-Language": []string{language}})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn.WriteJSON(wsMessage{Type: "connection_init"}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	var ack wsMessage
+	if err := conn.ReadJSON(&ack); err != nil || ack.Type != "connection_ack" {
+		conn.Close()
+		return nil, fmt.Errorf("serwer odrzucił połączenie subskrypcji")
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := conn.WriteJSON(wsMessage{ID: "1", Type: "subscribe", Payload: payload}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	//websocket connections allow one writer at a time, and both the reader (answering pings) and stop write
+	var writeMutex sync.Mutex
+	write := func(message wsMessage) error {
+		writeMutex.Lock()
+		defer writeMutex.Unlock()
+		return conn.WriteJSON(message)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			var message wsMessage
+			if err := conn.ReadJSON(&message); err != nil {
+				return
+			}
+
+			switch message.Type {
+			case "next":
+				var result struct {
+					Data json.RawMessage `json:"data"`
+				}
+				if err := json.Unmarshal(message.Payload, &result); err == nil {
+					onEvent(result.Data)
+				}
+			case "ping":
+				write(wsMessage{Type: "pong"})
+			case "error", "complete":
+				return
+			}
+		}
+	}()
+
+	//the reader is stopped before the connection is closed, so no event is handled after stop returns
+	stop := func() {
+		if write(wsMessage{ID: "1", Type: "complete"}) == nil {
+			writeMutex.Lock()
+			conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			writeMutex.Unlock()
+
+			select {
+			case <-done:
+			case <-time.After(subscriptionCloseTimeout):
+			}
+		}
+		conn.Close()
+		<-done
+	}
+	return stop, nil
+}
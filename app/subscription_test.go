@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// Server which acknowledges the subscription, sends an event and then keeps pinging the client until it disconnects
+func pingingSubscriptionServer(t *testing.T) *httptest.Server {
+	upgrader := websocket.Upgrader{Subprotocols: []string{"graphql-transport-ws"}}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+
+		var message wsMessage
+		conn.ReadJSON(&message)
+		conn.WriteJSON(wsMessage{Type: "connection_ack"})
+		conn.ReadJSON(&message)
+		conn.WriteJSON(wsMessage{ID: "1", Type: "next", Payload: json.RawMessage(`{"data":{"wordChanged":{"polish":"rower"}}}`)})
+
+		go func() {
+			for {
+				if err := conn.ReadJSON(&message); err != nil {
+					return
+				}
+			}
+		}()
+		for {
+			if err := conn.WriteJSON(wsMessage{Type: "ping"}); err != nil {
+				return
+			}
+		}
+	}))
+}
+
+func TestSubscriptionClient_StopWhilePinged_ShouldWaitForReader(t *testing.T) {
+	server := pingingSubscriptionServer(t)
+	defer server.Close()
+
+	events := make(chan json.RawMessage, 1)
+	client := &SubscriptionClient{endpoint: "ws" + strings.TrimPrefix(server.URL, "http")}
+
+	stop, err := client.Subscribe("subscription { wordChanged { polish } }", nil, func(data json.RawMessage) {
+		events <- data
+	})
+	assert.NoError(t, err)
+
+	assert.JSONEq(t, `{"wordChanged":{"polish":"rower"}}`, string(<-events))
+	stop()
+}
@@ -0,0 +1,45 @@
+package audio
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// Serves clips from the store under Route. Range requests are supported, so players can seek without
+// downloading the whole clip
+func Handler(store *FileStore) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		name := strings.TrimPrefix(r.URL.Path, Route)
+		file, err := store.Open(name)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				log.Printf("opening audio %s failed: %v", name, err)
+			}
+			http.NotFound(w, r)
+			return
+		}
+		defer file.Close()
+
+		info, err := file.Stat()
+		if err != nil {
+			log.Printf("opening audio %s failed: %v", name, err)
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", contentTypes[strings.ToLower(filepath.Ext(name))])
+		//clips are never changed, a new upload gets a new name
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		http.ServeContent(w, r, name, info.ModTime(), file)
+	})
+}
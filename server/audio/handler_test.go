@@ -0,0 +1,76 @@
+package audio
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_RangeRequest_ShouldServePartOfClip(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	assert.NoError(t, err)
+	assert.NoError(t, store.Save("rower.mp3", strings.NewReader("0123456789")))
+
+	req := httptest.NewRequest(http.MethodGet, Route+"rower.mp3", nil)
+	req.Header.Set("Range", "bytes=2-5")
+	rec := httptest.NewRecorder()
+
+	Handler(store).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusPartialContent, rec.Code)
+	assert.Equal(t, "audio/mpeg", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "bytes 2-5/10", rec.Header().Get("Content-Range"))
+	assert.Equal(t, "2345", rec.Body.String())
+}
+
+func TestHandler_PathOutsideRoot_ShouldNotBeFound(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "secret.mp3"), []byte("secret"), 0o644))
+	store, err := NewFileStore(filepath.Join(dir, "clips"))
+	assert.NoError(t, err)
+
+	for _, path := range []string{Route + "../secret.mp3", Route + "..", Route} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, Route, nil)
+		//paths are set directly, a client could send them without cleaning
+		req.URL.Path = path
+
+		Handler(store).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code, path)
+	}
+}
+
+func TestFileStore_Delete_ShouldRemoveClip(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	assert.NoError(t, err)
+	assert.NoError(t, store.Save("kot.ogg", strings.NewReader("meow")))
+
+	file, err := store.Open("kot.ogg")
+	assert.NoError(t, err)
+	content, _ := io.ReadAll(file)
+	file.Close()
+	assert.Equal(t, "meow", string(content))
+
+	assert.NoError(t, store.Delete("kot.ogg"))
+	assert.NoError(t, store.Delete("kot.ogg"))
+	_, err = store.Open("kot.ogg")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestExtension_ShouldAcceptOnlyAudioFormats(t *testing.T) {
+	ext, ok := Extension("Rower.MP3")
+	assert.True(t, ok)
+	assert.Equal(t, ".mp3", ext)
+
+	_, ok = Extension("rower.txt")
+	assert.False(t, ok)
+	_, ok = Extension("rower")
+	assert.False(t, ok)
+}
@@ -0,0 +1,110 @@
+package audio
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Route the clips are served from, a clip named 3f2a.mp3 has the URL /audio/3f2a.mp3
+const Route = "/audio/"
+
+// Content types of the supported clips by their extension
+var contentTypes = map[string]string{
+	".mp3":  "audio/mpeg",
+	".ogg":  "audio/ogg",
+	".opus": "audio/ogg",
+	".wav":  "audio/wav",
+	".m4a":  "audio/mp4",
+	".webm": "audio/webm",
+	".flac": "audio/flac",
+}
+
+// Returns the lowercase extension of an uploaded file, false if it isn't a supported audio format
+func Extension(filename string) (string, bool) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	_, ok := contentTypes[ext]
+	return ext, ok
+}
+
+// Returns a new random clip name with given extension, so clips of words with the same text don't collide
+func NewName(ext string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b) + ext, nil
+}
+
+func URL(name string) string {
+	return Route + name
+}
+
+// Keeps clips as files in a single directory of the local filesystem
+type FileStore struct {
+	root string
+}
+
+// Creates the store, making the root directory if it doesn't exist
+func NewFileStore(root string) (*FileStore, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStore{root: root}, nil
+}
+
+// Names come from URLs too, so only plain file names are accepted, never paths leading out of the root
+func (s *FileStore) path(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", fs.ErrNotExist
+	}
+	return filepath.Join(s.root, name), nil
+}
+
+// Writes the clip into a temporary file first, so a failed upload never leaves a partial clip under its name
+func (s *FileStore) Save(name string, r io.Reader) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.root, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *FileStore) Open(name string) (*os.File, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// Deletes the clip, deleting a clip which doesn't exist isn't an error
+func (s *FileStore) Delete(name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package database
+
+import (
+	"errors"
+	"strings"
+
+	dbmodels "github.com/staszkiet/DictionaryGolang/server/database/models"
+	customerrors "github.com/staszkiet/DictionaryGolang/server/errors"
+	"github.com/staszkiet/DictionaryGolang/server/graph/model"
+)
+
+// Collection names keep the case they were given in, only surrounding spaces are removed
+func collectionName(name string) (string, error) {
+	trimmed := strings.TrimSpace(name)
+	if trimmed == "" {
+		return "", customerrors.InvalidNameError{Name: name}
+	}
+	return trimmed, nil
+}
+
+func (r *DictionaryService) CreateCollection(name string) (*model.Collection, error) {
+	trimmed, err := collectionName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := dbmodels.Collection{Name: trimmed}
+	if err := r.repository.AddCollection(&collection); err != nil {
+		return nil, err
+	}
+	return dbmodels.DBCollectionToGQLCollection(&collection), nil
+}
+
+func (r *DictionaryService) Collection(name string) (*model.Collection, error) {
+	var collection dbmodels.Collection
+	if err := r.repository.GetCollection(strings.TrimSpace(name), &collection); err != nil {
+		return nil, err
+	}
+	return dbmodels.DBCollectionToGQLCollection(&collection), nil
+}
+
+// Returns every collection with its translations, in alphabetical order
+func (r *DictionaryService) Collections() ([]*model.Collection, error) {
+	var collections []dbmodels.Collection
+	if err := r.repository.ListCollections(&collections); err != nil {
+		return nil, err
+	}
+
+	result := []*model.Collection{}
+	for _, c := range collections {
+		result = append(result, dbmodels.DBCollectionToGQLCollection(&c))
+	}
+	return result, nil
+}
+
+func (r *DictionaryService) RenameCollection(name string, newName string) (*model.Collection, error) {
+	trimmed, err := collectionName(newName)
+	if err != nil {
+		return nil, err
+	}
+
+	var collection dbmodels.Collection
+	if err := r.repository.GetCollection(strings.TrimSpace(name), &collection); err != nil {
+		return nil, err
+	}
+
+	if err := r.repository.RenameCollection(&collection, trimmed); err != nil {
+		return nil, err
+	}
+	collection.Name = trimmed
+	return dbmodels.DBCollectionToGQLCollection(&collection), nil
+}
+
+// Deletes a collection and returns it as it was before. Its translations stay in the dictionary
+func (r *DictionaryService) DeleteCollection(name string) (*model.Collection, error) {
+	var collection dbmodels.Collection
+
+	_, err := r.repository.WithTransaction(func(txRepo IRepository) error {
+		if err := txRepo.GetCollection(strings.TrimSpace(name), &collection); err != nil {
+			return err
+		}
+		return txRepo.DeleteCollection(&collection)
+	}, false, false)
+
+	if err != nil {
+		return nil, err
+	}
+	return dbmodels.DBCollectionToGQLCollection(&collection), nil
+}
+
+// Fills the collection and the translation of item errors returned by the repository
+func collectionItemError(err error, name string, text string, translation string) error {
+	if errors.As(err, &customerrors.CollectionItemExistsError{}) {
+		return customerrors.CollectionItemExistsError{Collection: name, Word: text, Translation: translation}
+	}
+	if errors.As(err, &customerrors.CollectionItemNotExistsError{}) {
+		return customerrors.CollectionItemNotExistsError{Collection: name, Word: text, Translation: translation}
+	}
+	return err
+}
+
+// Puts a translation at the end of a collection
+func (r *DictionaryService) AddToCollection(name string, text string, translation string) (*model.Collection, error) {
+	name = strings.TrimSpace(name)
+
+	_, err := r.repository.WithTransaction(func(txRepo IRepository) error {
+		var collection dbmodels.Collection
+		if err := txRepo.GetCollection(name, &collection); err != nil {
+			return err
+		}
+
+		var dbtranslation dbmodels.Translation
+		if err := txRepo.GetTranslation(text, translation, &dbtranslation); err != nil {
+			return err
+		}
+
+		item := dbmodels.CollectionItem{CollectionID: collection.ID, TranslationID: dbtranslation.ID}
+		return collectionItemError(txRepo.AddCollectionItem(&item), name, text, translation)
+	}, false, false)
+
+	if err != nil {
+		return nil, err
+	}
+	return r.Collection(name)
+}
+
+// Takes a translation out of a collection, the translation stays in the dictionary
+func (r *DictionaryService) RemoveFromCollection(name string, text string, translation string) (*model.Collection, error) {
+	name = strings.TrimSpace(name)
+
+	_, err := r.repository.WithTransaction(func(txRepo IRepository) error {
+		var collection dbmodels.Collection
+		if err := txRepo.GetCollection(name, &collection); err != nil {
+			return err
+		}
+
+		var dbtranslation dbmodels.Translation
+		if err := txRepo.GetTranslation(text, translation, &dbtranslation); err != nil {
+			return err
+		}
+
+		return collectionItemError(txRepo.DeleteCollectionItem(collection.ID, dbtranslation.ID), name, text, translation)
+	}, false, false)
+
+	if err != nil {
+		return nil, err
+	}
+	return r.Collection(name)
+}
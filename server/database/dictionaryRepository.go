@@ -2,41 +2,216 @@ package database
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	dbmodels "github.com/staszkiet/DictionaryGolang/server/database/models"
 	customerrors "github.com/staszkiet/DictionaryGolang/server/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// Describes which page of words should be fetched by ListWords
+type WordsQuery struct {
+	Prefix     string
+	After      string
+	Descending bool
+	Limit      int
+	Grammar    GrammarFilter
+	Tag        string
+}
+
+// Describes which tables full-text search should look into
+type SearchQuery struct {
+	Text         string
+	Words        bool
+	Translations bool
+	Sentences    bool
+	Limit        int
+	Grammar      GrammarFilter
+	Tag          string
+}
+
+// Limits listed or searched words to given grammatical metadata, empty fields match every word
+type GrammarFilter struct {
+	PartOfSpeech string
+	Gender       string
+	Aspect       string
+	Countability string
+	Label        string
+	Domain       string
+}
+
+// Returns SQL conditions of the filter, each with a single argument, for words aliased as words. Countability, label and
+// domain are checked on translations aliased as translations, or on any translation of the word when translations is empty
+func (f GrammarFilter) conditions(words string, translations string) ([]string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	for _, c := range []struct{ column, value string }{{"part_of_speech", f.PartOfSpeech}, {"gender", f.Gender}, {"aspect", f.Aspect}} {
+		if c.value != "" {
+			conditions = append(conditions, words+"."+c.column+" = ?")
+			args = append(args, c.value)
+		}
+	}
+
+	//labels are separated by spaces, so padding them matches whole labels only
+	for _, c := range []struct{ condition, value, arg string }{
+		{"%s.countability = ?", f.Countability, f.Countability},
+		{"' ' || %s.labels || ' ' LIKE ?", f.Label, "% " + f.Label + " %"},
+		{"%s.domain = ?", f.Domain, f.Domain},
+	} {
+		if c.value == "" {
+			continue
+		}
+		if translations != "" {
+			conditions = append(conditions, fmt.Sprintf(c.condition, translations))
+		} else {
+			conditions = append(conditions, "EXISTS (SELECT 1 FROM translations c WHERE c.word_id = "+words+".id AND "+fmt.Sprintf(c.condition, "c")+")")
+		}
+		args = append(args, c.arg)
+	}
+	return conditions, args
+}
+
+// Condition limiting words aliased as words to the ones tagged with the tag given as its argument
+func tagCondition(words string) string {
+	return "EXISTS (SELECT 1 FROM word_tags wt JOIN tags tg ON tg.id = wt.tag_id WHERE wt.word_id = " + words + ".id AND tg.name = ?)"
+}
+
+// Languages of headwords and of their translations, as values of the GraphQL Language enum
+type LanguagePair struct {
+	Source string
+	Target string
+}
+
+// Pair of the dictionary before other languages were added, used when the client doesn't choose one
+var DefaultLanguagePair = LanguagePair{Source: "PL", Target: "EN"}
+
+// Number of rows inserted by a single statement, which keeps bulk inserts under the postgres parameters limit
+const insertBatchSize = 500
+
 type IRepository interface {
 	AddWord(word *dbmodels.Word) error
 	AddSentences(sentences []dbmodels.Sentence) error
 	AddTranslation(translation *dbmodels.Translation) error
+	AddWords(words []dbmodels.Word) error
+	AddTranslations(translations []dbmodels.Translation) error
 	GetWord(polish string, word *dbmodels.Word) error
+	GetWords(polish []string, words *[]dbmodels.Word) error
+	GetWordIgnoringDiacritics(polish string, word *dbmodels.Word) error
+	GetWordByForm(form string, word *dbmodels.Word) error
+	GetSimilarWords(polish string, limit int, words *[]string) error
+	ListWords(query WordsQuery, words *[]dbmodels.Word) error
+	GetWordsByEnglish(english string, words *[]dbmodels.Word) error
+	Search(query SearchQuery, hits *[]dbmodels.SearchHit) error
+	CompletePolish(prefix string, limit int, words *[]string) error
+	CompleteEnglish(prefix string, limit int, words *[]string) error
+	DueCards(now time.Time, limit int, cards *[]dbmodels.Card) error
+	GetCard(translationID uint, card *dbmodels.Card) error
+	SaveReview(review *dbmodels.ReviewState) error
+	RandomCards(limit int, cards *[]dbmodels.Card) error
+	RandomWords(limit int, words *[]dbmodels.Word) error
 	GetSentence(polish string, english string, sentence string, s *dbmodels.Sentence) error
 	DeleteSentence(s dbmodels.Sentence) error
 	GetTranslation(polish string, english string, translation *dbmodels.Translation) error
 	DeleteTranslation(translation *dbmodels.Translation) error
 	DeleteWord(polish string) error
+	DeleteWords(polish []string) error
 	UpdateWord(entity *dbmodels.Word, newPolish string) error
-	UpdateSentence(entity *dbmodels.Sentence, newSentence string) error
+	UpdateSentence(entity *dbmodels.Sentence, updated dbmodels.Sentence) error
 	UpdateTranslation(entity *dbmodels.Translation, newTranslation string) error
+	UpdateGrammar(word *dbmodels.Word) error
+	UpdateCountability(translation *dbmodels.Translation, countability *string) error
+	UpdatePronunciation(entity interface{}, pronunciation *dbmodels.Pronunciation) error
+	UpdateUsage(translation *dbmodels.Translation, usage *dbmodels.Usage) error
+	UpdatePositions(entity interface{}, ids []uint) error
+	AddInflection(inflection *dbmodels.Inflection) error
+	AddInflections(inflections []dbmodels.Inflection) error
+	DeleteInflection(polish string, form string, tags *string) error
+	AddWordRelation(relation *dbmodels.WordRelation, symmetric bool) error
+	AddTranslationRelation(relation *dbmodels.TranslationRelation, symmetric bool) error
+	DeleteWordRelation(kind string, wordID uint, relatedID uint, symmetric bool) error
+	DeleteTranslationRelation(kind string, translationID uint, relatedID uint, symmetric bool) error
+	GetOrCreateTags(names []string, tags *[]dbmodels.Tag) error
+	TagWord(word *dbmodels.Word, tags []dbmodels.Tag) error
+	UntagWord(word *dbmodels.Word, tags []dbmodels.Tag) error
+	GetTag(name string, tag *dbmodels.TagCount) error
+	ListTags(tags *[]dbmodels.TagCount) error
+	RenameTag(tagID uint, newName string) error
+	DeleteTag(tagID uint) error
+	AddCollection(collection *dbmodels.Collection) error
+	GetCollection(name string, collection *dbmodels.Collection) error
+	ListCollections(collections *[]dbmodels.Collection) error
+	RenameCollection(collection *dbmodels.Collection, newName string) error
+	DeleteCollection(collection *dbmodels.Collection) error
+	AddCollectionItem(item *dbmodels.CollectionItem) error
+	DeleteCollectionItem(collectionID uint, translationID uint) error
+	InPair(pair LanguagePair) IRepository
 	WithTransaction(fn func(tx IRepository) error, lock_words bool, lock_translations bool) (bool, error)
+	WithSavepoint(fn func(tx IRepository) error) error
 	withTx(tx *gorm.DB) IRepository
 }
 
+// Every query of the repository is limited to words of its language pair
 type dictionaryRepository struct {
-	db *gorm.DB
+	db   *gorm.DB
+	pair LanguagePair
 }
 
 func (r *dictionaryRepository) withTx(tx *gorm.DB) IRepository {
 	return &dictionaryRepository{
-		db: tx,
+		db:   tx,
+		pair: r.pair,
 	}
 }
 
+// Returns the repository of words of another language pair
+func (r *dictionaryRepository) InPair(pair LanguagePair) IRepository {
+	return &dictionaryRepository{
+		db:   r.db,
+		pair: pair,
+	}
+}
+
+// Starts a query of words of the language pair
+func (d *dictionaryRepository) pairWords() *gorm.DB {
+	return d.db.Model(&dbmodels.Word{}).Where("words.language = ? AND words.translation_language = ?", d.pair.Source, d.pair.Target)
+}
+
+// Sets the languages of a new word and of its translations
+func (d *dictionaryRepository) inPair(word *dbmodels.Word) {
+	word.Language = d.pair.Source
+	word.TranslationLanguage = d.pair.Target
+	for i := range word.Translations {
+		word.Translations[i].Language = d.pair.Target
+	}
+}
+
+// Starts a query of words with everything returned together with a word
+// Orders translations or sentences by their position, the ones added after the last reorder go last
+func byPosition(tx *gorm.DB) *gorm.DB {
+	return tx.Order("position NULLS LAST, id")
+}
+
+func (d *dictionaryRepository) words() *gorm.DB {
+	return d.pairWords().
+		Preload("Translations", byPosition).
+		Preload("Translations.Sentences", byPosition).
+		Preload("AspectPartner").
+		Preload("Inflections", func(tx *gorm.DB) *gorm.DB { return tx.Order("id") }).
+		Preload("Relations.Related").
+		Preload("RelatedBy.Word").
+		Preload("Translations.Relations.Related.Word").
+		Preload("Translations.RelatedBy.Translation.Word").
+		Preload("Tags", func(tx *gorm.DB) *gorm.DB { return tx.Order("name") })
+}
+
 func (d *dictionaryRepository) GetWord(polish string, word *dbmodels.Word) error {
-	err := d.db.Model(&dbmodels.Word{}).Preload("Translations.Sentences").Where("polish = ?", polish).First(word).Error
+	err := d.words().Where("polish = ?", polish).First(word).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return customerrors.WordNotExistsError{Word: polish}
@@ -46,19 +221,332 @@ func (d *dictionaryRepository) GetWord(polish string, word *dbmodels.Word) error
 	return nil
 }
 
+func (d *dictionaryRepository) GetWords(polish []string, words *[]dbmodels.Word) error {
+	if len(polish) == 0 {
+		return nil
+	}
+
+	err := d.words().Where("polish IN ?", polish).Find(words).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d *dictionaryRepository) GetWordIgnoringDiacritics(polish string, word *dbmodels.Word) error {
+	err := d.words().
+		Where("immutable_unaccent(polish) = immutable_unaccent(?)", polish).
+		Order("polish").
+		First(word).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return customerrors.WordNotExistsError{Word: polish}
+		}
+		return err
+	}
+	return nil
+}
+
+// Returns the word which has given inflected form. When forms of many words are spelled the same way,
+// the first of them in alphabetical order is returned
+func (d *dictionaryRepository) GetWordByForm(form string, word *dbmodels.Word) error {
+	inflected := d.db.Model(&dbmodels.Inflection{}).Select("word_id").Where("form = ?", form)
+
+	err := d.words().Where("id IN (?)", inflected).Order("polish").First(word).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return customerrors.WordNotExistsError{Word: form}
+		}
+		return err
+	}
+	return nil
+}
+
+func (d *dictionaryRepository) GetSimilarWords(polish string, limit int, words *[]string) error {
+	err := d.pairWords().
+		Where("immutable_unaccent(polish) % immutable_unaccent(?)", polish).
+		Order(clause.Expr{SQL: "similarity(immutable_unaccent(polish), immutable_unaccent(?)) DESC", Vars: []interface{}{polish}}).
+		Limit(limit).
+		Pluck("polish", words).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d *dictionaryRepository) ListWords(query WordsQuery, words *[]dbmodels.Word) error {
+	tx := d.words()
+
+	if query.Prefix != "" {
+		tx = tx.Where("polish LIKE ?", escapeLike(query.Prefix)+"%")
+	}
+
+	conditions, args := query.Grammar.conditions("words", "")
+	for i, condition := range conditions {
+		tx = tx.Where(condition, args[i])
+	}
+
+	if query.Tag != "" {
+		tx = tx.Where(tagCondition("words"), query.Tag)
+	}
+
+	if query.Descending {
+		if query.After != "" {
+			tx = tx.Where("polish < ?", query.After)
+		}
+		tx = tx.Order("polish DESC")
+	} else {
+		if query.After != "" {
+			tx = tx.Where("polish > ?", query.After)
+		}
+		tx = tx.Order("polish ASC")
+	}
+
+	if err := tx.Limit(query.Limit).Find(words).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d *dictionaryRepository) GetWordsByEnglish(english string, words *[]dbmodels.Word) error {
+	translated := d.db.Model(&dbmodels.Translation{}).Select("word_id").Where("english = ?", english)
+
+	err := d.words().
+		Where("id IN (?)", translated).
+		Order("polish").
+		Find(words).Error
+	if err != nil {
+		return err
+	}
+	if len(*words) == 0 {
+		return customerrors.EnglishWordNotExistsError{English: english}
+	}
+	return nil
+}
+
+func (d *dictionaryRepository) Search(query SearchQuery, hits *[]dbmodels.SearchHit) error {
+	parts := []string{}
+	args := []interface{}{}
+
+	//the text is the first argument of every part, followed by the language pair and arguments of the grammar and tag filters
+	filter := func(translations string) string {
+		conditions, filterArgs := query.Grammar.conditions("w", translations)
+		conditions = append([]string{"w.language = ?", "w.translation_language = ?"}, conditions...)
+		args = append(append(args, d.pair.Source, d.pair.Target), filterArgs...)
+		if query.Tag != "" {
+			conditions = append(conditions, tagCondition("w"))
+			args = append(args, query.Tag)
+		}
+		return strings.Join(append([]string{""}, conditions...), " AND ")
+	}
+
+	//every part names its columns, because the first one in the union may be any of them
+	if query.Words {
+		parts = append(parts, `SELECT 'word' AS kind, w.polish, '' AS english, '' AS sentence,
+			ts_rank(w.search_vector, q) AS rank,
+			ts_headline('simple', w.polish, q) AS snippet
+			FROM words w CROSS JOIN websearch_to_tsquery('simple', ?) q
+			WHERE w.search_vector @@ q`)
+		args = append(args, query.Text)
+		parts[len(parts)-1] += filter("")
+	}
+
+	if query.Translations {
+		parts = append(parts, `SELECT 'translation' AS kind, w.polish, t.english, '' AS sentence,
+			ts_rank(t.search_vector, q) AS rank,
+			ts_headline('english', t.english, q) AS snippet
+			FROM translations t JOIN words w ON w.id = t.word_id CROSS JOIN websearch_to_tsquery('english', ?) q
+			WHERE t.search_vector @@ q`)
+		args = append(args, query.Text)
+		parts[len(parts)-1] += filter("t")
+	}
+
+	if query.Sentences {
+		parts = append(parts, `SELECT 'sentence' AS kind, w.polish, t.english, s.sentence,
+			ts_rank(s.search_vector, q) AS rank,
+			ts_headline('english', s.sentence, q) AS snippet
+			FROM sentences s JOIN translations t ON t.id = s.translation_id JOIN words w ON w.id = t.word_id
+			CROSS JOIN websearch_to_tsquery('english', ?) q
+			WHERE s.search_vector @@ q`)
+		args = append(args, query.Text)
+		parts[len(parts)-1] += filter("t")
+	}
+
+	if len(parts) == 0 {
+		return nil
+	}
+
+	sql := strings.Join(parts, " UNION ALL ") + " ORDER BY rank DESC, polish LIMIT ?"
+	args = append(args, query.Limit)
+
+	if err := d.db.Raw(sql, args...).Scan(hits).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d *dictionaryRepository) CompletePolish(prefix string, limit int, words *[]string) error {
+	err := d.pairWords().
+		Where("polish LIKE ?", escapeLike(prefix)+"%").
+		Order("polish").
+		Limit(limit).
+		Pluck("polish", words).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d *dictionaryRepository) CompleteEnglish(prefix string, limit int, words *[]string) error {
+	err := d.db.Model(&dbmodels.Translation{}).
+		Distinct("english").
+		Where("word_id IN (?)", d.pairWords().Select("id")).
+		Where("english LIKE ?", escapeLike(prefix)+"%").
+		Order("english").
+		Limit(limit).
+		Pluck("english", words).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// escapes LIKE wildcards so that user input is matched literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// Returns translations due for a review at now, ordered by how long they are overdue, followed by translations
+// which were never reviewed, in the order they were added
+func (d *dictionaryRepository) DueCards(now time.Time, limit int, cards *[]dbmodels.Card) error {
+	var translations []dbmodels.Translation
+	err := d.db.Model(&dbmodels.Translation{}).
+		Preload("Sentences", byPosition).
+		Preload("Review").
+		Joins("LEFT JOIN review_states ON review_states.translation_id = translations.id").
+		Where("translations.word_id IN (?)", d.pairWords().Select("id")).
+		Where("review_states.translation_id IS NULL OR review_states.due <= ?", now).
+		Order("review_states.due IS NULL, review_states.due, translations.id").
+		Limit(limit).
+		Find(&translations).Error
+	if err != nil {
+		return err
+	}
+
+	return d.withWords(translations, cards)
+}
+
+func (d *dictionaryRepository) GetCard(translationID uint, card *dbmodels.Card) error {
+	var translation dbmodels.Translation
+	err := d.db.Model(&dbmodels.Translation{}).Preload("Sentences", byPosition).Preload("Review").First(&translation, translationID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return customerrors.CardNotExistsError{TranslationID: strconv.FormatUint(uint64(translationID), 10)}
+		}
+		return err
+	}
+
+	var cards []dbmodels.Card
+	if err := d.withWords([]dbmodels.Translation{translation}, &cards); err != nil {
+		return err
+	}
+	*card = cards[0]
+	return nil
+}
+
+// Makes cards of translations, reading words they translate
+func (d *dictionaryRepository) withWords(translations []dbmodels.Translation, cards *[]dbmodels.Card) error {
+	ids := make([]uint, 0, len(translations))
+	for _, t := range translations {
+		ids = append(ids, t.WordID)
+	}
+
+	var words []dbmodels.Word
+	if len(ids) > 0 {
+		if err := d.db.Model(&dbmodels.Word{}).Where("id IN ?", ids).Find(&words).Error; err != nil {
+			return err
+		}
+	}
+	byID := make(map[uint]dbmodels.Word, len(words))
+	for _, w := range words {
+		byID[w.ID] = w
+	}
+
+	*cards = make([]dbmodels.Card, 0, len(translations))
+	for _, t := range translations {
+		word := byID[t.WordID]
+		*cards = append(*cards, dbmodels.Card{Polish: word.Polish, Language: word.Language, Translation: t})
+	}
+	return nil
+}
+
+// Returns random translations as cards, used to generate quizzes
+func (d *dictionaryRepository) RandomCards(limit int, cards *[]dbmodels.Card) error {
+	var translations []dbmodels.Translation
+	err := d.db.Model(&dbmodels.Translation{}).
+		Preload("Sentences", byPosition).
+		Where("word_id IN (?)", d.pairWords().Select("id")).
+		Order("random()").Limit(limit).Find(&translations).Error
+	if err != nil {
+		return err
+	}
+
+	return d.withWords(translations, cards)
+}
+
+func (d *dictionaryRepository) RandomWords(limit int, words *[]dbmodels.Word) error {
+	err := d.pairWords().Preload("Translations").Order("random()").Limit(limit).Find(words).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Inserts the review state of a translation graded for the first time or replaces the previous one
+func (d *dictionaryRepository) SaveReview(review *dbmodels.ReviewState) error {
+	return d.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(review).Error
+}
+
 func (d *dictionaryRepository) AddWord(word *dbmodels.Word) error {
+	d.inPair(word)
 
 	if err := d.db.Create(word).Error; err != nil {
-		return err
+		return translateUniqueViolation(err)
 	}
 	return nil
 
 }
 
 func (d *dictionaryRepository) AddTranslation(translation *dbmodels.Translation) error {
+	translation.Language = d.pair.Target
 
 	if err := d.db.Create(translation).Error; err != nil {
-		return err
+		return translateUniqueViolation(err)
+	}
+	return nil
+
+}
+
+// Words are inserted together with their translations and sentences
+func (d *dictionaryRepository) AddWords(words []dbmodels.Word) error {
+	for i := range words {
+		d.inPair(&words[i])
+	}
+
+	if err := d.db.CreateInBatches(words, insertBatchSize).Error; err != nil {
+		return translateUniqueViolation(err)
+	}
+	return nil
+
+}
+
+func (d *dictionaryRepository) AddTranslations(translations []dbmodels.Translation) error {
+	for i := range translations {
+		translations[i].Language = d.pair.Target
+	}
+
+	if err := d.db.CreateInBatches(translations, insertBatchSize).Error; err != nil {
+		return translateUniqueViolation(err)
 	}
 	return nil
 
@@ -66,19 +554,285 @@ func (d *dictionaryRepository) AddTranslation(translation *dbmodels.Translation)
 
 func (d *dictionaryRepository) AddSentences(sentences []dbmodels.Sentence) error {
 
-	if err := d.db.Create(sentences).Error; err != nil {
+	if err := d.db.CreateInBatches(sentences, insertBatchSize).Error; err != nil {
+		return translateUniqueViolation(err)
+	}
+	return nil
+
+}
+
+// Returns InflectionExistsError without the word, which the caller knows
+func (d *dictionaryRepository) AddInflection(inflection *dbmodels.Inflection) error {
+
+	if err := d.db.Create(inflection).Error; err != nil {
+		if _, ok := uniqueViolation(err); ok {
+			return customerrors.InflectionExistsError{Form: inflection.Form, Tags: inflection.Tags}
+		}
+		return err
+	}
+	return nil
+}
+
+func (d *dictionaryRepository) AddInflections(inflections []dbmodels.Inflection) error {
+
+	if err := d.db.CreateInBatches(inflections, insertBatchSize).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// Deletes an inflected form of the word, only the one with given tags when tags aren't nil
+func (d *dictionaryRepository) DeleteInflection(polish string, form string, tags *string) error {
+	word := d.pairWords().Select("id").Where("polish = ?", polish)
+
+	tx := d.db.Where("word_id IN (?) AND form = ?", word, form)
+	if tags != nil {
+		tx = tx.Where("tags = ?", *tags)
+	}
+
+	result := tx.Delete(&dbmodels.Inflection{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return customerrors.InflectionNotExistsError{Word: polish, Form: form}
+	}
+	return nil
+}
+
+// Adds an edge between two words. Symmetric relations are stored once, so an edge created from the other end
+// counts as well. Returns RelationExistsError without the ends, which the caller knows
+func (d *dictionaryRepository) AddWordRelation(relation *dbmodels.WordRelation, symmetric bool) error {
+	if symmetric {
+		reverse := d.db.Model(&dbmodels.WordRelation{}).
+			Where("kind = ? AND word_id = ? AND related_id = ?", relation.Kind, relation.RelatedID, relation.WordID)
+		if err := relationExists(reverse, relation.Kind); err != nil {
+			return err
+		}
+	}
+	return createRelation(d.db, relation, relation.Kind)
+}
+
+// Adds an edge between two translations, works like AddWordRelation
+func (d *dictionaryRepository) AddTranslationRelation(relation *dbmodels.TranslationRelation, symmetric bool) error {
+	if symmetric {
+		reverse := d.db.Model(&dbmodels.TranslationRelation{}).
+			Where("kind = ? AND translation_id = ? AND related_id = ?", relation.Kind, relation.RelatedID, relation.TranslationID)
+		if err := relationExists(reverse, relation.Kind); err != nil {
+			return err
+		}
+	}
+	return createRelation(d.db, relation, relation.Kind)
+}
+
+// Deletes an edge between two words, edges of symmetric relations whichever end they were created from
+func (d *dictionaryRepository) DeleteWordRelation(kind string, wordID uint, relatedID uint, symmetric bool) error {
+	return deleteRelation(d.db, &dbmodels.WordRelation{}, "word_id", kind, wordID, relatedID, symmetric)
+}
+
+// Deletes an edge between two translations, works like DeleteWordRelation
+func (d *dictionaryRepository) DeleteTranslationRelation(kind string, translationID uint, relatedID uint, symmetric bool) error {
+	return deleteRelation(d.db, &dbmodels.TranslationRelation{}, "translation_id", kind, translationID, relatedID, symmetric)
+}
+
+// Returns RelationExistsError when the query finds any edge
+func relationExists(edges *gorm.DB, kind string) error {
+	var count int64
+	if err := edges.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return customerrors.RelationExistsError{Kind: kind}
+	}
+	return nil
+}
+
+func createRelation(db *gorm.DB, relation interface{}, kind string) error {
+	if err := db.Create(relation).Error; err != nil {
+		if _, ok := uniqueViolation(err); ok {
+			return customerrors.RelationExistsError{Kind: kind}
+		}
+		return err
+	}
+	return nil
+}
+
+// Deletes edges from the table of relation, column holds the id of the end the edge starts at
+func deleteRelation(db *gorm.DB, relation interface{}, column string, kind string, from uint, to uint, symmetric bool) error {
+	edge := "kind = ? AND " + column + " = ? AND related_id = ?"
+	args := []interface{}{kind, from, to}
+	if symmetric {
+		edge = "kind = ? AND ((" + column + " = ? AND related_id = ?) OR (" + column + " = ? AND related_id = ?))"
+		args = append(args, to, from)
+	}
+
+	result := db.Where(edge, args...).Delete(relation)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return customerrors.RelationNotExistsError{Kind: kind}
+	}
+	return nil
+}
+
+// Returns tags with given names, creating the ones which don't exist yet
+func (d *dictionaryRepository) GetOrCreateTags(names []string, tags *[]dbmodels.Tag) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	created := make([]dbmodels.Tag, len(names))
+	for i, name := range names {
+		created[i] = dbmodels.Tag{Name: name}
+	}
+	if err := d.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&created).Error; err != nil {
+		return err
+	}
+
+	return d.db.Where("name IN ?", names).Order("name").Find(tags).Error
+}
+
+func (d *dictionaryRepository) TagWord(word *dbmodels.Word, tags []dbmodels.Tag) error {
+	return d.db.Model(word).Association("Tags").Append(tags)
+}
+
+func (d *dictionaryRepository) UntagWord(word *dbmodels.Word, tags []dbmodels.Tag) error {
+	return d.db.Model(word).Association("Tags").Delete(tags)
+}
+
+// Starts a query of tags with the number of words tagged with each of them
+func (d *dictionaryRepository) tagCounts() *gorm.DB {
+	return d.db.Model(&dbmodels.Tag{}).
+		Select("tags.id, tags.name, COUNT(word_tags.word_id) AS words").
+		Joins("LEFT JOIN word_tags ON word_tags.tag_id = tags.id").
+		Group("tags.id, tags.name")
+}
+
+func (d *dictionaryRepository) GetTag(name string, tag *dbmodels.TagCount) error {
+	var tags []dbmodels.TagCount
+	if err := d.tagCounts().Where("tags.name = ?", name).Scan(&tags).Error; err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return customerrors.TagNotExistsError{Tag: name}
+	}
+	*tag = tags[0]
+	return nil
+}
+
+// Returns every tag with the number of words tagged with it, in alphabetical order
+func (d *dictionaryRepository) ListTags(tags *[]dbmodels.TagCount) error {
+	return d.tagCounts().Order("tags.name").Scan(tags).Error
+}
+
+func (d *dictionaryRepository) RenameTag(tagID uint, newName string) error {
+	if err := d.db.Model(&dbmodels.Tag{ID: tagID}).Update("name", newName).Error; err != nil {
+		if _, ok := uniqueViolation(err); ok {
+			return customerrors.TagExistsError{Tag: newName}
+		}
+		return err
+	}
+	return nil
+}
+
+func (d *dictionaryRepository) DeleteTag(tagID uint) error {
+	if err := d.db.Exec("DELETE FROM word_tags WHERE tag_id = ?", tagID).Error; err != nil {
+		return err
+	}
+	return d.db.Delete(&dbmodels.Tag{ID: tagID}).Error
+}
+
+func (d *dictionaryRepository) AddCollection(collection *dbmodels.Collection) error {
+	if err := d.db.Create(collection).Error; err != nil {
+		if _, ok := uniqueViolation(err); ok {
+			return customerrors.CollectionExistsError{Collection: collection.Name}
+		}
 		return err
 	}
 	return nil
+}
+
+// Starts a query of collections with their items in order
+func (d *dictionaryRepository) collections() *gorm.DB {
+	return d.db.Model(&dbmodels.Collection{}).
+		Preload("Items", func(tx *gorm.DB) *gorm.DB { return tx.Order("position") }).
+		Preload("Items.Translation.Word")
+}
 
+func (d *dictionaryRepository) GetCollection(name string, collection *dbmodels.Collection) error {
+	err := d.collections().Where("name = ?", name).First(collection).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return customerrors.CollectionNotExistsError{Collection: name}
+		}
+		return err
+	}
+	return nil
+}
+
+func (d *dictionaryRepository) ListCollections(collections *[]dbmodels.Collection) error {
+	return d.collections().Order("name").Find(collections).Error
+}
+
+func (d *dictionaryRepository) RenameCollection(collection *dbmodels.Collection, newName string) error {
+	if err := d.db.Model(collection).Update("name", newName).Error; err != nil {
+		if _, ok := uniqueViolation(err); ok {
+			return customerrors.CollectionExistsError{Collection: newName}
+		}
+		return err
+	}
+	return nil
+}
+
+func (d *dictionaryRepository) DeleteCollection(collection *dbmodels.Collection) error {
+	return d.db.Delete(collection).Error
+}
+
+// Puts the item after the last item of its collection. Returns CollectionItemExistsError with the collection
+// and the translation left for the caller to fill
+func (d *dictionaryRepository) AddCollectionItem(item *dbmodels.CollectionItem) error {
+	var last int
+	err := d.db.Model(&dbmodels.CollectionItem{}).
+		Select("COALESCE(MAX(position), 0)").
+		Where("collection_id = ?", item.CollectionID).
+		Scan(&last).Error
+	if err != nil {
+		return err
+	}
+
+	item.Position = last + 1
+	if err := d.db.Create(item).Error; err != nil {
+		if _, ok := uniqueViolation(err); ok {
+			return customerrors.CollectionItemExistsError{}
+		}
+		return err
+	}
+	return nil
+}
+
+// Returns CollectionItemNotExistsError with the collection and the translation left for the caller to fill
+func (d *dictionaryRepository) DeleteCollectionItem(collectionID uint, translationID uint) error {
+	result := d.db.Where("collection_id = ? AND translation_id = ?", collectionID, translationID).Delete(&dbmodels.CollectionItem{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return customerrors.CollectionItemNotExistsError{}
+	}
+	return nil
 }
 
 func (d *dictionaryRepository) GetSentence(polish string, english string, sentence string, s *dbmodels.Sentence) error {
 
 	err := d.db.Joins("JOIN translations ON sentences.translation_id = translations.id").
 		Joins("JOIN words ON words.id = translations.word_id").
-		Where("words.polish = ? AND translations.english = ? AND sentences.sentence = ?", polish, english, sentence).
-		First(s).Error
+		Where("words.language = ? AND words.translation_language = ?", d.pair.Source, d.pair.Target).
+		Where("words.polish = ? AND translations.english = ?", polish, english).
+		Where("sentences.sentence = ? OR sentences.original = ?", sentence, sentence).
+		//a sentence matching by itself wins over one matching by its original
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "sentences.sentence = ? DESC", Vars: []interface{}{sentence}}}).
+		Take(s).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return customerrors.SentenceNotExistsError{Word: polish, Translation: english, Sentence: sentence}
@@ -98,6 +852,7 @@ func (d *dictionaryRepository) DeleteSentence(s dbmodels.Sentence) error {
 func (d *dictionaryRepository) GetTranslation(polish string, english string, translation *dbmodels.Translation) error {
 
 	err := d.db.Joins("RIGHT JOIN words ON words.id = translations.word_id").
+		Where("words.language = ? AND words.translation_language = ?", d.pair.Source, d.pair.Target).
 		Where("words.polish = ? AND translations.english = ?", polish, english).
 		First(translation).Error
 	if err != nil {
@@ -131,7 +886,22 @@ func (d *dictionaryRepository) DeleteTranslation(translation *dbmodels.Translati
 
 func (d *dictionaryRepository) DeleteWord(polish string) error {
 
-	if err := d.db.Where("polish = ?", polish).Delete(&dbmodels.Word{}).Error; err != nil {
+	result := d.db.Where("language = ? AND translation_language = ? AND polish = ?", d.pair.Source, d.pair.Target, polish).
+		Delete(&dbmodels.Word{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return customerrors.CantDeleteWordError{Word: polish}
+	}
+	return nil
+}
+
+func (d *dictionaryRepository) DeleteWords(polish []string) error {
+
+	err := d.db.Where("language = ? AND translation_language = ? AND polish IN ?", d.pair.Source, d.pair.Target, polish).
+		Delete(&dbmodels.Word{}).Error
+	if err != nil {
 		return err
 	}
 	return nil
@@ -141,6 +911,9 @@ func (d *dictionaryRepository) UpdateWord(word *dbmodels.Word, newPolish string)
 
 	err := d.db.Model(word).Update("polish", newPolish).Error
 	if err != nil {
+		if _, ok := uniqueViolation(err); ok {
+			return customerrors.GetUpdatedEntityExistsError(word, newPolish)
+		}
 		return err
 	}
 	return nil
@@ -150,20 +923,118 @@ func (d *dictionaryRepository) UpdateTranslation(translation *dbmodels.Translati
 
 	err := d.db.Model(translation).Update("english", newTranslation).Error
 	if err != nil {
+		if _, ok := uniqueViolation(err); ok {
+			return customerrors.GetUpdatedEntityExistsError(translation, newTranslation)
+		}
 		return err
 	}
 	return nil
 }
 
-func (d *dictionaryRepository) UpdateSentence(sentence *dbmodels.Sentence, newSentence string) error {
+// Saves the part of speech, gender, aspect and aspect partner of the word, clearing those which are nil
+func (d *dictionaryRepository) UpdateGrammar(word *dbmodels.Word) error {
+	return d.db.Model(word).Updates(map[string]interface{}{
+		"part_of_speech":    word.PartOfSpeech,
+		"gender":            word.Gender,
+		"aspect":            word.Aspect,
+		"aspect_partner_id": word.AspectPartnerID,
+	}).Error
+}
+
+func (d *dictionaryRepository) UpdateCountability(translation *dbmodels.Translation, countability *string) error {
+	return d.db.Model(translation).Update("countability", countability).Error
+}
+
+// Saves the pronunciation of a word or a translation, clearing the fields which are nil
+func (d *dictionaryRepository) UpdatePronunciation(entity interface{}, pronunciation *dbmodels.Pronunciation) error {
+	return d.db.Model(entity).Updates(map[string]interface{}{
+		"ipa":    pronunciation.IPA,
+		"stress": pronunciation.Stress,
+		"audio":  pronunciation.Audio,
+	}).Error
+}
+
+// Numbers translations or sentences from 1 in the order of ids
+func (d *dictionaryRepository) UpdatePositions(entity interface{}, ids []uint) error {
+	for i, id := range ids {
+		if err := d.db.Model(entity).Where("id = ?", id).Update("position", i+1).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *dictionaryRepository) UpdateUsage(translation *dbmodels.Translation, usage *dbmodels.Usage) error {
+	return d.db.Model(translation).Updates(map[string]interface{}{
+		"labels": usage.Labels,
+		"domain": usage.Domain,
+		"note":   usage.Note,
+	}).Error
+}
+
+func (d *dictionaryRepository) UpdateSentence(sentence *dbmodels.Sentence, updated dbmodels.Sentence) error {
 
-	err := d.db.Model(sentence).Update("sentence", newSentence).Error
+	err := d.db.Model(sentence).Updates(map[string]interface{}{
+		"sentence":    updated.Sentence,
+		"original":    updated.Original,
+		"attribution": updated.Attribution,
+		"license":     updated.License,
+	}).Error
 	if err != nil {
+		if _, ok := uniqueViolation(err); ok {
+			return customerrors.GetUpdatedEntityExistsError(sentence, updated.Sentence)
+		}
 		return err
 	}
 	return nil
 }
 
+// SQLSTATE returned by postgres when a unique constraint is violated
+const uniqueViolationCode = "23505"
+
+func uniqueViolation(err error) (*pgconn.PgError, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		return pgErr, true
+	}
+	return nil, false
+}
+
+// Translates a unique violation on insert into the error of the entity which already exists.
+// Words are created together with their translations and sentences, so the violated table decides
+// the entity and the duplicated value is read from the detail, e.g. "Key (word_id, english)=(1, bike) already exists."
+func translateUniqueViolation(err error) error {
+	pgErr, ok := uniqueViolation(err)
+	if !ok {
+		return err
+	}
+
+	value := duplicatedValue(pgErr.Detail)
+	switch pgErr.TableName {
+	case "words":
+		return customerrors.GetEntityExistsError(&dbmodels.Word{Polish: value})
+	case "translations":
+		return customerrors.GetEntityExistsError(&dbmodels.Translation{English: value})
+	case "sentences":
+		return customerrors.GetEntityExistsError(&dbmodels.Sentence{Sentence: value})
+	}
+	return err
+}
+
+// Returns the value of the last key column from a unique violation detail
+func duplicatedValue(detail string) string {
+	columnsStart := strings.Index(detail, "(")
+	valuesStart := strings.Index(detail, ")=(")
+	valuesEnd := strings.LastIndex(detail, ") already exists")
+	if columnsStart < 0 || valuesStart < columnsStart || valuesEnd < valuesStart {
+		return ""
+	}
+
+	columns := strings.Count(detail[columnsStart:valuesStart], ", ") + 1
+	values := strings.SplitN(detail[valuesStart+len(")=("):valuesEnd], ", ", columns)
+	return values[len(values)-1]
+}
+
 func (d *dictionaryRepository) WithTransaction(fn func(repo IRepository) error, lock_words bool, lock_translations bool) (bool, error) {
 	err := d.db.Transaction(
 		func(tx *gorm.DB) error {
@@ -184,3 +1055,23 @@ func (d *dictionaryRepository) WithTransaction(fn func(repo IRepository) error,
 
 	return true, nil
 }
+
+// Savepoint of a single step of a transaction, which is rolled back without aborting the transaction
+const stepSavepoint = "step"
+
+// Runs fn under a savepoint of the transaction the repository works in. When fn fails, only its changes
+// are rolled back and its error is returned
+func (d *dictionaryRepository) WithSavepoint(fn func(repo IRepository) error) error {
+	if err := d.db.SavePoint(stepSavepoint).Error; err != nil {
+		return err
+	}
+
+	if err := fn(d); err != nil {
+		if rollbackErr := d.db.RollbackTo(stepSavepoint).Error; rollbackErr != nil {
+			return rollbackErr
+		}
+		return err
+	}
+
+	return d.db.Exec("RELEASE SAVEPOINT " + stepSavepoint).Error
+}
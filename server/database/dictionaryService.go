@@ -1,26 +1,51 @@
 package database
 
 import (
+	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	dbmodels "github.com/staszkiet/DictionaryGolang/server/database/models"
 	customerrors "github.com/staszkiet/DictionaryGolang/server/errors"
+	"github.com/staszkiet/DictionaryGolang/server/events"
+	"github.com/staszkiet/DictionaryGolang/server/importer"
+	"github.com/staszkiet/DictionaryGolang/server/study"
 
 	"github.com/staszkiet/DictionaryGolang/server/graph/model"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+const (
+	DefaultPageSize         = 10
+	MaxPageSize             = 100
+	SearchLimit             = 50
+	SuggestionsLimit        = 5
+	DefaultCompletionsLimit = 10
+	MaxCompletionsLimit     = 50
+	ExportBatchSize         = 100
+	DefaultDueCardsLimit    = 20
+	MaxDueCardsLimit        = 100
+)
+
 type DictionaryService struct {
 	repository IRepository
+	events     *events.Broker
+	audio      AudioStore
+	//nil when the service works on the default pair
+	pair *LanguagePair
 }
 
-// Creates new database service to handle operations on repository
-func NewDatabaseService() *DictionaryService {
+// Creates new database service to handle operations on repository, keeping audio clips in given store
+func NewDatabaseService(audio AudioStore) *DictionaryService {
 
 	var db *gorm.DB
 	err := godotenv.Load()
@@ -42,20 +67,51 @@ func NewDatabaseService() *DictionaryService {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
-	err = db.AutoMigrate(&dbmodels.Word{}, &dbmodels.Translation{}, &dbmodels.Sentence{})
+	err = Migrate(db)
 	if err != nil {
 		log.Fatal("Failed to migrate")
 	}
-	repo := &dictionaryRepository{db: db}
-	return &DictionaryService{repository: repo}
+	repo := &dictionaryRepository{db: db, pair: DefaultLanguagePair}
+	return &DictionaryService{repository: repo, events: events.NewBroker(), audio: audio}
+
+}
+
+// Returns the service working on words of the language pair, nil stands for the default polish-english pair.
+// Services of all pairs share the events, so subscribers are told about changes in every pair
+func (r *DictionaryService) InPair(pair *model.LanguagePair) (*DictionaryService, error) {
+	if pair == nil {
+		return r, nil
+	}
+	if pair.Source == pair.Target {
+		return nil, customerrors.InvalidLanguagePairError{Source: string(pair.Source), Target: string(pair.Target)}
+	}
+
+	languages := LanguagePair{Source: string(pair.Source), Target: string(pair.Target)}
+	return &DictionaryService{repository: r.repository.InPair(languages), events: r.events, audio: r.audio, pair: &languages}, nil
+}
+
+func (r *DictionaryService) languagePair() LanguagePair {
+	if r.pair == nil {
+		return DefaultLanguagePair
+	}
+	return *r.pair
+}
 
+// Tells subscribers about a change of a word, marked with the language pair so they can tell the dictionaries apart
+func (r *DictionaryService) publish(event *model.WordChangedEvent) {
+	pair := r.languagePair()
+	event.Source = model.Language(pair.Source)
+	event.Target = model.Language(pair.Target)
+	r.events.Publish(event)
 }
 
 // Adds a translation to the dictionary (whether given polish word exists or not). I translation to given word already exists then adds
 // sum of sentences to the translation
-func (r *DictionaryService) CreateWordOrAddTranslationOrSentence(polish string, translation model.NewTranslation) (bool, error) {
+func (r *DictionaryService) CreateWordOrAddTranslationOrSentence(polish string, translation model.NewTranslation) (*model.MutationResult, error) {
+
+	outcome := model.MutationOutcomeNoop
 
-	return r.repository.WithTransaction(func(txRepo IRepository) error {
+	_, err := r.repository.WithTransaction(func(txRepo IRepository) error {
 
 		var dbword dbmodels.Word
 		var dbtranslation dbmodels.Translation
@@ -70,49 +126,45 @@ func (r *DictionaryService) CreateWordOrAddTranslationOrSentence(polish string,
 					existingSentencesMap[s.Sentence] = true
 				}
 
-				for _, s := range translation.Sentences {
-					if !existingSentencesMap[s] {
-						newSentences = append(newSentences, dbmodels.Sentence{Sentence: s, TranslationID: dbtranslation.ID})
+				for _, s := range exampleSentences(translation) {
+					if !existingSentencesMap[s.Sentence] {
+						s.TranslationID = dbtranslation.ID
+						newSentences = append(newSentences, s)
 					}
 				}
 
 				if len(newSentences) > 0 {
-					txRepo.AddSentences(newSentences)
+					if err = txRepo.AddSentences(newSentences); err != nil {
+						return err
+					}
+					outcome = model.MutationOutcomeMerged
 				}
 
 				return nil
 			} else {
-				sentences := make([]dbmodels.Sentence, 0)
-
-				for _, s := range translation.Sentences {
-					sentences = append(sentences, dbmodels.Sentence{Sentence: s})
-				}
 				newTranslation := &dbmodels.Translation{
 					WordID:    dbword.ID,
 					English:   translation.English,
-					Sentences: sentences,
+					Sentences: exampleSentences(translation),
+					Usage:     usageOf(translation.Labels, translation.Domain, translation.Note),
 				}
 
 				if err = txRepo.AddTranslation(newTranslation); err != nil {
 					return err
 				}
 
+				outcome = model.MutationOutcomeMerged
 				return nil
 			}
 		}
 
 		if errors.Is(err, customerrors.WordNotExistsError{Word: polish}) {
-			sentences := make([]dbmodels.Sentence, 0)
-
-			for _, s := range translation.Sentences {
-				sentences = append(sentences, dbmodels.Sentence{Sentence: s})
-			}
-
 			var convertedTranslations []dbmodels.Translation
 
 			convertedTranslations = append(convertedTranslations, dbmodels.Translation{
 				English:   translation.English,
-				Sentences: sentences,
+				Sentences: exampleSentences(translation),
+				Usage:     usageOf(translation.Labels, translation.Domain, translation.Note),
 			})
 
 			word := &dbmodels.Word{
@@ -123,17 +175,25 @@ func (r *DictionaryService) CreateWordOrAddTranslationOrSentence(polish string,
 			if err := txRepo.AddWord(word); err != nil {
 				return err
 			}
+			outcome = model.MutationOutcomeCreated
 			return nil
 		}
 		return err
 
 	}, true, true)
+
+	if err != nil {
+		return nil, err
+	}
+	return r.mutationResult(polish, nil, outcome)
 }
 
 // Deletes an example sentence from given translation
-func (r *DictionaryService) DeleteSentence(polish string, english string, sentence string) (bool, error) {
+func (r *DictionaryService) DeleteSentence(polish string, english string, sentence string) (*model.MutationResult, error) {
 
-	return r.repository.WithTransaction(func(txRepo IRepository) error {
+	outcome := model.MutationOutcomeNoop
+
+	_, err := r.repository.WithTransaction(func(txRepo IRepository) error {
 		var s dbmodels.Sentence
 		err := txRepo.GetSentence(polish, english, sentence, &s)
 		if err != nil {
@@ -146,16 +206,24 @@ func (r *DictionaryService) DeleteSentence(polish string, english string, senten
 		if err := txRepo.DeleteSentence(s); err != nil {
 			return err
 		}
+		outcome = model.MutationOutcomeDeleted
 		return nil
 	}, false, false)
 
+	if err != nil {
+		return nil, err
+	}
+	return r.mutationResult(polish, nil, outcome)
 }
 
 // Deletes an english part of translation
 // (If it was the last translation attached to the polish part, the polish part also gets deleted)
-func (r *DictionaryService) DeleteTranslation(polish string, english string) (bool, error) {
+func (r *DictionaryService) DeleteTranslation(polish string, english string) (*model.MutationResult, error) {
+
+	outcome := model.MutationOutcomeNoop
+	removed := []string{}
 
-	return r.repository.WithTransaction(func(txRepo IRepository) error {
+	_, err := r.repository.WithTransaction(func(txRepo IRepository) error {
 		var translation dbmodels.Translation
 		err := txRepo.GetTranslation(polish, english, &translation)
 		if err != nil {
@@ -165,30 +233,73 @@ func (r *DictionaryService) DeleteTranslation(polish string, english string) (bo
 			return err
 		}
 
+		var word dbmodels.Word
+		if err := txRepo.GetWord(polish, &word); err != nil {
+			return err
+		}
+
 		if err := txRepo.DeleteTranslation(&translation); err != nil {
 			return err
 		}
+		outcome = model.MutationOutcomeDeleted
+
+		//the repository deletes the word together with its last translation
+		if len(word.Translations) == 1 {
+			removed = audioFiles(&word)
+		} else if translation.Pronunciation.Audio != nil {
+			removed = []string{*translation.Pronunciation.Audio}
+		}
 		return nil
 	}, false, false)
 
+	if err != nil {
+		return nil, err
+	}
+	r.removeAudio(removed)
+
+	result, err := r.mutationResult(polish, nil, outcome)
+	if err != nil {
+		return nil, err
+	}
+	//the word is gone when its last translation was deleted
+	if outcome == model.MutationOutcomeDeleted && result.Word == nil {
+		result.Outcome = model.MutationOutcomeCascadeDeleted
+	}
+	return result, nil
 }
 
-// Deletes whole translation (polish part, english counterparts and its sentences)
-func (r *DictionaryService) DeleteWord(polish string) (bool, error) {
+// Deletes whole translation (polish part, english counterparts and its sentences) with the audio clips
+func (r *DictionaryService) DeleteWord(polish string) (*model.MutationResult, error) {
 
-	if err := r.repository.DeleteWord(polish); err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return true, nil
+	removed := []string{}
+
+	_, err := r.repository.WithTransaction(func(txRepo IRepository) error {
+		var word dbmodels.Word
+		if err := txRepo.GetWord(polish, &word); err != nil {
+			return err
+		}
+
+		if err := txRepo.DeleteWord(polish); err != nil {
+			return err
 		}
-		return false, err
+		removed = audioFiles(&word)
+		return nil
+	}, false, false)
+
+	if err != nil {
+		if errors.Is(err, customerrors.WordNotExistsError{Word: polish}) || errors.Is(err, customerrors.CantDeleteWordError{Word: polish}) {
+			return &model.MutationResult{Outcome: model.MutationOutcomeNoop}, nil
+		}
+		return nil, err
 	}
-	return true, nil
+	r.removeAudio(removed)
+	return r.mutationResult(polish, nil, model.MutationOutcomeDeleted)
 }
 
 // Updates polish part of the translation
-func (r *DictionaryService) UpdateWord(polish string, newPolish string) (bool, error) {
+func (r *DictionaryService) UpdateWord(polish string, newPolish string) (*model.MutationResult, error) {
 
-	return r.repository.WithTransaction(func(txRepo IRepository) error {
+	_, err := r.repository.WithTransaction(func(txRepo IRepository) error {
 		var word dbmodels.Word
 		err := txRepo.GetWord(polish, &word)
 		if err != nil {
@@ -204,12 +315,16 @@ func (r *DictionaryService) UpdateWord(polish string, newPolish string) (bool, e
 		return nil
 	}, false, false)
 
+	if err != nil {
+		return nil, err
+	}
+	return r.mutationResult(newPolish, &polish, model.MutationOutcomeUpdated)
 }
 
 // Updates english part of the translation
-func (r *DictionaryService) UpdateTranslation(polish string, english string, newEnglish string) (bool, error) {
+func (r *DictionaryService) UpdateTranslation(polish string, english string, newEnglish string) (*model.MutationResult, error) {
 
-	return r.repository.WithTransaction(func(txRepo IRepository) error {
+	_, err := r.repository.WithTransaction(func(txRepo IRepository) error {
 		var translation dbmodels.Translation
 
 		err := txRepo.GetTranslation(polish, english, &translation)
@@ -227,12 +342,17 @@ func (r *DictionaryService) UpdateTranslation(polish string, english string, new
 		return nil
 	}, false, false)
 
+	if err != nil {
+		return nil, err
+	}
+	return r.mutationResult(polish, nil, model.MutationOutcomeUpdated)
 }
 
 // Updates an example sentence of given translation
-func (r *DictionaryService) UpdateSentence(polish string, english string, sentence string, newSentence string) (bool, error) {
+// Edits a sentence found by itself or by its original, see editedSentence
+func (r *DictionaryService) UpdateSentence(polish string, english string, sentence string, edit model.SentenceInput) (*model.MutationResult, error) {
 
-	return r.repository.WithTransaction(func(txRepo IRepository) error {
+	_, err := r.repository.WithTransaction(func(txRepo IRepository) error {
 
 		var s dbmodels.Sentence
 		err := txRepo.GetSentence(polish, english, sentence, &s)
@@ -243,16 +363,378 @@ func (r *DictionaryService) UpdateSentence(polish string, english string, senten
 			return err
 		}
 
-		err = txRepo.UpdateSentence(&s, newSentence)
+		err = txRepo.UpdateSentence(&s, editedSentence(s, edit))
 		if err != nil {
 			return err
 		}
 		return nil
 	}, false, false)
 
+	if err != nil {
+		return nil, err
+	}
+	return r.mutationResult(polish, nil, model.MutationOutcomeUpdated)
+}
+
+// Imports many entries in a single transaction, merging them into the dictionary the same way as CreateWordOrAddTranslationOrSentence.
+// In ATOMIC mode (the default) new words, translations and sentences are inserted in batches and the error of an invalid entry
+// fails the whole import. In BEST_EFFORT mode every entry is inserted under its own savepoint, so an invalid entry, as well as
+// one the database rejects, is reported as FAILED with its error code and the rest is imported
+func (r *DictionaryService) ImportWords(entries []*model.NewWordEntry, mode *model.ImportMode) ([]*model.ImportEntryResult, error) {
+	atomic := mode == nil || *mode == model.ImportModeAtomic
+
+	statuses := make([]model.ImportStatus, len(entries))
+	errorCodes := make([]*string, len(entries))
+
+	for i, entry := range entries {
+		if err := validateEntry(i, entry); err != nil {
+			if atomic {
+				return nil, err
+			}
+			statuses[i] = model.ImportStatusFailed
+			errorCodes[i] = errorCode(err)
+		}
+	}
+
+	if atomic {
+		if err := r.importEntries(entries, statuses, model.ConflictStrategyMerge); err != nil {
+			return nil, err
+		}
+	} else if err := r.importEachEntry(entries, statuses, errorCodes); err != nil {
+		return nil, err
+	}
+
+	results := make([]*model.ImportEntryResult, len(entries))
+	for i, entry := range entries {
+		results[i] = &model.ImportEntryResult{
+			Index:     int32(i),
+			Polish:    entry.Polish,
+			English:   entry.Translation.English,
+			Status:    statuses[i],
+			ErrorCode: errorCodes[i],
+		}
+	}
+	return results, nil
 }
 
-// Fetches data regarding given polish word
+// Imports rows of a CSV/TSV file. Malformed and invalid rows are rejected and reported with their line numbers,
+// the rest is imported in a single transaction according to the conflict strategy
+func (r *DictionaryService) ImportFile(file io.Reader, options *model.FileImportOptions) (*model.ImportReport, error) {
+	parseOptions, conflict, err := fileImportOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := importer.Parse(file, parseOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*model.NewWordEntry, len(rows))
+	statuses := make([]model.ImportStatus, len(rows))
+	errorCodes := make([]*string, len(rows))
+
+	for i, row := range rows {
+		entries[i] = row.Entry
+		if row.Err == nil {
+			row.Err = validateEntry(i, row.Entry)
+		}
+		if row.Err != nil {
+			if entries[i] == nil {
+				entries[i] = &model.NewWordEntry{Translation: &model.NewTranslation{}}
+			}
+			statuses[i] = model.ImportStatusFailed
+			errorCodes[i] = errorCode(row.Err)
+		}
+	}
+
+	if err := r.importEntries(entries, statuses, conflict); err != nil {
+		return nil, err
+	}
+
+	report := &model.ImportReport{Rows: make([]*model.ImportRowResult, len(rows))}
+	for i, row := range rows {
+		report.Rows[i] = &model.ImportRowResult{
+			Line:      int32(row.Line),
+			Polish:    entries[i].Polish,
+			English:   entries[i].Translation.English,
+			Status:    statuses[i],
+			ErrorCode: errorCodes[i],
+		}
+
+		switch statuses[i] {
+		case model.ImportStatusCreated:
+			report.Created++
+		case model.ImportStatusMerged:
+			report.Merged++
+		case model.ImportStatusOverwritten:
+			report.Overwritten++
+		case model.ImportStatusSkipped:
+			report.Skipped++
+		case model.ImportStatusFailed:
+			report.Rejected++
+		}
+	}
+	return report, nil
+}
+
+func fileImportOptions(options *model.FileImportOptions) (importer.Options, model.ConflictStrategy, error) {
+	parseOptions := importer.DefaultOptions()
+	conflict := model.ConflictStrategyMerge
+
+	if options == nil {
+		return parseOptions, conflict, nil
+	}
+
+	if options.Delimiter != nil {
+		delimiter := []rune(*options.Delimiter)
+		if len(delimiter) != 1 || delimiter[0] == '"' || delimiter[0] == '\n' || delimiter[0] == '\r' {
+			return parseOptions, conflict, customerrors.InvalidDelimiterError{Delimiter: *options.Delimiter}
+		}
+		parseOptions.Delimiter = delimiter[0]
+	}
+	if options.Header != nil {
+		parseOptions.Header = *options.Header
+	}
+	if options.PolishColumn != nil {
+		parseOptions.PolishColumn = *options.PolishColumn
+	}
+	if options.EnglishColumn != nil {
+		parseOptions.EnglishColumn = *options.EnglishColumn
+	}
+	parseOptions.SentenceColumns = options.SentenceColumns
+	if options.Conflict != nil {
+		conflict = *options.Conflict
+	}
+
+	return parseOptions, conflict, nil
+}
+
+// Merges entries into the dictionary in a single transaction and sets the status of every entry
+// which hasn't FAILED already
+func (r *DictionaryService) importEntries(entries []*model.NewWordEntry, statuses []model.ImportStatus, conflict model.ConflictStrategy) error {
+	polish := []string{}
+	for i, entry := range entries {
+		if statuses[i] != model.ImportStatusFailed {
+			polish = append(polish, entry.Polish)
+		}
+	}
+
+	var plan *importPlan
+	var existing []dbmodels.Word
+
+	_, err := r.repository.WithTransaction(func(txRepo IRepository) error {
+		existing = nil
+		if err := txRepo.GetWords(polish, &existing); err != nil {
+			return err
+		}
+
+		plan = newImportPlan(existing, conflict)
+		for i, entry := range entries {
+			if statuses[i] != model.ImportStatusFailed {
+				statuses[i] = plan.add(entry)
+			}
+		}
+
+		return plan.insert(txRepo)
+	}, true, true)
+
+	if err != nil {
+		return err
+	}
+
+	//overwritten words are deleted before their entries are inserted, so their clips are gone too
+	if conflict == model.ConflictStrategyOverwrite {
+		for _, w := range existing {
+			r.removeAudio(audioFiles(&w))
+		}
+	}
+
+	r.publishImported(plan.changes())
+	return nil
+}
+
+// Merges entries into the dictionary one by one in a single transaction. Every entry is inserted under a savepoint,
+// so an entry failing in the database is rolled back alone and gets FAILED status with the code of its error
+func (r *DictionaryService) importEachEntry(entries []*model.NewWordEntry, statuses []model.ImportStatus, errorCodes []*string) error {
+	var changes []*model.WordChangedEvent
+
+	_, err := r.repository.WithTransaction(func(txRepo IRepository) error {
+		changes = nil
+		for i, entry := range entries {
+			if statuses[i] == model.ImportStatusFailed {
+				continue
+			}
+
+			var plan *importPlan
+			err := txRepo.WithSavepoint(func(entryRepo IRepository) error {
+				var existing []dbmodels.Word
+				if err := entryRepo.GetWords([]string{entry.Polish}, &existing); err != nil {
+					return err
+				}
+				plan = newImportPlan(existing, model.ConflictStrategyMerge)
+				statuses[i] = plan.add(entry)
+				return plan.insert(entryRepo)
+			})
+			if err != nil {
+				statuses[i] = model.ImportStatusFailed
+				errorCodes[i] = errorCode(err)
+				continue
+			}
+			changes = append(changes, plan.changes()...)
+		}
+		return nil
+	}, true, true)
+
+	if err != nil {
+		return err
+	}
+
+	//a word imported by several entries is announced once, as created if the first of them created it
+	announced := map[string]bool{}
+	unique := []*model.WordChangedEvent{}
+	for _, event := range changes {
+		if !announced[event.Polish] {
+			announced[event.Polish] = true
+			unique = append(unique, event)
+		}
+	}
+	r.publishImported(unique)
+	return nil
+}
+
+// Tells subscribers about words changed by an import, with their state after the import
+func (r *DictionaryService) publishImported(changes []*model.WordChangedEvent) {
+	if !r.events.HasSubscribers() {
+		return
+	}
+	for _, event := range changes {
+		var word dbmodels.Word
+		if err := r.repository.GetWord(event.Polish, &word); err == nil {
+			event.Word = dbmodels.DBWordToGQLWord(&word)
+		}
+		r.publish(event)
+	}
+}
+
+func errorCode(err error) *string {
+	code := customerrors.Code(err)
+	return &code
+}
+
+func validateEntry(index int, entry *model.NewWordEntry) error {
+	if strings.TrimSpace(entry.Polish) == "" {
+		return customerrors.InvalidEntryError{Index: index, Field: "polish"}
+	}
+	if strings.TrimSpace(entry.Translation.English) == "" {
+		return customerrors.InvalidEntryError{Index: index, Field: "english"}
+	}
+	for _, s := range entry.Translation.Sentences {
+		if strings.TrimSpace(s) == "" {
+			return customerrors.InvalidEntryError{Index: index, Field: "sentences"}
+		}
+	}
+	for _, p := range entry.Translation.SentencePairs {
+		if strings.TrimSpace(p.Sentence) == "" {
+			return customerrors.InvalidEntryError{Index: index, Field: "sentencePairs"}
+		}
+	}
+	return nil
+}
+
+// Limits exported words, an empty field doesn't limit anything
+type ExportFilter struct {
+	// Language pair of the exported dictionary, polish-english when nil
+	Pair   *model.LanguagePair
+	Prefix string
+	Tag    string
+}
+
+// Calls fn with every word of the dictionary matching the filter in alphabetical order. Words are read in batches,
+// so memory use doesn't grow with the size of the dictionary
+func (r *DictionaryService) ExportWords(filter ExportFilter, fn func(word *model.Word) error) error {
+	dictionary, err := r.InPair(filter.Pair)
+	if err != nil {
+		return err
+	}
+
+	after := ""
+	for {
+		var words []dbmodels.Word
+		if err := dictionary.repository.ListWords(WordsQuery{Prefix: filter.Prefix, Tag: tagFilter(&filter.Tag), After: after, Limit: ExportBatchSize}, &words); err != nil {
+			return err
+		}
+
+		for i := range words {
+			if err := fn(dbmodels.DBWordToGQLWord(&words[i])); err != nil {
+				return err
+			}
+		}
+
+		if len(words) < ExportBatchSize {
+			return nil
+		}
+		after = words[len(words)-1].Polish
+	}
+}
+
+// Streams changes made to the dictionary of the language pair until ctx is done. If polish is given, only changes of that word are sent
+func (r *DictionaryService) WordChanged(ctx context.Context, polish *string) (<-chan *model.WordChangedEvent, error) {
+	changes := r.events.Subscribe(ctx)
+	pair := r.languagePair()
+
+	filtered := make(chan *model.WordChangedEvent)
+
+	go func() {
+		defer close(filtered)
+		for event := range changes {
+			if string(event.Source) != pair.Source || string(event.Target) != pair.Target {
+				continue
+			}
+			if polish != nil && event.Polish != *polish && (event.PreviousPolish == nil || *event.PreviousPolish != *polish) {
+				continue
+			}
+			select {
+			case filtered <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return filtered, nil
+}
+
+// Builds the result of a committed mutation from the current state of the word (nil when it no longer exists)
+// and tells subscribers about the change
+func (r *DictionaryService) mutationResult(polish string, previousPolish *string, outcome model.MutationOutcome) (*model.MutationResult, error) {
+	result := &model.MutationResult{Outcome: outcome}
+
+	var word dbmodels.Word
+	if err := r.repository.GetWord(polish, &word); err != nil {
+		if !errors.Is(err, customerrors.WordNotExistsError{Word: polish}) {
+			return nil, err
+		}
+	} else {
+		result.Word = dbmodels.DBWordToGQLWord(&word)
+	}
+
+	if outcome != model.MutationOutcomeNoop {
+		event := &model.WordChangedEvent{Kind: model.ChangeKindUpdated, Polish: polish, PreviousPolish: previousPolish, Word: result.Word}
+		if outcome == model.MutationOutcomeCreated {
+			event.Kind = model.ChangeKindCreated
+		} else if result.Word == nil {
+			event.Kind = model.ChangeKindDeleted
+		}
+		r.publish(event)
+	}
+
+	return result, nil
+}
+
+// Fetches data regarding given polish word. When there is no exact match it is looked up as an inflected form ("rowerem"
+// finds "rower", the matched forms are returned with the word), then polish diacritics are ignored ("zolw" finds "żółw"),
+// and if that fails too the returned error suggests the most similar words from the dictionary
 func (r *DictionaryService) SelectWord(polish string) (*model.Word, error) {
 	var word dbmodels.Word
 	var err error
@@ -261,8 +743,240 @@ func (r *DictionaryService) SelectWord(polish string) (*model.Word, error) {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, customerrors.WordNotExistsError{Word: polish}
 		}
-		return nil, err
+		if !errors.Is(err, customerrors.WordNotExistsError{Word: polish}) {
+			return nil, err
+		}
+
+		if err = r.repository.GetWordByForm(polish, &word); err == nil {
+			result := dbmodels.DBWordToGQLWord(&word)
+			result.MatchedForms = matchedForms(&word, polish)
+			return result, nil
+		}
+		if !errors.Is(err, customerrors.WordNotExistsError{Word: polish}) {
+			return nil, err
+		}
+
+		if err = r.repository.GetWordIgnoringDiacritics(polish, &word); err != nil {
+			if errors.Is(err, customerrors.WordNotExistsError{Word: polish}) {
+				return nil, r.wordNotExistsError(polish)
+			}
+			return nil, err
+		}
 	}
 
 	return dbmodels.DBWordToGQLWord(&word), nil
 }
+
+// Suggestions are only a hint for the user, so failing to find them doesn't hide the original error
+func (r *DictionaryService) wordNotExistsError(polish string) error {
+	var suggestions []string
+
+	if err := r.repository.GetSimilarWords(polish, SuggestionsLimit, &suggestions); err != nil || len(suggestions) == 0 {
+		return customerrors.WordNotExistsError{Word: polish}
+	}
+
+	return customerrors.WordSuggestionsError{WordNotExistsError: customerrors.WordNotExistsError{Word: polish}, Suggestions: suggestions}
+}
+
+// Fetches every polish word which has given english translation
+func (r *DictionaryService) SelectByEnglish(english string) ([]*model.Word, error) {
+	var words []dbmodels.Word
+
+	if err := r.repository.GetWordsByEnglish(english, &words); err != nil {
+		return nil, err
+	}
+
+	result := []*model.Word{}
+	for _, w := range words {
+		result = append(result, dbmodels.DBWordToGQLWord(&w))
+	}
+
+	return result, nil
+}
+
+// Runs full-text search over polish words, english translations and example sentences.
+// Results from all searched tables are ranked together, the best matches come first
+func (r *DictionaryService) Search(text string, scope *model.SearchScope, grammar *model.GrammarFilter, tag *string) ([]model.SearchResult, error) {
+	results := []model.SearchResult{}
+
+	if strings.TrimSpace(text) == "" {
+		return results, nil
+	}
+
+	query := SearchQuery{Text: text, Limit: SearchLimit, Words: true, Translations: true, Sentences: true, Grammar: grammarFilter(grammar), Tag: tagFilter(tag)}
+
+	if scope != nil && *scope != model.SearchScopeAll {
+		query.Words = *scope == model.SearchScopeWords
+		query.Translations = *scope == model.SearchScopeTranslations
+		query.Sentences = *scope == model.SearchScopeSentences
+	}
+
+	var hits []dbmodels.SearchHit
+	if err := r.repository.Search(query, &hits); err != nil {
+		return nil, err
+	}
+
+	for _, h := range hits {
+		results = append(results, dbmodels.DBSearchHitToGQLSearchResult(&h))
+	}
+
+	return results, nil
+}
+
+// Returns polish words or english translations starting with given prefix, in alphabetical order
+func (r *DictionaryService) Autocomplete(prefix string, language *model.Language, limit *int32) ([]string, error) {
+	completions := []string{}
+	count := DefaultCompletionsLimit
+
+	if limit != nil {
+		if *limit < 1 || *limit > MaxCompletionsLimit {
+			return nil, customerrors.InvalidPageSizeError{First: int(*limit), Max: MaxCompletionsLimit}
+		}
+		count = int(*limit)
+	}
+
+	var err error
+	//translations are completed in the target language, headwords in any other
+	if language != nil && string(*language) == r.languagePair().Target {
+		err = r.repository.CompleteEnglish(prefix, count, &completions)
+	} else {
+		err = r.repository.CompletePolish(prefix, count, &completions)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return completions, nil
+}
+
+// Returns cards to review now, see IRepository.DueCards for their order
+func (r *DictionaryService) DueCards(limit *int32) ([]*model.Card, error) {
+	count := DefaultDueCardsLimit
+	if limit != nil {
+		if *limit < 1 || *limit > MaxDueCardsLimit {
+			return nil, customerrors.InvalidPageSizeError{First: int(*limit), Max: MaxDueCardsLimit}
+		}
+		count = int(*limit)
+	}
+
+	now := time.Now()
+	var cards []dbmodels.Card
+	if err := r.repository.DueCards(now, count, &cards); err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Card, 0, len(cards))
+	for i := range cards {
+		result = append(result, dbmodels.DBCardToGQLCard(&cards[i], now))
+	}
+	return result, nil
+}
+
+// Schedules the next review of a card with SM-2, depending on how well it was recalled
+func (r *DictionaryService) GradeCard(translationID string, grade int32) (*model.Card, error) {
+	if grade < study.MinGrade || grade > study.MaxGrade {
+		return nil, customerrors.InvalidGradeError{Grade: int(grade), Min: study.MinGrade, Max: study.MaxGrade}
+	}
+
+	id, err := strconv.ParseUint(translationID, 10, 64)
+	if err != nil {
+		return nil, customerrors.CardNotExistsError{TranslationID: translationID}
+	}
+
+	var card dbmodels.Card
+	var review dbmodels.ReviewState
+	now := time.Now()
+
+	//the state is read and saved with translations locked, so grades given at the same time are applied one after another
+	//instead of overwriting each other
+	_, err = r.repository.WithTransaction(func(txRepo IRepository) error {
+		if err := txRepo.GetCard(uint(id), &card); err != nil {
+			return err
+		}
+
+		state := card.State(now).Grade(int(grade), now)
+		review = dbmodels.ReviewState{
+			TranslationID: card.Translation.ID,
+			EaseFactor:    state.EaseFactor,
+			Interval:      state.Interval,
+			Repetitions:   state.Repetitions,
+			Lapses:        state.Lapses,
+			Due:           state.Due,
+		}
+		return txRepo.SaveReview(&review)
+	}, false, true)
+
+	if err != nil {
+		return nil, err
+	}
+
+	card.Translation.Review = &review
+	return dbmodels.DBCardToGQLCard(&card, now), nil
+}
+
+// Fetches a page of dictionary words. Words are ordered alphabetically and the cursor of a page
+// is the last polish word it contains, so following pages are stable while the dictionary changes
+func (r *DictionaryService) ListWords(first *int32, after *string, prefix *string, order *model.SortOrder, grammar *model.GrammarFilter, tag *string) (*model.WordConnection, error) {
+	query := WordsQuery{Limit: DefaultPageSize, Grammar: grammarFilter(grammar), Tag: tagFilter(tag)}
+
+	if first != nil {
+		if *first < 1 || *first > MaxPageSize {
+			return nil, customerrors.InvalidPageSizeError{First: int(*first), Max: MaxPageSize}
+		}
+		query.Limit = int(*first)
+	}
+
+	if after != nil && *after != "" {
+		decoded, err := decodeCursor(*after)
+		if err != nil {
+			return nil, err
+		}
+		query.After = decoded
+	}
+
+	if prefix != nil {
+		query.Prefix = *prefix
+	}
+
+	if order != nil && *order == model.SortOrderDesc {
+		query.Descending = true
+	}
+
+	pageSize := query.Limit
+	//one more word is fetched to find out whether there is a next page
+	query.Limit++
+
+	var words []dbmodels.Word
+	if err := r.repository.ListWords(query, &words); err != nil {
+		return nil, err
+	}
+
+	connection := &model.WordConnection{Edges: []*model.WordEdge{}, PageInfo: &model.PageInfo{}}
+
+	if len(words) > pageSize {
+		words = words[:pageSize]
+		connection.PageInfo.HasNextPage = true
+	}
+
+	for _, w := range words {
+		connection.Edges = append(connection.Edges, &model.WordEdge{Cursor: encodeCursor(w.Polish), Node: dbmodels.DBWordToGQLWord(&w)})
+	}
+
+	if len(connection.Edges) > 0 {
+		connection.PageInfo.EndCursor = &connection.Edges[len(connection.Edges)-1].Cursor
+	}
+
+	return connection, nil
+}
+
+func encodeCursor(polish string) string {
+	return base64.URLEncoding.EncodeToString([]byte(polish))
+}
+
+func decodeCursor(cursor string) (string, error) {
+	decoded, err := base64.URLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", customerrors.InvalidCursorError{Cursor: cursor}
+	}
+	return string(decoded), nil
+}
@@ -0,0 +1,157 @@
+package database
+
+import (
+	dbmodels "github.com/staszkiet/DictionaryGolang/server/database/models"
+	customerrors "github.com/staszkiet/DictionaryGolang/server/errors"
+	"github.com/staszkiet/DictionaryGolang/server/graph/model"
+)
+
+// Replaces grammatical metadata of a word. Aspect partners are linked both ways: links which the new one replaces
+// are removed from both words, and the partner becomes a verb of the opposite aspect unless it already has them
+func (r *DictionaryService) SetGrammar(polish string, grammar model.GrammarInput) (*model.MutationResult, error) {
+	if err := validateGrammar(grammar); err != nil {
+		return nil, err
+	}
+
+	//other words whose aspect partner changed, subscribers are told about them too
+	linked := []string{}
+
+	_, err := r.repository.WithTransaction(func(txRepo IRepository) error {
+		var word dbmodels.Word
+		if err := txRepo.GetWord(polish, &word); err != nil {
+			return err
+		}
+
+		var partner dbmodels.Word
+		if grammar.AspectPartner != nil {
+			invalid := customerrors.InvalidAspectPartnerError{Word: polish, Partner: *grammar.AspectPartner}
+			if *grammar.AspectPartner == polish {
+				return invalid
+			}
+			if err := txRepo.GetWord(*grammar.AspectPartner, &partner); err != nil {
+				return err
+			}
+			if partner.PartOfSpeech != nil && *partner.PartOfSpeech != string(model.PartOfSpeechVerb) {
+				return invalid
+			}
+			if partner.Aspect != nil && grammar.Aspect != nil && *partner.Aspect == string(*grammar.Aspect) {
+				return invalid
+			}
+		}
+
+		for _, w := range []*dbmodels.Word{&word, &partner} {
+			previous := w.AspectPartner
+			if previous == nil || previous.Polish == polish || (grammar.AspectPartner != nil && previous.Polish == *grammar.AspectPartner) {
+				continue
+			}
+			if err := unlinkAspectPartner(txRepo, previous.Polish); err != nil {
+				return err
+			}
+			linked = append(linked, previous.Polish)
+		}
+
+		word.PartOfSpeech = dbmodels.EnumColumn(&grammar.PartOfSpeech)
+		word.Gender = dbmodels.EnumColumn(grammar.Gender)
+		word.Aspect = dbmodels.EnumColumn(grammar.Aspect)
+		word.AspectPartnerID = nil
+
+		if grammar.AspectPartner != nil {
+			word.AspectPartnerID = &partner.ID
+			partner.AspectPartnerID = &word.ID
+			if partner.PartOfSpeech == nil {
+				partner.PartOfSpeech = word.PartOfSpeech
+			}
+			if partner.Aspect == nil && grammar.Aspect != nil {
+				opposite := oppositeAspect(*grammar.Aspect)
+				partner.Aspect = dbmodels.EnumColumn(&opposite)
+			}
+			if err := txRepo.UpdateGrammar(&partner); err != nil {
+				return err
+			}
+			linked = append(linked, partner.Polish)
+		}
+
+		return txRepo.UpdateGrammar(&word)
+	}, false, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range linked {
+		if _, err := r.mutationResult(p, nil, model.MutationOutcomeUpdated); err != nil {
+			return nil, err
+		}
+	}
+	return r.mutationResult(polish, nil, model.MutationOutcomeUpdated)
+}
+
+// Gender only applies to nouns, aspect and aspect partners only to verbs
+func validateGrammar(grammar model.GrammarInput) error {
+	partOfSpeech := string(grammar.PartOfSpeech)
+
+	if grammar.Gender != nil && grammar.PartOfSpeech != model.PartOfSpeechNoun {
+		return customerrors.GrammarMismatchError{Field: "gender", PartOfSpeech: partOfSpeech}
+	}
+	if grammar.Aspect != nil && grammar.PartOfSpeech != model.PartOfSpeechVerb {
+		return customerrors.GrammarMismatchError{Field: "aspect", PartOfSpeech: partOfSpeech}
+	}
+	if grammar.AspectPartner != nil && grammar.PartOfSpeech != model.PartOfSpeechVerb {
+		return customerrors.GrammarMismatchError{Field: "aspectPartner", PartOfSpeech: partOfSpeech}
+	}
+	return nil
+}
+
+func unlinkAspectPartner(txRepo IRepository, polish string) error {
+	var word dbmodels.Word
+	if err := txRepo.GetWord(polish, &word); err != nil {
+		return err
+	}
+	word.AspectPartnerID = nil
+	return txRepo.UpdateGrammar(&word)
+}
+
+func oppositeAspect(aspect model.Aspect) model.Aspect {
+	if aspect == model.AspectPerfective {
+		return model.AspectImperfective
+	}
+	return model.AspectPerfective
+}
+
+// Sets countability of the english side of a translation, nil clears it
+func (r *DictionaryService) SetCountability(polish string, english string, countability *model.Countability) (*model.MutationResult, error) {
+
+	_, err := r.repository.WithTransaction(func(txRepo IRepository) error {
+		var translation dbmodels.Translation
+		if err := txRepo.GetTranslation(polish, english, &translation); err != nil {
+			return err
+		}
+		return txRepo.UpdateCountability(&translation, dbmodels.EnumColumn(countability))
+	}, false, false)
+
+	if err != nil {
+		return nil, err
+	}
+	return r.mutationResult(polish, nil, model.MutationOutcomeUpdated)
+}
+
+func grammarFilter(filter *model.GrammarFilter) GrammarFilter {
+	if filter == nil {
+		return GrammarFilter{}
+	}
+	return GrammarFilter{
+		PartOfSpeech: enumString(filter.PartOfSpeech),
+		Gender:       enumString(filter.Gender),
+		Aspect:       enumString(filter.Aspect),
+		Countability: enumString(filter.Countability),
+		Label:        enumString(filter.Label),
+		Domain:       enumString(usageDomain(filter.Domain)),
+	}
+}
+
+func enumString[T ~string](value *T) string {
+	if value == nil {
+		return ""
+	}
+	return string(*value)
+}
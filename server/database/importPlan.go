@@ -0,0 +1,195 @@
+package database
+
+import (
+	dbmodels "github.com/staszkiet/DictionaryGolang/server/database/models"
+	"github.com/staszkiet/DictionaryGolang/server/graph/model"
+)
+
+type importedTranslation struct {
+	id        uint //0 when the translation is created by the import
+	english   string
+	known     map[string]bool
+	sentences []dbmodels.Sentence //sentences which have to be inserted
+	usage     dbmodels.Usage      //of the first entry creating the translation
+}
+
+type importedWord struct {
+	id           uint //0 when the word is created by the import
+	polish       string
+	translations []*importedTranslation
+	byEnglish    map[string]*importedTranslation
+	changed      bool
+	frozen       bool //existing word which the import must not change
+	overwritten  bool //existing word which is replaced by the import
+}
+
+// Merges imported entries into the words which already exist, the same way CreateWordOrAddTranslationOrSentence does,
+// so that everything the import adds can be inserted in batches
+type importPlan struct {
+	words map[string]*importedWord
+	order []*importedWord
+}
+
+func newImportPlan(existing []dbmodels.Word, conflict model.ConflictStrategy) *importPlan {
+	plan := &importPlan{words: make(map[string]*importedWord)}
+
+	for _, w := range existing {
+		switch conflict {
+		case model.ConflictStrategySkip:
+			plan.word(w.Polish, w.ID).frozen = true
+			continue
+		case model.ConflictStrategyOverwrite:
+			//the word is deleted before inserting, so it is created again only with imported translations
+			plan.word(w.Polish, 0).overwritten = true
+			continue
+		}
+
+		word := plan.word(w.Polish, w.ID)
+		for _, t := range w.Translations {
+			translation := word.translation(t.English, t.ID)
+			for _, s := range t.Sentences {
+				translation.known[s.Sentence] = true
+			}
+		}
+	}
+
+	return plan
+}
+
+func (p *importPlan) word(polish string, id uint) *importedWord {
+	word := &importedWord{id: id, polish: polish, byEnglish: make(map[string]*importedTranslation)}
+	p.words[polish] = word
+	p.order = append(p.order, word)
+	return word
+}
+
+func (w *importedWord) translation(english string, id uint) *importedTranslation {
+	translation := &importedTranslation{id: id, english: english, known: make(map[string]bool)}
+	w.byEnglish[english] = translation
+	w.translations = append(w.translations, translation)
+	return translation
+}
+
+// Adds the entry to the plan and returns what happens to it
+func (p *importPlan) add(entry *model.NewWordEntry) model.ImportStatus {
+	status := model.ImportStatusSkipped
+
+	word, ok := p.words[entry.Polish]
+	if !ok {
+		word = p.word(entry.Polish, 0)
+		status = model.ImportStatusCreated
+	}
+	if word.frozen {
+		return model.ImportStatusSkipped
+	}
+
+	translation, ok := word.byEnglish[entry.Translation.English]
+	if !ok {
+		translation = word.translation(entry.Translation.English, 0)
+		translation.usage = usageOf(entry.Translation.Labels, entry.Translation.Domain, entry.Translation.Note)
+		if status == model.ImportStatusSkipped {
+			status = model.ImportStatusMerged
+		}
+	}
+
+	for _, s := range exampleSentences(*entry.Translation) {
+		if translation.known[s.Sentence] {
+			continue
+		}
+		translation.known[s.Sentence] = true
+		translation.sentences = append(translation.sentences, s)
+		if status == model.ImportStatusSkipped {
+			status = model.ImportStatusMerged
+		}
+	}
+
+	if status != model.ImportStatusSkipped {
+		word.changed = true
+		if word.overwritten {
+			status = model.ImportStatusOverwritten
+		}
+	}
+	return status
+}
+
+// Deletes overwritten words and inserts new words, translations of existing words and sentences of existing translations,
+// each kind in batches
+func (p *importPlan) insert(repo IRepository) error {
+	overwritten := []string{}
+	words := []dbmodels.Word{}
+	translations := []dbmodels.Translation{}
+	sentences := []dbmodels.Sentence{}
+
+	for _, w := range p.order {
+		if w.overwritten && w.changed {
+			overwritten = append(overwritten, w.polish)
+		}
+		if !w.changed {
+			continue
+		}
+
+		if w.id == 0 {
+			word := dbmodels.Word{Polish: w.polish}
+			for _, t := range w.translations {
+				word.Translations = append(word.Translations, dbmodels.Translation{English: t.english, Sentences: newSentences(t.sentences, 0), Usage: t.usage})
+			}
+			words = append(words, word)
+			continue
+		}
+
+		for _, t := range w.translations {
+			if t.id == 0 {
+				translations = append(translations, dbmodels.Translation{WordID: w.id, English: t.english, Sentences: newSentences(t.sentences, 0), Usage: t.usage})
+			} else {
+				sentences = append(sentences, newSentences(t.sentences, t.id)...)
+			}
+		}
+	}
+
+	if len(overwritten) > 0 {
+		if err := repo.DeleteWords(overwritten); err != nil {
+			return err
+		}
+	}
+	if len(words) > 0 {
+		if err := repo.AddWords(words); err != nil {
+			return err
+		}
+	}
+	if len(translations) > 0 {
+		if err := repo.AddTranslations(translations); err != nil {
+			return err
+		}
+	}
+	if len(sentences) > 0 {
+		if err := repo.AddSentences(sentences); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns events describing words changed by the import, without their current state
+func (p *importPlan) changes() []*model.WordChangedEvent {
+	changes := []*model.WordChangedEvent{}
+	for _, w := range p.order {
+		if !w.changed {
+			continue
+		}
+		kind := model.ChangeKindUpdated
+		if w.id == 0 && !w.overwritten {
+			kind = model.ChangeKindCreated
+		}
+		changes = append(changes, &model.WordChangedEvent{Kind: kind, Polish: w.polish})
+	}
+	return changes
+}
+
+func newSentences(sentences []dbmodels.Sentence, translationID uint) []dbmodels.Sentence {
+	converted := make([]dbmodels.Sentence, 0, len(sentences))
+	for _, s := range sentences {
+		s.TranslationID = translationID
+		converted = append(converted, s)
+	}
+	return converted
+}
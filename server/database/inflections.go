@@ -0,0 +1,174 @@
+package database
+
+import (
+	"errors"
+	"strings"
+
+	dbmodels "github.com/staszkiet/DictionaryGolang/server/database/models"
+	customerrors "github.com/staszkiet/DictionaryGolang/server/errors"
+	"github.com/staszkiet/DictionaryGolang/server/graph/model"
+)
+
+// Tags are stored lowercase and separated by single spaces, so ["Instrumental", "singular"] and
+// ["instrumental singular"] are the same tags
+func inflectionTags(tags []string) string {
+	return strings.ToLower(strings.Join(strings.Fields(strings.Join(tags, " ")), " "))
+}
+
+// Adds an inflected form of a word, which selectWord then resolves to the word
+func (r *DictionaryService) AddInflection(polish string, form string, tags []string) (*model.MutationResult, error) {
+
+	_, err := r.repository.WithTransaction(func(txRepo IRepository) error {
+		var word dbmodels.Word
+		if err := txRepo.GetWord(polish, &word); err != nil {
+			return err
+		}
+
+		inflection := dbmodels.Inflection{WordID: word.ID, Form: strings.TrimSpace(form), Tags: inflectionTags(tags)}
+		if err := txRepo.AddInflection(&inflection); err != nil {
+			var exists customerrors.InflectionExistsError
+			if errors.As(err, &exists) {
+				exists.Word = polish
+				return exists
+			}
+			return err
+		}
+		return nil
+	}, false, false)
+
+	if err != nil {
+		return nil, err
+	}
+	return r.mutationResult(polish, nil, model.MutationOutcomeUpdated)
+}
+
+// Deletes an inflected form of a word. Without tags every entry of the form is deleted
+func (r *DictionaryService) DeleteInflection(polish string, form string, tags []string) (*model.MutationResult, error) {
+	var normalized *string
+	if tags != nil {
+		joined := inflectionTags(tags)
+		normalized = &joined
+	}
+
+	if err := r.repository.DeleteInflection(polish, strings.TrimSpace(form), normalized); err != nil {
+		return nil, err
+	}
+	return r.mutationResult(polish, nil, model.MutationOutcomeDeleted)
+}
+
+// Adds many inflected forms in a single transaction. Entries with an empty lemma or form and entries of words
+// which aren't in the dictionary fail, forms which already exist are SKIPPED. In ATOMIC mode (the default)
+// a failed entry fails the whole import, in BEST_EFFORT mode the rest is imported
+func (r *DictionaryService) ImportInflections(entries []*model.NewInflection, mode *model.ImportMode) ([]*model.InflectionImportResult, error) {
+	atomic := mode == nil || *mode == model.ImportModeAtomic
+
+	results := make([]*model.InflectionImportResult, len(entries))
+	for i, entry := range entries {
+		results[i] = &model.InflectionImportResult{Index: int32(i), Lemma: entry.Lemma, Form: entry.Form, Status: model.ImportStatusCreated}
+	}
+
+	fail := func(i int, err error) error {
+		if atomic {
+			return customerrors.ImportFailedError{Index: i, Reason: customerrors.Code(err)}
+		}
+		results[i].Status = model.ImportStatusFailed
+		results[i].ErrorCode = errorCode(err)
+		return nil
+	}
+
+	lemmas := []string{}
+	for i, entry := range entries {
+		var err error
+		if strings.TrimSpace(entry.Lemma) == "" {
+			err = customerrors.InvalidEntryError{Index: i, Field: "lemma"}
+		} else if strings.TrimSpace(entry.Form) == "" {
+			err = customerrors.InvalidEntryError{Index: i, Field: "form"}
+		}
+		if err != nil {
+			if err := fail(i, err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		lemmas = append(lemmas, entry.Lemma)
+	}
+
+	changed := map[string]bool{}
+
+	_, err := r.repository.WithTransaction(func(txRepo IRepository) error {
+		var words []dbmodels.Word
+		if err := txRepo.GetWords(lemmas, &words); err != nil {
+			return err
+		}
+
+		type key struct {
+			wordID     uint
+			form, tags string
+		}
+		ids := make(map[string]uint, len(words))
+		known := map[key]bool{}
+		for _, w := range words {
+			ids[w.Polish] = w.ID
+			for _, i := range w.Inflections {
+				known[key{w.ID, i.Form, i.Tags}] = true
+			}
+		}
+
+		inflections := []dbmodels.Inflection{}
+		for i, entry := range entries {
+			if results[i].Status == model.ImportStatusFailed {
+				continue
+			}
+
+			id, ok := ids[entry.Lemma]
+			if !ok {
+				if err := fail(i, customerrors.WordNotExistsError{Word: entry.Lemma}); err != nil {
+					return err
+				}
+				continue
+			}
+
+			inflection := dbmodels.Inflection{WordID: id, Form: strings.TrimSpace(entry.Form), Tags: inflectionTags(entry.Tags)}
+			k := key{id, inflection.Form, inflection.Tags}
+			if known[k] {
+				results[i].Status = model.ImportStatusSkipped
+				continue
+			}
+			known[k] = true
+			changed[entry.Lemma] = true
+			inflections = append(inflections, inflection)
+		}
+
+		if len(inflections) > 0 {
+			return txRepo.AddInflections(inflections)
+		}
+		return nil
+	}, false, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if r.events.HasSubscribers() {
+		for polish := range changed {
+			event := &model.WordChangedEvent{Kind: model.ChangeKindUpdated, Polish: polish}
+			var word dbmodels.Word
+			if err := r.repository.GetWord(polish, &word); err == nil {
+				event.Word = dbmodels.DBWordToGQLWord(&word)
+			}
+			r.publish(event)
+		}
+	}
+	return results, nil
+}
+
+// Returns the inflections of the word which are spelled as form
+func matchedForms(word *dbmodels.Word, form string) []*model.Inflection {
+	matched := []*model.Inflection{}
+	for _, i := range word.Inflections {
+		if i.Form == form {
+			matched = append(matched, dbmodels.DBInflectionToGQLInflection(&i))
+		}
+	}
+	return matched
+}
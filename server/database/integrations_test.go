@@ -4,11 +4,15 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/staszkiet/DictionaryGolang/server/audio"
 	dbmodels "github.com/staszkiet/DictionaryGolang/server/database/models"
+	customerrors "github.com/staszkiet/DictionaryGolang/server/errors"
+	"github.com/staszkiet/DictionaryGolang/server/events"
 	"github.com/staszkiet/DictionaryGolang/server/graph/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -20,9 +24,10 @@ import (
 
 type DictionaryTestSuite struct {
 	suite.Suite
-	DB   *gorm.DB
-	repo dictionaryRepository
-	svc  DictionaryService
+	DB    *gorm.DB
+	repo  dictionaryRepository
+	svc   DictionaryService
+	clips *audio.FileStore
 }
 
 func (s *DictionaryTestSuite) SetupSuite() {
@@ -68,13 +73,18 @@ func (s *DictionaryTestSuite) SetupSuite() {
 		s.T().Fatalf("Failed to connect to test database: %v", err)
 	}
 
-	err = s.DB.AutoMigrate(&dbmodels.Word{}, &dbmodels.Translation{}, &dbmodels.Sentence{})
+	err = Migrate(s.DB)
 	if err != nil {
 		s.T().Fatalf("Failed to migrate schema: %v", err)
 	}
 
-	s.repo = dictionaryRepository{s.DB}
-	s.svc = DictionaryService{&s.repo}
+	s.clips, err = audio.NewFileStore(s.T().TempDir())
+	if err != nil {
+		s.T().Fatalf("Failed to create audio store: %v", err)
+	}
+
+	s.repo = dictionaryRepository{s.DB, DefaultLanguagePair}
+	s.svc = DictionaryService{repository: &s.repo, events: events.NewBroker(), audio: s.clips}
 }
 
 func (s *DictionaryTestSuite) SetupTest() {
@@ -288,3 +298,765 @@ func (s *DictionaryTestSuite) TestDeleteTranslationParallel_WhenItIsTheOnlyTrans
 	s.DB.Model(&dbmodels.Word{}).Where("polish = ?", baseWord).Count(&count)
 	assert.Equal(s.T(), int64(0), count)
 }
+
+func (s *DictionaryTestSuite) TestListWords_ShouldPaginateOverPrefixedWords() {
+
+	for _, polish := range []string{"kot", "koń", "krowa", "pies"} {
+		_, err := s.svc.CreateWordOrAddTranslationOrSentence(polish, model.NewTranslation{English: polish, Sentences: []string{}})
+		assert.NoError(s.T(), err)
+	}
+
+	first := int32(2)
+	prefix := "k"
+
+	page, err := s.svc.ListWords(&first, nil, &prefix, nil, nil, nil)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), page.Edges, 2)
+	assert.True(s.T(), page.PageInfo.HasNextPage)
+
+	page, err = s.svc.ListWords(&first, page.PageInfo.EndCursor, &prefix, nil, nil, nil)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), page.Edges, 1)
+	assert.False(s.T(), page.PageInfo.HasNextPage)
+}
+
+func (s *DictionaryTestSuite) TestSelectByEnglish_ShouldReturnEveryWordWithGivenTranslation() {
+
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{"I like my bike"}})
+	s.svc.CreateWordOrAddTranslationOrSentence("motocykl", model.NewTranslation{English: "bike", Sentences: []string{}})
+	s.svc.CreateWordOrAddTranslationOrSentence("kot", model.NewTranslation{English: "cat", Sentences: []string{}})
+
+	words, err := s.svc.SelectByEnglish("bike")
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), words, 2)
+	assert.Equal(s.T(), "motocykl", words[0].Polish)
+	assert.Equal(s.T(), "rower", words[1].Polish)
+	assert.Equal(s.T(), "I like my bike", words[1].Translations[0].Sentences[0].Sentence)
+
+	_, err = s.svc.SelectByEnglish("dog")
+	assert.Error(s.T(), err)
+}
+
+func (s *DictionaryTestSuite) TestSearch_ShouldRankMatchesFromAllTables() {
+
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{"I ride my bike every day"}})
+	s.svc.CreateWordOrAddTranslationOrSentence("kot", model.NewTranslation{English: "cat", Sentences: []string{"My cat hates riding"}})
+
+	results, err := s.svc.Search("bike", nil, nil, nil)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), results, 2)
+
+	scope := model.SearchScopeSentences
+	results, err = s.svc.Search("riding", &scope, nil, nil)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), results, 2)
+	for _, r := range results {
+		assert.IsType(s.T(), &model.SentenceHit{}, r)
+	}
+
+	scope = model.SearchScopeWords
+	results, err = s.svc.Search("rower", &scope, nil, nil)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "<b>rower</b>", results[0].(*model.WordHit).Snippet)
+}
+
+func (s *DictionaryTestSuite) TestSearch_OnlyTranslations_ShouldReturnRankedTranslationHits() {
+
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{"I ride my bike every day"}})
+
+	scope := model.SearchScopeTranslations
+	results, err := s.svc.Search("bike", &scope, nil, nil)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), results, 1)
+
+	hit := results[0].(*model.TranslationHit)
+	assert.Equal(s.T(), "rower", hit.Polish)
+	assert.Equal(s.T(), "bike", hit.English)
+	assert.Equal(s.T(), "<b>bike</b>", hit.Snippet)
+	assert.Greater(s.T(), hit.Rank, 0.0)
+}
+
+func (s *DictionaryTestSuite) TestSearch_OnlySentences_ShouldReturnRankedSentenceHits() {
+
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{"I ride my bike every day"}})
+
+	scope := model.SearchScopeSentences
+	results, err := s.svc.Search("ride", &scope, nil, nil)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), results, 1)
+
+	hit := results[0].(*model.SentenceHit)
+	assert.Equal(s.T(), "rower", hit.Polish)
+	assert.Equal(s.T(), "bike", hit.English)
+	assert.Equal(s.T(), "I ride my bike every day", hit.Sentence)
+	assert.Contains(s.T(), hit.Snippet, "<b>ride</b>")
+	assert.Greater(s.T(), hit.Rank, 0.0)
+}
+
+func (s *DictionaryTestSuite) TestSelectWord_ShouldIgnoreDiacriticsAndSuggestSimilarWords() {
+
+	s.svc.CreateWordOrAddTranslationOrSentence("żółw", model.NewTranslation{English: "turtle", Sentences: []string{}})
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{}})
+
+	word, err := s.svc.SelectWord("zolw")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "żółw", word.Polish)
+
+	_, err = s.svc.SelectWord("rowerr")
+	var suggestionsErr customerrors.WordSuggestionsError
+	assert.ErrorAs(s.T(), err, &suggestionsErr)
+	assert.Contains(s.T(), suggestionsErr.Suggestions, "rower")
+}
+
+func (s *DictionaryTestSuite) TestAutocomplete_ShouldCompleteWordsAndTranslations() {
+
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{}})
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bicycle", Sentences: []string{}})
+	s.svc.CreateWordOrAddTranslationOrSentence("motocykl", model.NewTranslation{English: "bike", Sentences: []string{}})
+	s.svc.CreateWordOrAddTranslationOrSentence("rowek", model.NewTranslation{English: "groove", Sentences: []string{}})
+
+	completions, err := s.svc.Autocomplete("row", nil, nil)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"rowek", "rower"}, completions)
+
+	language := model.LanguageEn
+	completions, err = s.svc.Autocomplete("bi", &language, nil)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"bicycle", "bike"}, completions)
+}
+
+func (s *DictionaryTestSuite) TestWordChanged_ShouldReceiveCommittedChangesOfWatchedWord() {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	polish := "rower"
+	changes, err := s.svc.WordChanged(ctx, &polish)
+	assert.NoError(s.T(), err)
+
+	s.svc.CreateWordOrAddTranslationOrSentence("kot", model.NewTranslation{English: "cat", Sentences: []string{}})
+	s.svc.CreateWordOrAddTranslationOrSentence(polish, model.NewTranslation{English: "bike", Sentences: []string{}})
+	s.svc.CreateWordOrAddTranslationOrSentence(polish, model.NewTranslation{English: "bicycle", Sentences: []string{}})
+	s.svc.DeleteWord(polish)
+
+	event := <-changes
+	assert.Equal(s.T(), model.ChangeKindCreated, event.Kind)
+	assert.Len(s.T(), event.Word.Translations, 1)
+
+	event = <-changes
+	assert.Equal(s.T(), model.ChangeKindUpdated, event.Kind)
+	assert.Len(s.T(), event.Word.Translations, 2)
+
+	event = <-changes
+	assert.Equal(s.T(), model.ChangeKindDeleted, event.Kind)
+	assert.Nil(s.T(), event.Word)
+}
+
+func (s *DictionaryTestSuite) TestMutations_ShouldReportWhatActuallyHappened() {
+
+	result, err := s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{}})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.MutationOutcomeCreated, result.Outcome)
+
+	result, err = s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{"I like my bike"}})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.MutationOutcomeMerged, result.Outcome)
+	assert.Len(s.T(), result.Word.Translations[0].Sentences, 1)
+
+	result, err = s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{"I like my bike"}})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.MutationOutcomeNoop, result.Outcome)
+
+	result, err = s.svc.DeleteTranslation("rower", "bike")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.MutationOutcomeCascadeDeleted, result.Outcome)
+	assert.Nil(s.T(), result.Word)
+
+	result, err = s.svc.DeleteWord("rower")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.MutationOutcomeNoop, result.Outcome)
+}
+
+func (s *DictionaryTestSuite) TestUpdateWord_IntoExistingWord_ShouldReturnWordExistsError() {
+
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{}})
+	s.svc.CreateWordOrAddTranslationOrSentence("rowerek", model.NewTranslation{English: "small bike", Sentences: []string{}})
+
+	_, err := s.svc.UpdateWord("rowerek", "rower")
+	assert.Equal(s.T(), customerrors.WordExistsError{Word: "rower"}, err)
+}
+
+func (s *DictionaryTestSuite) TestUpdateTranslation_IntoExistingTranslation_ShouldReturnTranslationExistsError() {
+
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{}})
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bicycle", Sentences: []string{}})
+
+	_, err := s.svc.UpdateTranslation("rower", "bicycle", "bike")
+	assert.Equal(s.T(), customerrors.TranslationExistsError{Translation: "bike"}, err)
+}
+
+func (s *DictionaryTestSuite) TestUpdateSentence_IntoExistingSentence_ShouldReturnSentenceExistsError() {
+
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{"I like my bike", "My bike is green"}})
+
+	_, err := s.svc.UpdateSentence("rower", "bike", "My bike is green", model.SentenceInput{Sentence: "I like my bike"})
+	assert.Equal(s.T(), customerrors.SentenceExistsError{Sentence: "I like my bike"}, err)
+}
+
+func (s *DictionaryTestSuite) TestSentencePairs_ShouldBeFoundByEitherSentence() {
+
+	original, license := "Lubię mój rower.", "CC BY 2.0 FR"
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{"My bike is green"}})
+	_, err := s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{}, SentencePairs: []*model.SentenceInput{{Sentence: "I like my bike", Original: &original, License: &license}}})
+	assert.NoError(s.T(), err)
+
+	word, err := s.svc.SelectWord("rower")
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), word.Translations[0].Sentences, 2)
+
+	_, err = s.svc.UpdateSentence("rower", "bike", original, model.SentenceInput{Sentence: "I love my bike"})
+	assert.NoError(s.T(), err)
+
+	word, err = s.svc.SelectWord("rower")
+	assert.NoError(s.T(), err)
+	for _, sentence := range word.Translations[0].Sentences {
+		if sentence.Original != nil {
+			assert.Equal(s.T(), "I love my bike", sentence.Sentence)
+			assert.Equal(s.T(), license, *sentence.License)
+		}
+	}
+
+	result, err := s.svc.DeleteSentence("rower", "bike", original)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.MutationOutcomeDeleted, result.Outcome)
+	assert.Len(s.T(), result.Word.Translations[0].Sentences, 1)
+}
+
+func (s *DictionaryTestSuite) TestReorder_WordShouldKeepOrderOfTranslationsAndSentences() {
+
+	for _, english := range []string{"zipper", "lock", "castle"} {
+		s.svc.CreateWordOrAddTranslationOrSentence("zamek", model.NewTranslation{English: english, Sentences: []string{}})
+	}
+	s.svc.CreateWordOrAddTranslationOrSentence("zamek", model.NewTranslation{English: "castle", Sentences: []string{"The castle is old.", "The castle stands on a hill."}})
+
+	_, err := s.svc.ReorderTranslations("zamek", []string{"castle", "lock"})
+	assert.NoError(s.T(), err)
+	_, err = s.svc.ReorderSentences("zamek", "castle", []string{"The castle stands on a hill."})
+	assert.NoError(s.T(), err)
+
+	//translations added after the reorder go last
+	s.svc.CreateWordOrAddTranslationOrSentence("zamek", model.NewTranslation{English: "bolt", Sentences: []string{}})
+
+	word, err := s.svc.SelectWord("zamek")
+	assert.NoError(s.T(), err)
+	texts := []string{}
+	for _, t := range word.Translations {
+		texts = append(texts, t.Text)
+	}
+	assert.Equal(s.T(), []string{"castle", "lock", "zipper", "bolt"}, texts)
+	assert.Equal(s.T(), "The castle stands on a hill.", word.Translations[0].Sentences[0].Sentence)
+	assert.Equal(s.T(), "The castle is old.", word.Translations[0].Sentences[1].Sentence)
+}
+
+func (s *DictionaryTestSuite) TestAddWord_ExistingWord_ShouldReturnWordExistsError() {
+
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{}})
+
+	err := s.repo.AddWord(&dbmodels.Word{Polish: "rower"})
+	assert.Equal(s.T(), customerrors.WordExistsError{Word: "rower"}, err)
+}
+
+func (s *DictionaryTestSuite) TestAddTranslation_ExistingTranslation_ShouldReturnTranslationExistsError() {
+
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{}})
+
+	var word dbmodels.Word
+	s.repo.GetWord("rower", &word)
+
+	err := s.repo.AddTranslation(&dbmodels.Translation{WordID: word.ID, English: "bike"})
+	assert.Equal(s.T(), customerrors.TranslationExistsError{Translation: "bike"}, err)
+}
+
+func (s *DictionaryTestSuite) TestCreateWord_DuplicatedSentence_ShouldReturnSentenceExistsError() {
+
+	_, err := s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{"I like my bike", "I like my bike"}})
+	assert.Equal(s.T(), customerrors.SentenceExistsError{Sentence: "I like my bike"}, err)
+
+	_, err = s.svc.SelectWord("rower")
+	assert.Error(s.T(), err)
+}
+
+func (s *DictionaryTestSuite) TestImportWords_ShouldMergeIntoExistingWords() {
+
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{"I like my bike"}})
+
+	entries := []*model.NewWordEntry{
+		{Polish: "kot", Translation: &model.NewTranslation{English: "cat", Sentences: []string{"My cat is black"}}},
+		{Polish: "rower", Translation: &model.NewTranslation{English: "bike", Sentences: []string{"I like my bike", "My bike is green"}}},
+		{Polish: "rower", Translation: &model.NewTranslation{English: "bicycle", Sentences: []string{}}},
+	}
+
+	results, err := s.svc.ImportWords(entries, nil)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.ImportStatusCreated, results[0].Status)
+	assert.Equal(s.T(), model.ImportStatusMerged, results[1].Status)
+	assert.Equal(s.T(), model.ImportStatusMerged, results[2].Status)
+
+	word, err := s.svc.SelectWord("rower")
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), word.Translations, 2)
+
+	word, err = s.svc.SelectWord("kot")
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), word.Translations[0].Sentences, 1)
+}
+
+func (s *DictionaryTestSuite) TestImportWords_BestEffortWithEntryRejectedByDatabase_ShouldImportTheRest() {
+
+	//postgres doesn't accept NUL characters in text, which validation lets through
+	entries := []*model.NewWordEntry{
+		{Polish: "kot", Translation: &model.NewTranslation{English: "cat", Sentences: []string{}}},
+		{Polish: "pies", Translation: &model.NewTranslation{English: "dog\x00", Sentences: []string{}}},
+		{Polish: "kot", Translation: &model.NewTranslation{English: "tomcat", Sentences: []string{}}},
+	}
+	mode := model.ImportModeBestEffort
+
+	results, err := s.svc.ImportWords(entries, &mode)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.ImportStatusCreated, results[0].Status)
+	assert.Equal(s.T(), model.ImportStatusFailed, results[1].Status)
+	assert.Equal(s.T(), customerrors.CodeInternal, *results[1].ErrorCode)
+	assert.Equal(s.T(), model.ImportStatusMerged, results[2].Status)
+
+	word, err := s.svc.SelectWord("kot")
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), word.Translations, 2)
+
+	_, err = s.svc.SelectWord("pies")
+	assert.Error(s.T(), err)
+}
+
+func (s *DictionaryTestSuite) TestImportFile_OverwriteShouldReplaceTranslations() {
+
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{"I like my bike"}})
+
+	file := "polish,english,sentence\n" +
+		"rower,bicycle,I like my bicycle\n" +
+		"kot,cat,\n"
+	conflict := model.ConflictStrategyOverwrite
+
+	report, err := s.svc.ImportFile(strings.NewReader(file), &model.FileImportOptions{Conflict: &conflict})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int32(1), report.Overwritten)
+	assert.Equal(s.T(), int32(1), report.Created)
+
+	word, err := s.svc.SelectWord("rower")
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), word.Translations, 1)
+	assert.Equal(s.T(), "bicycle", word.Translations[0].English)
+}
+
+func (s *DictionaryTestSuite) TestGradeCard_ShouldHideCardUntilItsDue() {
+
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{"I like my bike"}})
+	s.svc.CreateWordOrAddTranslationOrSentence("kot", model.NewTranslation{English: "cat", Sentences: []string{}})
+
+	cards, err := s.svc.DueCards(nil)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), cards, 2)
+	assert.Equal(s.T(), "rower", cards[0].Polish)
+	assert.Equal(s.T(), "I like my bike", cards[0].Translation.Sentences[0].Sentence)
+
+	card, err := s.svc.GradeCard(cards[0].TranslationID, 4)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int32(1), card.Interval)
+	assert.Equal(s.T(), int32(1), card.Repetitions)
+
+	cards, err = s.svc.DueCards(nil)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), cards, 1)
+	assert.Equal(s.T(), "kot", cards[0].Polish)
+
+	//review state is deleted with its translation
+	_, err = s.svc.DeleteWord("rower")
+	assert.NoError(s.T(), err)
+	var count int64
+	s.DB.Table("review_states").Count(&count)
+	assert.Equal(s.T(), int64(0), count)
+}
+
+func (s *DictionaryTestSuite) TestGradeCard_Parallel_ShouldCountEveryGrade() {
+
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{}})
+
+	cards, err := s.svc.DueCards(nil)
+	assert.NoError(s.T(), err)
+
+	var wg sync.WaitGroup
+	wg.Add(10)
+
+	retChan := make(chan error, 10)
+
+	for i := 0; i < 10; i++ {
+		go func() {
+			defer wg.Done()
+			_, err := s.svc.GradeCard(cards[0].TranslationID, 5)
+			retChan <- err
+		}()
+	}
+
+	wg.Wait()
+	close(retChan)
+
+	for err := range retChan {
+		assert.NoError(s.T(), err)
+	}
+
+	var review dbmodels.ReviewState
+	s.DB.First(&review, "translation_id = ?", cards[0].TranslationID)
+	assert.Equal(s.T(), 10, review.Repetitions)
+}
+
+func (s *DictionaryTestSuite) TestQuiz_ShouldCheckAnswersToGeneratedQuestions() {
+
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{"Bikes are fast"}})
+	s.svc.CreateWordOrAddTranslationOrSentence("kot", model.NewTranslation{English: "cat", Sentences: []string{}})
+
+	size := int32(2)
+	questions, err := s.svc.GenerateQuiz(&size, nil, []model.QuestionKind{model.QuestionKindTyping})
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), questions, 2)
+
+	english := map[string]string{"rower": "bike", "kot": "cat"}
+	answers := []*model.QuizAnswer{
+		{QuestionID: questions[0].ID, Answer: english[questions[0].Prompt]},
+		{QuestionID: questions[1].ID, Answer: "dog"},
+	}
+
+	result, err := s.svc.SubmitQuiz(answers)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int32(1), result.Score)
+	assert.True(s.T(), result.Answers[0].Correct)
+	assert.False(s.T(), result.Answers[1].Correct)
+}
+
+func (s *DictionaryTestSuite) TestSetGrammar_ShouldLinkAspectPartnersAndFilterWords() {
+
+	s.svc.CreateWordOrAddTranslationOrSentence("robić", model.NewTranslation{English: "do", Sentences: []string{}})
+	s.svc.CreateWordOrAddTranslationOrSentence("zrobić", model.NewTranslation{English: "do", Sentences: []string{}})
+	s.svc.CreateWordOrAddTranslationOrSentence("rada", model.NewTranslation{English: "advice", Sentences: []string{"Good advice"}})
+
+	imperfective := model.AspectImperfective
+	partner := "zrobić"
+	result, err := s.svc.SetGrammar("robić", model.GrammarInput{PartOfSpeech: model.PartOfSpeechVerb, Aspect: &imperfective, AspectPartner: &partner})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "zrobić", *result.Word.AspectPartner)
+
+	word, err := s.svc.SelectWord("zrobić")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.PartOfSpeechVerb, *word.PartOfSpeech)
+	assert.Equal(s.T(), model.AspectPerfective, *word.Aspect)
+	assert.Equal(s.T(), "robić", *word.AspectPartner)
+
+	feminine := model.GenderFeminine
+	_, err = s.svc.SetGrammar("rada", model.GrammarInput{PartOfSpeech: model.PartOfSpeechNoun, Gender: &feminine})
+	assert.NoError(s.T(), err)
+	uncountable := model.CountabilityUncountable
+	_, err = s.svc.SetCountability("rada", "advice", &uncountable)
+	assert.NoError(s.T(), err)
+
+	verb := model.PartOfSpeechVerb
+	page, err := s.svc.ListWords(nil, nil, nil, nil, &model.GrammarFilter{PartOfSpeech: &verb}, nil)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), page.Edges, 2)
+
+	page, err = s.svc.ListWords(nil, nil, nil, nil, &model.GrammarFilter{Countability: &uncountable}, nil)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), page.Edges, 1)
+	assert.Equal(s.T(), model.CountabilityUncountable, *page.Edges[0].Node.Translations[0].Countability)
+
+	results, err := s.svc.Search("advice", nil, &model.GrammarFilter{Gender: &feminine, Countability: &uncountable}, nil)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), results, 2)
+
+	//the partner loses its link when it is deleted
+	_, err = s.svc.DeleteWord("zrobić")
+	assert.NoError(s.T(), err)
+	word, err = s.svc.SelectWord("robić")
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), word.AspectPartner)
+}
+
+func (s *DictionaryTestSuite) TestSelectWord_InflectedForm_ShouldResolveToLemma() {
+
+	s.svc.CreateWordOrAddTranslationOrSentence("kot", model.NewTranslation{English: "cat", Sentences: []string{}})
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{}})
+
+	_, err := s.svc.AddInflection("rower", "rowerem", []string{"instrumental", "singular"})
+	assert.NoError(s.T(), err)
+	_, err = s.svc.AddInflection("rower", "rowerem", []string{"Instrumental singular"})
+	assert.Equal(s.T(), customerrors.InflectionExistsError{Word: "rower", Form: "rowerem", Tags: "instrumental singular"}, err)
+
+	results, err := s.svc.ImportInflections([]*model.NewInflection{
+		{Lemma: "kot", Form: "kota", Tags: []string{"genitive", "singular"}},
+		{Lemma: "kot", Form: "kota", Tags: []string{"accusative", "singular"}},
+		{Lemma: "kot", Form: "kotów", Tags: []string{"genitive", "plural"}},
+		{Lemma: "rower", Form: "rowerem", Tags: []string{"instrumental", "singular"}},
+	}, nil)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.ImportStatusSkipped, results[3].Status)
+
+	word, err := s.svc.SelectWord("rowerem")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "rower", word.Polish)
+	assert.Equal(s.T(), []*model.Inflection{{Form: "rowerem", Tags: []string{"instrumental", "singular"}}}, word.MatchedForms)
+
+	word, err = s.svc.SelectWord("kota")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "kot", word.Polish)
+	assert.Len(s.T(), word.MatchedForms, 2)
+	assert.Len(s.T(), word.Inflections, 3)
+
+	word, err = s.svc.SelectWord("kot")
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), word.MatchedForms)
+
+	_, err = s.svc.DeleteInflection("kot", "kota", []string{"accusative", "singular"})
+	assert.NoError(s.T(), err)
+	_, err = s.svc.DeleteInflection("kot", "kota", nil)
+	assert.NoError(s.T(), err)
+	_, err = s.svc.DeleteInflection("kot", "kota", nil)
+	assert.Equal(s.T(), customerrors.InflectionNotExistsError{Word: "kot", Form: "kota"}, err)
+}
+
+func (s *DictionaryTestSuite) TestRelations_ShouldLinkEntriesAndBeDeletedWithThem() {
+
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bicycle", Sentences: []string{}})
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{}})
+	s.svc.CreateWordOrAddTranslationOrSentence("bicykl", model.NewTranslation{English: "bicycle", Sentences: []string{}})
+	s.svc.CreateWordOrAddTranslationOrSentence("rowerzysta", model.NewTranslation{English: "cyclist", Sentences: []string{}})
+
+	rower, bicykl, rowerzysta := model.RelationEnd{Text: "rower"}, model.RelationEnd{Text: "bicykl"}, model.RelationEnd{Text: "rowerzysta"}
+
+	_, err := s.svc.AddRelation(model.RelationKindSynonym, rower, bicykl)
+	assert.NoError(s.T(), err)
+	_, err = s.svc.AddRelation(model.RelationKindSynonym, bicykl, rower)
+	assert.Equal(s.T(), customerrors.RelationExistsError{Kind: "SYNONYM", From: "bicykl", To: "rower"}, err)
+	_, err = s.svc.AddRelation(model.RelationKindDerivedFrom, rowerzysta, rower)
+	assert.NoError(s.T(), err)
+
+	bicycle, bike := "bicycle", "bike"
+	_, err = s.svc.AddRelation(model.RelationKindSynonym,
+		model.RelationEnd{Text: "rower", Translation: &bicycle}, model.RelationEnd{Text: "rower", Translation: &bike})
+	assert.NoError(s.T(), err)
+
+	word, err := s.svc.SelectWord("rower")
+	assert.NoError(s.T(), err)
+	assert.ElementsMatch(s.T(), []*model.Relation{
+		{Kind: model.RelationKindSynonym, Word: "bicykl"},
+		{Kind: model.RelationKindDerivedFrom, Word: "rowerzysta", Incoming: true},
+	}, word.Related)
+	assert.Equal(s.T(), []*model.Relation{{Kind: model.RelationKindSynonym, Word: "rower", Translation: &bike}}, word.Translations[0].Related)
+
+	_, err = s.svc.RemoveRelation(model.RelationKindDerivedFrom, rower, rowerzysta)
+	assert.Equal(s.T(), customerrors.RelationNotExistsError{Kind: "DERIVED_FROM", From: "rower", To: "rowerzysta"}, err)
+	_, err = s.svc.RemoveRelation(model.RelationKindSynonym, bicykl, rower)
+	assert.NoError(s.T(), err)
+
+	_, err = s.svc.DeleteWord("rowerzysta")
+	assert.NoError(s.T(), err)
+	_, err = s.svc.DeleteTranslation("rower", "bike")
+	assert.NoError(s.T(), err)
+
+	word, err = s.svc.SelectWord("rower")
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), word.Related)
+	assert.Empty(s.T(), word.Translations[0].Related)
+}
+
+func (s *DictionaryTestSuite) TestPronunciation_AudioShouldBeDeletedWithWord() {
+
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bike", Sentences: []string{}})
+	s.svc.CreateWordOrAddTranslationOrSentence("rower", model.NewTranslation{English: "bicycle", Sentences: []string{}})
+
+	ipa, stress := "/ˈrɔ.vɛr/", int32(1)
+	_, err := s.svc.SetPronunciation("rower", nil, model.PronunciationInput{Ipa: &ipa, Stress: &stress})
+	assert.NoError(s.T(), err)
+
+	result, err := s.svc.UploadAudio("rower", nil, "rower.mp3", strings.NewReader("rower"))
+	assert.NoError(s.T(), err)
+	word := result.Word
+	assert.Equal(s.T(), "ˈrɔ.vɛr", *word.Pronunciation.Ipa)
+	assert.Equal(s.T(), int32(1), *word.Pronunciation.Stress)
+	wordClip := strings.TrimPrefix(*word.Pronunciation.AudioURL, audio.Route)
+
+	translation := "bike"
+	result, err = s.svc.UploadAudio("rower", &translation, "bike.ogg", strings.NewReader("bike"))
+	assert.NoError(s.T(), err)
+	bikeClip := strings.TrimPrefix(*result.Word.Translations[0].Pronunciation.AudioURL, audio.Route)
+
+	//setting the transcription keeps the clip
+	_, err = s.svc.SetPronunciation("rower", nil, model.PronunciationInput{})
+	assert.NoError(s.T(), err)
+	selected, err := s.svc.SelectWord("rower")
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), selected.Pronunciation.Ipa)
+	assert.NotNil(s.T(), selected.Pronunciation.AudioURL)
+
+	_, err = s.svc.DeleteWord("rower")
+	assert.NoError(s.T(), err)
+
+	for _, clip := range []string{wordClip, bikeClip} {
+		_, err = s.clips.Open(clip)
+		assert.Error(s.T(), err, clip)
+	}
+}
+
+func (s *DictionaryTestSuite) TestUsage_ShouldLabelTranslationsAndFilterWords() {
+
+	medical := "Medical"
+	_, err := s.svc.CreateWordOrAddTranslationOrSentence("facet", model.NewTranslation{English: "bloke", Sentences: []string{}, Labels: []model.UsageLabel{model.UsageLabelColloquial, model.UsageLabelRegional}})
+	assert.NoError(s.T(), err)
+	_, err = s.svc.CreateWordOrAddTranslationOrSentence("facet", model.NewTranslation{English: "guy", Sentences: []string{}})
+	assert.NoError(s.T(), err)
+	_, err = s.svc.CreateWordOrAddTranslationOrSentence("zawał", model.NewTranslation{English: "infarction", Sentences: []string{}, Domain: &medical})
+	assert.NoError(s.T(), err)
+
+	word, err := s.svc.SelectWord("facet")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []model.UsageLabel{model.UsageLabelColloquial, model.UsageLabelRegional}, word.Translations[0].Labels)
+	assert.Empty(s.T(), word.Translations[1].Labels)
+
+	colloquial := model.UsageLabelColloquial
+	page, err := s.svc.ListWords(nil, nil, nil, nil, &model.GrammarFilter{Label: &colloquial}, nil)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), page.Edges, 1)
+	assert.Equal(s.T(), "facet", page.Edges[0].Node.Polish)
+
+	//search matches translations by their own labels, not by the labels of other translations of the word
+	results, err := s.svc.Search("bloke", nil, &model.GrammarFilter{Label: &colloquial}, nil)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), results, 1)
+	results, err = s.svc.Search("guy", nil, &model.GrammarFilter{Label: &colloquial}, nil)
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), results)
+
+	note := "used in medical reports"
+	_, err = s.svc.SetUsage("zawał", "infarction", model.UsageInput{Labels: []model.UsageLabel{model.UsageLabelFormal}, Domain: &medical, Note: &note})
+	assert.NoError(s.T(), err)
+
+	formal := model.UsageLabelFormal
+	page, err = s.svc.ListWords(nil, nil, nil, nil, &model.GrammarFilter{Label: &formal, Domain: &medical}, nil)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), page.Edges, 1)
+	assert.Equal(s.T(), "medical", *page.Edges[0].Node.Translations[0].Domain)
+	assert.Equal(s.T(), note, *page.Edges[0].Node.Translations[0].Note)
+}
+
+func (s *DictionaryTestSuite) TestTagsAndCollections_ShouldOrganiseWords() {
+
+	s.svc.CreateWordOrAddTranslationOrSentence("garnek", model.NewTranslation{English: "pot", Sentences: []string{"The pot is hot"}})
+	s.svc.CreateWordOrAddTranslationOrSentence("nóż", model.NewTranslation{English: "knife", Sentences: []string{}})
+	s.svc.CreateWordOrAddTranslationOrSentence("bilet", model.NewTranslation{English: "ticket", Sentences: []string{}})
+
+	_, err := s.svc.TagWord("garnek", []string{"Kitchen", "lesson 5"})
+	assert.NoError(s.T(), err)
+	result, err := s.svc.TagWord("nóż", []string{"kitchen"})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"kitchen"}, result.Word.Tags)
+	result, err = s.svc.TagWord("nóż", []string{"KITCHEN"})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.MutationOutcomeNoop, result.Outcome)
+
+	tag := "kitchen"
+	page, err := s.svc.ListWords(nil, nil, nil, nil, nil, &tag)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), page.Edges, 2)
+	results, err := s.svc.Search("pot", nil, nil, &tag)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), results, 2)
+
+	tags, err := s.svc.Tags()
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []*model.Tag{{Name: "kitchen", Words: 2}, {Name: "lesson 5", Words: 1}}, tags)
+
+	_, err = s.svc.RenameTag("lesson 5", "kitchen")
+	assert.Equal(s.T(), customerrors.TagExistsError{Tag: "kitchen"}, err)
+	_, err = s.svc.DeleteTag("kitchen")
+	assert.NoError(s.T(), err)
+	word, err := s.svc.SelectWord("garnek")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"lesson 5"}, word.Tags)
+
+	_, err = s.svc.CreateCollection("Lesson 5")
+	assert.NoError(s.T(), err)
+	_, err = s.svc.CreateCollection("Lesson 5 ")
+	assert.Equal(s.T(), customerrors.CollectionExistsError{Collection: "Lesson 5"}, err)
+
+	_, err = s.svc.AddToCollection("Lesson 5", "bilet", "ticket")
+	assert.NoError(s.T(), err)
+	collection, err := s.svc.AddToCollection("Lesson 5", "garnek", "pot")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "ticket", collection.Items[0].Translation)
+	assert.Equal(s.T(), int32(2), collection.Items[1].Position)
+	_, err = s.svc.AddToCollection("Lesson 5", "garnek", "pot")
+	assert.Equal(s.T(), customerrors.CollectionItemExistsError{Collection: "Lesson 5", Word: "garnek", Translation: "pot"}, err)
+
+	_, err = s.svc.DeleteWord("bilet")
+	assert.NoError(s.T(), err)
+	collection, err = s.svc.Collection("Lesson 5")
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), collection.Items, 1)
+	assert.Equal(s.T(), int32(1), collection.Items[0].Position)
+
+	collection, err = s.svc.RemoveFromCollection("Lesson 5", "garnek", "pot")
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), collection.Items)
+
+	_, err = s.svc.DeleteCollection("Lesson 5")
+	assert.NoError(s.T(), err)
+	_, err = s.svc.Collection("Lesson 5")
+	assert.Equal(s.T(), customerrors.CollectionNotExistsError{Collection: "Lesson 5"}, err)
+}
+
+func (s *DictionaryTestSuite) TestLanguagePairs_ShouldKeepWordsOfEachPairApart() {
+
+	german, err := s.svc.InPair(&model.LanguagePair{Source: model.LanguageDe, Target: model.LanguageEn})
+	assert.NoError(s.T(), err)
+	polishGerman, err := s.svc.InPair(&model.LanguagePair{Source: model.LanguagePl, Target: model.LanguageDe})
+	assert.NoError(s.T(), err)
+
+	s.svc.CreateWordOrAddTranslationOrSentence("kot", model.NewTranslation{English: "cat", Sentences: []string{}})
+	result, err := german.CreateWordOrAddTranslationOrSentence("Katze", model.NewTranslation{English: "cat", Sentences: []string{}})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.MutationOutcomeCreated, result.Outcome)
+	result, err = polishGerman.CreateWordOrAddTranslationOrSentence("kot", model.NewTranslation{English: "Katze", Sentences: []string{}})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.MutationOutcomeCreated, result.Outcome)
+
+	word, err := polishGerman.SelectWord("kot")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.LanguagePl, word.Language)
+	assert.Equal(s.T(), "Katze", word.Translations[0].Text)
+	assert.Equal(s.T(), model.LanguageDe, word.Translations[0].Language)
+
+	words, err := german.SelectByEnglish("cat")
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), words, 1)
+	assert.Equal(s.T(), "Katze", words[0].Text)
+
+	_, err = s.svc.SelectWord("Katze")
+	assert.Error(s.T(), err)
+
+	_, err = polishGerman.DeleteWord("kot")
+	assert.NoError(s.T(), err)
+	word, err = s.svc.SelectWord("kot")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "cat", word.Translations[0].English)
+
+	language := model.LanguageEn
+	completions, err := german.Autocomplete("c", &language, nil)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"cat"}, completions)
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	dbmodels "github.com/staszkiet/DictionaryGolang/server/database/models"
+	"gorm.io/gorm"
+)
+
+// Full-text search columns are generated by postgres from the text they index, so they are created
+// with plain SQL instead of being declared on the models (AutoMigrate can't describe generated columns)
+var searchMigrations = []string{
+	`ALTER TABLE words ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (to_tsvector('simple', polish)) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_words_search_vector ON words USING GIN (search_vector)`,
+	`ALTER TABLE translations ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (to_tsvector('english', english)) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_translations_search_vector ON translations USING GIN (search_vector)`,
+	`ALTER TABLE sentences ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (to_tsvector('english', sentence)) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_sentences_search_vector ON sentences USING GIN (search_vector)`,
+}
+
+// Typo and diacritic tolerant lookups compare words with polish letters replaced by their latin counterparts
+// (unaccent is not immutable, so it is wrapped to be usable in indexes) and rank them by trigram similarity
+var fuzzyMatchMigrations = []string{
+	`CREATE EXTENSION IF NOT EXISTS unaccent`,
+	`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+	`CREATE OR REPLACE FUNCTION immutable_unaccent(text) RETURNS text AS
+		$$ SELECT public.unaccent('public.unaccent'::regdictionary, $1) $$
+		LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT`,
+	`CREATE INDEX IF NOT EXISTS idx_words_polish_unaccent ON words (immutable_unaccent(polish))`,
+	`CREATE INDEX IF NOT EXISTS idx_words_polish_trgm ON words USING GIN (immutable_unaccent(polish) gin_trgm_ops)`,
+}
+
+// text_pattern_ops indexes let postgres answer "LIKE 'prefix%'" with an index scan regardless of the database collation
+var prefixMigrations = []string{
+	`CREATE INDEX IF NOT EXISTS idx_words_polish_prefix ON words (polish text_pattern_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_translations_english_prefix ON translations (english text_pattern_ops)`,
+}
+
+// Creates or updates all tables, columns and indexes used by the dictionary
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&dbmodels.Word{}, &dbmodels.Translation{}, &dbmodels.Sentence{}, &dbmodels.ReviewState{}, &dbmodels.Inflection{},
+		&dbmodels.WordRelation{}, &dbmodels.TranslationRelation{}, &dbmodels.Tag{}, &dbmodels.Collection{}, &dbmodels.CollectionItem{}); err != nil {
+		return err
+	}
+
+	for _, migrations := range [][]string{searchMigrations, fuzzyMatchMigrations, prefixMigrations} {
+		for _, statement := range migrations {
+			if err := db.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
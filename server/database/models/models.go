@@ -1,28 +1,163 @@
 package dbmodels
 
-import "github.com/staszkiet/DictionaryGolang/server/graph/model"
+import (
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/staszkiet/DictionaryGolang/server/audio"
+	"github.com/staszkiet/DictionaryGolang/server/graph/model"
+	"github.com/staszkiet/DictionaryGolang/server/study"
+)
+
+// Headword of a language pair, Polish holds its text in any language. Words of different pairs are separate entries,
+// so the same text can be translated from polish into english and into german.
+// Grammatical metadata is optional, columns hold values of the GraphQL enums. Aspect partners point at each other.
+// Relations are the edges created from the word, RelatedBy the edges created from other words pointing at it
 type Word struct {
-	ID           uint          `gorm:"primarykey"`
-	Polish       string        `json:"polish" gorm:"index;unique"`
-	Translations []Translation `gorm:"foreignKey:WordID;constraint:OnDelete:CASCADE;"`
+	ID                  uint           `gorm:"primarykey"`
+	Language            string         `json:"language" gorm:"not null;default:PL;uniqueIndex:headword,priority:1"`
+	TranslationLanguage string         `json:"translationLanguage" gorm:"not null;default:EN;uniqueIndex:headword,priority:2"`
+	Polish              string         `json:"polish" gorm:"index;uniqueIndex:headword,priority:3"`
+	PartOfSpeech        *string        `json:"partOfSpeech" gorm:"index"`
+	Gender              *string        `json:"gender"`
+	Aspect              *string        `json:"aspect"`
+	AspectPartnerID     *uint          `json:"aspectPartnerId"`
+	AspectPartner       *Word          `gorm:"foreignKey:AspectPartnerID;constraint:OnDelete:SET NULL;"`
+	Translations        []Translation  `gorm:"foreignKey:WordID;constraint:OnDelete:CASCADE;"`
+	Inflections         []Inflection   `gorm:"foreignKey:WordID;constraint:OnDelete:CASCADE;"`
+	Relations           []WordRelation `gorm:"foreignKey:WordID;constraint:OnDelete:CASCADE;"`
+	RelatedBy           []WordRelation `gorm:"foreignKey:RelatedID;constraint:OnDelete:CASCADE;"`
+	Tags                []Tag          `gorm:"many2many:word_tags;constraint:OnDelete:CASCADE;"`
+	Pronunciation       Pronunciation  `gorm:"embedded"`
+}
+
+// How a word or a translation is pronounced, stored in the columns of its table. Audio is the name of the clip
+// in the audio store, which is deleted by the service together with the word or translation
+type Pronunciation struct {
+	IPA    *string `json:"ipa" gorm:"column:ipa"`
+	Stress *int    `json:"stress"`
+	Audio  *string `json:"audio"`
+}
+
+// Topic label of words, e.g. kitchen or lesson 5. Names are stored lowercase, a tag is shared by words of every pair
+type Tag struct {
+	ID   uint   `gorm:"primarykey"`
+	Name string `json:"name" gorm:"unique"`
+}
+
+// Tag with the number of words tagged with it. It isn't a table, rows are read from tags joined with word_tags
+type TagCount struct {
+	ID    uint
+	Name  string
+	Words int
+}
+
+// Ordered list of translations put together by the user, e.g. the vocabulary of a lesson
+type Collection struct {
+	ID    uint             `gorm:"primarykey"`
+	Name  string           `json:"name" gorm:"unique"`
+	Items []CollectionItem `gorm:"foreignKey:CollectionID;constraint:OnDelete:CASCADE;"`
+}
+
+// Translation in a collection. Items are ordered by Position, new ones are put at the end. Positions may have gaps
+// after items are removed, the API numbers items from 1
+type CollectionItem struct {
+	ID            uint                `gorm:"primarykey"`
+	CollectionID  uint                `json:"collectionId" gorm:"uniqueIndex:collection_item"`
+	TranslationID uint                `json:"translationId" gorm:"uniqueIndex:collection_item"`
+	Position      int                 `json:"position"`
+	Translation   *RelatedTranslation `gorm:"foreignKey:TranslationID;-:migration"`
+}
+
+// Inflected form of a word, e.g. rowerem for rower. Tags are lowercase grammatical categories separated by spaces,
+// the same form can be stored with different tags (kota is both genitive and accusative singular of kot)
+type Inflection struct {
+	ID     uint   `gorm:"primarykey"`
+	WordID uint   `json:"wordId" gorm:"uniqueIndex:inflection"`
+	Form   string `json:"form" gorm:"uniqueIndex:inflection;index"`
+	Tags   string `json:"tags" gorm:"uniqueIndex:inflection"`
+}
+
+// Typed edge from a word to a related one, e.g. bicykl is a synonym of rower. Edges are stored in the direction
+// they were created and are deleted together with either of the words
+type WordRelation struct {
+	ID        uint   `gorm:"primarykey"`
+	Kind      string `json:"kind" gorm:"uniqueIndex:word_relation"`
+	WordID    uint   `json:"wordId" gorm:"uniqueIndex:word_relation"`
+	RelatedID uint   `json:"relatedId" gorm:"uniqueIndex:word_relation;index"`
+	Word      *Word  `gorm:"foreignKey:WordID;constraint:OnDelete:CASCADE;"`
+	Related   *Word  `gorm:"foreignKey:RelatedID;constraint:OnDelete:CASCADE;"`
+}
+
+// Typed edge from a translation to a related one, works like WordRelation
+type TranslationRelation struct {
+	ID            uint                `gorm:"primarykey"`
+	Kind          string              `json:"kind" gorm:"uniqueIndex:translation_relation"`
+	TranslationID uint                `json:"translationId" gorm:"uniqueIndex:translation_relation"`
+	RelatedID     uint                `json:"relatedId" gorm:"uniqueIndex:translation_relation;index"`
+	Translation   *RelatedTranslation `gorm:"foreignKey:TranslationID;-:migration"`
+	Related       *RelatedTranslation `gorm:"foreignKey:RelatedID;-:migration"`
+}
+
+// Translation another entry points at (the other end of a relation, a collection item), read together with its word.
+// It isn't a separate table, the columns are read from translations
+type RelatedTranslation struct {
+	ID       uint
+	WordID   uint
+	Language string
+	English  string
+	Word     *Word `gorm:"foreignKey:WordID;-:migration"`
+}
+
+func (RelatedTranslation) TableName() string {
+	return "translations"
 }
 
+// Translation into the target language of the pair, English holds its text in any language. Translations are ordered by
+// Position, the ones without a position (added after the last reorder) follow in the order they were added
 type Translation struct {
-	ID        uint       `gorm:"primarykey"`
-	WordID    uint       `json:"wordId" gorm:"uniqueIndex:translation"`
-	English   string     `json:"english" gorm:"uniqueIndex:translation"`
-	Sentences []Sentence `gorm:"foreignKey:TranslationID;constraint:OnDelete:CASCADE"`
+	ID            uint                  `gorm:"primarykey"`
+	WordID        uint                  `json:"wordId" gorm:"uniqueIndex:translation"`
+	Language      string                `json:"language" gorm:"not null;default:EN"`
+	English       string                `json:"english" gorm:"uniqueIndex:translation;index"`
+	Countability  *string               `json:"countability"`
+	Sentences     []Sentence            `gorm:"foreignKey:TranslationID;constraint:OnDelete:CASCADE"`
+	Review        *ReviewState          `gorm:"foreignKey:TranslationID;constraint:OnDelete:CASCADE"`
+	Relations     []TranslationRelation `gorm:"foreignKey:TranslationID;constraint:OnDelete:CASCADE"`
+	RelatedBy     []TranslationRelation `gorm:"foreignKey:RelatedID;constraint:OnDelete:CASCADE"`
+	Collected     []CollectionItem      `gorm:"foreignKey:TranslationID;constraint:OnDelete:CASCADE"`
+	Pronunciation Pronunciation         `gorm:"embedded"`
+	Usage         Usage                 `gorm:"embedded"`
+	Position      *int                  `json:"position"`
+}
+
+// Register and usage labels of a translation, e.g. COLLOQUIAL for bloke. Labels are values of the GraphQL UsageLabel
+// enum separated by spaces, the domain is lowercase
+type Usage struct {
+	Labels string  `json:"labels" gorm:"not null;default:''"`
+	Domain *string `json:"domain" gorm:"index"`
+	Note   *string `json:"note"`
 }
 
+// Example sentence in the language of the translation. Original holds the same sentence in the language of the word,
+// which makes it a sentence pair. Sentences are ordered like translations
 type Sentence struct {
-	ID            uint   `gorm:"primarykey"`
-	TranslationID uint   `json:"translationId" gorm:"uniqueIndex:sentence"`
-	Sentence      string `json:"sentence" gorm:"uniqueIndex:sentence"`
+	ID            uint    `gorm:"primarykey"`
+	TranslationID uint    `json:"translationId" gorm:"uniqueIndex:sentence"`
+	Sentence      string  `json:"sentence" gorm:"uniqueIndex:sentence"`
+	Original      *string `json:"original"`
+	Attribution   *string `json:"attribution"`
+	License       *string `json:"license"`
+	Position      *int    `json:"position"`
 }
 
 func DBSentenceToGQLSentence(s *Sentence) *model.Sentence {
-	return &model.Sentence{Sentence: s.Sentence}
+	return &model.Sentence{Sentence: s.Sentence, Original: s.Original, Attribution: s.Attribution, License: s.License}
+}
+
+func DBInflectionToGQLInflection(i *Inflection) *model.Inflection {
+	return &model.Inflection{Form: i.Form, Tags: strings.Fields(i.Tags)}
 }
 
 func DBTranslationToGQLTranslation(t *Translation) *model.Translation {
@@ -33,7 +168,55 @@ func DBTranslationToGQLTranslation(t *Translation) *model.Translation {
 		sentences = append(sentences, DBSentenceToGQLSentence(&s))
 	}
 
-	return &model.Translation{English: t.English, Sentences: sentences}
+	related := []*model.Relation{}
+
+	for _, r := range t.Relations {
+		if r.Related != nil && r.Related.Word != nil {
+			related = append(related, &model.Relation{Kind: model.RelationKind(r.Kind), Word: r.Related.Word.Polish, Translation: &r.Related.English})
+		}
+	}
+	for _, r := range t.RelatedBy {
+		if r.Translation != nil && r.Translation.Word != nil {
+			related = append(related, &model.Relation{Kind: model.RelationKind(r.Kind), Word: r.Translation.Word.Polish, Translation: &r.Translation.English, Incoming: true})
+		}
+	}
+
+	labels := []model.UsageLabel{}
+
+	for _, l := range strings.Fields(t.Usage.Labels) {
+		labels = append(labels, model.UsageLabel(l))
+	}
+
+	return &model.Translation{
+		Text:          t.English,
+		English:       t.English,
+		Language:      model.Language(t.Language),
+		Countability:  enumValue[model.Countability](t.Countability),
+		Sentences:     sentences,
+		Related:       related,
+		Pronunciation: DBPronunciationToGQLPronunciation(&t.Pronunciation),
+		Labels:        labels,
+		Domain:        t.Usage.Domain,
+		Note:          t.Usage.Note,
+	}
+}
+
+// Returns nil when nothing about the pronunciation is known
+func DBPronunciationToGQLPronunciation(p *Pronunciation) *model.Pronunciation {
+	if p.IPA == nil && p.Stress == nil && p.Audio == nil {
+		return nil
+	}
+
+	pronunciation := &model.Pronunciation{Ipa: p.IPA}
+	if p.Stress != nil {
+		stress := int32(*p.Stress)
+		pronunciation.Stress = &stress
+	}
+	if p.Audio != nil {
+		url := audio.URL(*p.Audio)
+		pronunciation.AudioURL = &url
+	}
+	return pronunciation
 }
 
 func DBWordToGQLWord(w *Word) *model.Word {
@@ -43,5 +226,157 @@ func DBWordToGQLWord(w *Word) *model.Word {
 		translations = append(translations, DBTranslationToGQLTranslation(&t))
 	}
 
-	return &model.Word{Polish: w.Polish, Translations: translations}
+	inflections := []*model.Inflection{}
+
+	for _, i := range w.Inflections {
+		inflections = append(inflections, DBInflectionToGQLInflection(&i))
+	}
+
+	related := []*model.Relation{}
+
+	for _, r := range w.Relations {
+		if r.Related != nil {
+			related = append(related, &model.Relation{Kind: model.RelationKind(r.Kind), Word: r.Related.Polish})
+		}
+	}
+	for _, r := range w.RelatedBy {
+		if r.Word != nil {
+			related = append(related, &model.Relation{Kind: model.RelationKind(r.Kind), Word: r.Word.Polish, Incoming: true})
+		}
+	}
+
+	tags := []string{}
+
+	for _, t := range w.Tags {
+		tags = append(tags, t.Name)
+	}
+
+	word := &model.Word{
+		Text:          w.Polish,
+		Polish:        w.Polish,
+		Language:      model.Language(w.Language),
+		PartOfSpeech:  enumValue[model.PartOfSpeech](w.PartOfSpeech),
+		Gender:        enumValue[model.Gender](w.Gender),
+		Aspect:        enumValue[model.Aspect](w.Aspect),
+		Translations:  translations,
+		Inflections:   inflections,
+		MatchedForms:  []*model.Inflection{},
+		Related:       related,
+		Tags:          tags,
+		Pronunciation: DBPronunciationToGQLPronunciation(&w.Pronunciation),
+	}
+	if w.AspectPartner != nil {
+		word.AspectPartner = &w.AspectPartner.Polish
+	}
+	return word
+}
+
+// Converts an optional column into the GraphQL enum it stores
+func enumValue[T ~string](column *string) *T {
+	if column == nil {
+		return nil
+	}
+	value := T(*column)
+	return &value
+}
+
+// Converts an optional GraphQL enum into the column storing it
+func EnumColumn[T ~string](value *T) *string {
+	if value == nil {
+		return nil
+	}
+	column := string(*value)
+	return &column
+}
+
+// Spaced repetition state of a translation, created when it's graded for the first time
+type ReviewState struct {
+	TranslationID uint `gorm:"primarykey;autoIncrement:false"`
+	EaseFactor    float64
+	Interval      int
+	Repetitions   int
+	Lapses        int
+	Due           time.Time `gorm:"index"`
+}
+
+// Translation studied as a flashcard. It isn't a table, cards are read from translations with their words
+type Card struct {
+	Polish string
+	// Language of the word, the translation has its own
+	Language    string
+	Translation Translation
+}
+
+// Returns the review state of the card, cards which were never graded are due now
+func (c *Card) State(now time.Time) study.State {
+	r := c.Translation.Review
+	if r == nil {
+		return study.NewState(now)
+	}
+	return study.State{EaseFactor: r.EaseFactor, Interval: r.Interval, Repetitions: r.Repetitions, Lapses: r.Lapses, Due: r.Due}
+}
+
+func DBCardToGQLCard(c *Card, now time.Time) *model.Card {
+	state := c.State(now)
+	return &model.Card{
+		TranslationID: strconv.FormatUint(uint64(c.Translation.ID), 10),
+		Text:          c.Polish,
+		Polish:        c.Polish,
+		Translation:   DBTranslationToGQLTranslation(&c.Translation),
+		EaseFactor:    state.EaseFactor,
+		Interval:      int32(state.Interval),
+		Repetitions:   int32(state.Repetitions),
+		Lapses:        int32(state.Lapses),
+		Due:           state.Due,
+	}
+}
+
+func DBTagCountToGQLTag(t *TagCount) *model.Tag {
+	return &model.Tag{Name: t.Name, Words: int32(t.Words)}
+}
+
+func DBCollectionToGQLCollection(c *Collection) *model.Collection {
+	items := []*model.CollectionItem{}
+
+	for _, i := range c.Items {
+		if i.Translation == nil || i.Translation.Word == nil {
+			continue
+		}
+		items = append(items, &model.CollectionItem{
+			Position:            int32(len(items) + 1),
+			Text:                i.Translation.Word.Polish,
+			Language:            model.Language(i.Translation.Word.Language),
+			Translation:         i.Translation.English,
+			TranslationLanguage: model.Language(i.Translation.Language),
+		})
+	}
+
+	return &model.Collection{Name: c.Name, Items: items}
+}
+
+const (
+	SearchHitWord        = "word"
+	SearchHitTranslation = "translation"
+	SearchHitSentence    = "sentence"
+)
+
+// Single full-text search match. It isn't a table, rows are read from a query over words, translations and sentences
+type SearchHit struct {
+	Kind     string
+	Polish   string
+	English  string
+	Sentence string
+	Rank     float64
+	Snippet  string
+}
+
+func DBSearchHitToGQLSearchResult(h *SearchHit) model.SearchResult {
+	switch h.Kind {
+	case SearchHitTranslation:
+		return &model.TranslationHit{Polish: h.Polish, English: h.English, Rank: h.Rank, Snippet: h.Snippet}
+	case SearchHitSentence:
+		return &model.SentenceHit{Polish: h.Polish, English: h.English, Sentence: h.Sentence, Rank: h.Rank, Snippet: h.Snippet}
+	default:
+		return &model.WordHit{Polish: h.Polish, Rank: h.Rank, Snippet: h.Snippet}
+	}
 }
@@ -0,0 +1,117 @@
+package database
+
+import (
+	"slices"
+
+	dbmodels "github.com/staszkiet/DictionaryGolang/server/database/models"
+	customerrors "github.com/staszkiet/DictionaryGolang/server/errors"
+	"github.com/staszkiet/DictionaryGolang/server/graph/model"
+)
+
+// Puts the ids of items matching the listed names first, in the order of the list, followed by the other items in their
+// current order. names holds the names each item can be listed by. Returns the listed name which matches no item, if any
+func newOrder(ids []uint, names [][]string, listed []string) ([]uint, *string) {
+	order := make([]uint, 0, len(ids))
+	placed := make([]bool, len(ids))
+
+	for _, name := range listed {
+		found := false
+		for i := range ids {
+			if slices.Contains(names[i], name) {
+				found = true
+				if !placed[i] {
+					placed[i] = true
+					order = append(order, ids[i])
+				}
+				break
+			}
+		}
+		if !found {
+			return nil, &name
+		}
+	}
+
+	for i, id := range ids {
+		if !placed[i] {
+			order = append(order, id)
+		}
+	}
+	return order, nil
+}
+
+// Changes the order of translations of a word, the first one becomes its primary sense
+func (r *DictionaryService) ReorderTranslations(polish string, english []string) (*model.MutationResult, error) {
+	outcome := model.MutationOutcomeNoop
+
+	_, err := r.repository.WithTransaction(func(txRepo IRepository) error {
+		var word dbmodels.Word
+		if err := txRepo.GetWord(polish, &word); err != nil {
+			return err
+		}
+
+		ids := []uint{}
+		names := [][]string{}
+		for _, t := range word.Translations {
+			ids = append(ids, t.ID)
+			names = append(names, []string{t.English})
+		}
+
+		order, missing := newOrder(ids, names, english)
+		if missing != nil {
+			return customerrors.TranslationNotExistsError{Word: polish, Translation: *missing}
+		}
+		if slices.Equal(order, ids) {
+			return nil
+		}
+		outcome = model.MutationOutcomeUpdated
+		return txRepo.UpdatePositions(&dbmodels.Translation{}, order)
+	}, false, false)
+
+	if err != nil {
+		return nil, err
+	}
+	return r.mutationResult(polish, nil, outcome)
+}
+
+// Changes the order of example sentences of a translation. Sentence pairs can be listed by either of their sentences
+func (r *DictionaryService) ReorderSentences(polish string, english string, sentences []string) (*model.MutationResult, error) {
+	outcome := model.MutationOutcomeNoop
+
+	_, err := r.repository.WithTransaction(func(txRepo IRepository) error {
+		var word dbmodels.Word
+		if err := txRepo.GetWord(polish, &word); err != nil {
+			return err
+		}
+
+		index := slices.IndexFunc(word.Translations, func(t dbmodels.Translation) bool { return t.English == english })
+		if index < 0 {
+			return customerrors.TranslationNotExistsError{Word: polish, Translation: english}
+		}
+
+		ids := []uint{}
+		names := [][]string{}
+		for _, s := range word.Translations[index].Sentences {
+			ids = append(ids, s.ID)
+			if s.Original != nil {
+				names = append(names, []string{s.Sentence, *s.Original})
+			} else {
+				names = append(names, []string{s.Sentence})
+			}
+		}
+
+		order, missing := newOrder(ids, names, sentences)
+		if missing != nil {
+			return customerrors.SentenceNotExistsError{Word: polish, Translation: english, Sentence: *missing}
+		}
+		if slices.Equal(order, ids) {
+			return nil
+		}
+		outcome = model.MutationOutcomeUpdated
+		return txRepo.UpdatePositions(&dbmodels.Sentence{}, order)
+	}, false, false)
+
+	if err != nil {
+		return nil, err
+	}
+	return r.mutationResult(polish, nil, outcome)
+}
@@ -0,0 +1,170 @@
+package database
+
+import (
+	"io"
+	"log"
+	"strings"
+
+	"github.com/staszkiet/DictionaryGolang/server/audio"
+	dbmodels "github.com/staszkiet/DictionaryGolang/server/database/models"
+	customerrors "github.com/staszkiet/DictionaryGolang/server/errors"
+	"github.com/staszkiet/DictionaryGolang/server/graph/model"
+)
+
+// Keeps audio clips of words and translations under the names stored in their pronunciation
+type AudioStore interface {
+	Save(name string, r io.Reader) error
+	Delete(name string) error
+}
+
+// Strips the slashes or brackets IPA is usually written in, e.g. /ˈrɔ.vɛr/. An empty transcription clears it
+func ipaTranscription(ipa *string) *string {
+	if ipa == nil {
+		return nil
+	}
+
+	trimmed := strings.TrimSpace(*ipa)
+	for _, delimiters := range []string{"//", "[]"} {
+		if len(trimmed) >= 2 && trimmed[0] == delimiters[0] && trimmed[len(trimmed)-1] == delimiters[1] {
+			trimmed = strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+		}
+	}
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// Finds the word, or its translation when one is given, and returns it with its pronunciation
+func pronunciationOf(txRepo IRepository, text string, translation *string) (interface{}, *dbmodels.Pronunciation, error) {
+	if translation == nil {
+		var word dbmodels.Word
+		if err := txRepo.GetWord(text, &word); err != nil {
+			return nil, nil, err
+		}
+		return &word, &word.Pronunciation, nil
+	}
+
+	var t dbmodels.Translation
+	if err := txRepo.GetTranslation(text, *translation, &t); err != nil {
+		return nil, nil, err
+	}
+	return &t, &t.Pronunciation, nil
+}
+
+// Names of the audio clips of a word and its translations
+func audioFiles(word *dbmodels.Word) []string {
+	names := []string{}
+	if word.Pronunciation.Audio != nil {
+		names = append(names, *word.Pronunciation.Audio)
+	}
+	for _, t := range word.Translations {
+		if t.Pronunciation.Audio != nil {
+			names = append(names, *t.Pronunciation.Audio)
+		}
+	}
+	return names
+}
+
+// Deletes clips of deleted or replaced entries. The database change is already committed, so failures are only logged
+func (r *DictionaryService) removeAudio(names []string) {
+	for _, name := range names {
+		if err := r.audio.Delete(name); err != nil {
+			log.Printf("deleting audio %s failed: %v", name, err)
+		}
+	}
+}
+
+// Sets the transcription and stress of a word or of its translation, the audio clip is kept
+func (r *DictionaryService) SetPronunciation(text string, translation *string, pronunciation model.PronunciationInput) (*model.MutationResult, error) {
+	var stress *int
+	if pronunciation.Stress != nil {
+		if *pronunciation.Stress < 1 {
+			return nil, customerrors.InvalidStressError{Stress: int(*pronunciation.Stress)}
+		}
+		value := int(*pronunciation.Stress)
+		stress = &value
+	}
+	ipa := ipaTranscription(pronunciation.Ipa)
+
+	_, err := r.repository.WithTransaction(func(txRepo IRepository) error {
+		entity, current, err := pronunciationOf(txRepo, text, translation)
+		if err != nil {
+			return err
+		}
+
+		current.IPA, current.Stress = ipa, stress
+		return txRepo.UpdatePronunciation(entity, current)
+	}, false, false)
+
+	if err != nil {
+		return nil, err
+	}
+	return r.mutationResult(text, nil, model.MutationOutcomeUpdated)
+}
+
+// Stores the clip under a new name and attaches it to a word or its translation. The clip it replaces is deleted
+func (r *DictionaryService) UploadAudio(text string, translation *string, filename string, file io.Reader) (*model.MutationResult, error) {
+	ext, ok := audio.Extension(filename)
+	if !ok {
+		return nil, customerrors.UnsupportedAudioError{File: filename}
+	}
+
+	name, err := audio.NewName(ext)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.audio.Save(name, file); err != nil {
+		return nil, err
+	}
+
+	var replaced *string
+
+	_, err = r.repository.WithTransaction(func(txRepo IRepository) error {
+		entity, current, err := pronunciationOf(txRepo, text, translation)
+		if err != nil {
+			return err
+		}
+
+		replaced = current.Audio
+		current.Audio = &name
+		return txRepo.UpdatePronunciation(entity, current)
+	}, false, false)
+
+	if err != nil {
+		r.removeAudio([]string{name})
+		return nil, err
+	}
+	if replaced != nil {
+		r.removeAudio([]string{*replaced})
+	}
+	return r.mutationResult(text, nil, model.MutationOutcomeUpdated)
+}
+
+// Detaches the clip from a word or its translation and deletes it
+func (r *DictionaryService) DeleteAudio(text string, translation *string) (*model.MutationResult, error) {
+	var removed *string
+
+	_, err := r.repository.WithTransaction(func(txRepo IRepository) error {
+		entity, current, err := pronunciationOf(txRepo, text, translation)
+		if err != nil {
+			return err
+		}
+
+		removed = current.Audio
+		if removed == nil {
+			return nil
+		}
+		current.Audio = nil
+		return txRepo.UpdatePronunciation(entity, current)
+	}, false, false)
+
+	if err != nil {
+		return nil, err
+	}
+	if removed == nil {
+		return r.mutationResult(text, nil, model.MutationOutcomeNoop)
+	}
+	r.removeAudio([]string{*removed})
+	return r.mutationResult(text, nil, model.MutationOutcomeDeleted)
+}
@@ -0,0 +1,280 @@
+package database
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"strings"
+
+	dbmodels "github.com/staszkiet/DictionaryGolang/server/database/models"
+	customerrors "github.com/staszkiet/DictionaryGolang/server/errors"
+	"github.com/staszkiet/DictionaryGolang/server/graph/model"
+)
+
+const (
+	DefaultQuizSize = 10
+	MaxQuizSize     = 50
+	// Options of a multiple choice question, including the correct one
+	QuizChoices = 4
+	// Replaces the translation in sentences of cloze questions
+	ClozeBlank = "____"
+)
+
+// Question IDs describe what was asked, so answers can be checked against the current dictionary
+// without keeping generated quizzes on the server
+type quizQuestion struct {
+	kind          model.QuestionKind
+	direction     model.QuizDirection
+	translationID uint
+	sentenceID    uint //only in cloze questions
+}
+
+func (q quizQuestion) id() string {
+	return base64.URLEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s:%d:%d", q.kind, q.direction, q.translationID, q.sentenceID)))
+}
+
+func parseQuestionID(id string) (quizQuestion, error) {
+	invalid := customerrors.InvalidQuestionError{QuestionID: id}
+
+	decoded, err := base64.URLEncoding.DecodeString(id)
+	if err != nil {
+		return quizQuestion{}, invalid
+	}
+
+	var q quizQuestion
+	var kind, direction string
+	if _, err := fmt.Sscanf(strings.ReplaceAll(string(decoded), ":", " "), "%s %s %d %d", &kind, &direction, &q.translationID, &q.sentenceID); err != nil {
+		return quizQuestion{}, invalid
+	}
+	q.kind = model.QuestionKind(kind)
+	q.direction = model.QuizDirection(direction)
+	if !q.kind.IsValid() || !q.direction.IsValid() {
+		return quizQuestion{}, invalid
+	}
+	return q, nil
+}
+
+// Matches the translation and its forms (e.g. bikes for bike) in a sentence
+func clozePattern(english string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(english) + `\w*`)
+}
+
+// Answers are compared ignoring case and extra whitespace
+func normalizeAnswer(answer string) string {
+	return strings.ToLower(strings.Join(strings.Fields(answer), " "))
+}
+
+// Builds a quiz from random translations. Question kinds are used in turns; translations without a sentence
+// containing them can't be cloze questions and translations without distractors can't be multiple choice,
+// so they are asked as typing questions instead
+func (r *DictionaryService) GenerateQuiz(size *int32, direction *model.QuizDirection, kinds []model.QuestionKind) ([]*model.QuizQuestion, error) {
+	count := DefaultQuizSize
+	if size != nil {
+		if *size < 1 || *size > MaxQuizSize {
+			return nil, customerrors.InvalidPageSizeError{First: int(*size), Max: MaxQuizSize}
+		}
+		count = int(*size)
+	}
+
+	dir := model.QuizDirectionPlEn
+	if direction != nil {
+		dir = *direction
+	}
+	if len(kinds) == 0 {
+		kinds = model.AllQuestionKind
+	}
+
+	var cards []dbmodels.Card
+	if err := r.repository.RandomCards(count, &cards); err != nil {
+		return nil, err
+	}
+
+	var pool []dbmodels.Word
+	if err := r.repository.RandomWords(count*QuizChoices, &pool); err != nil {
+		return nil, err
+	}
+
+	//every translation of the asked word is a correct answer, so none of them can be a distractor
+	polish := make([]string, 0, len(cards))
+	for _, c := range cards {
+		polish = append(polish, c.Polish)
+	}
+	var asked []dbmodels.Word
+	if err := r.repository.GetWords(polish, &asked); err != nil {
+		return nil, err
+	}
+	translationsOf := make(map[string][]string, len(asked))
+	for _, w := range asked {
+		for _, t := range w.Translations {
+			translationsOf[w.Polish] = append(translationsOf[w.Polish], t.English)
+		}
+	}
+
+	questions := make([]*model.QuizQuestion, 0, len(cards))
+	for i := range cards {
+		card := &cards[i]
+		q := quizQuestion{kind: kinds[i%len(kinds)], direction: dir, translationID: card.Translation.ID}
+		question := &model.QuizQuestion{Kind: q.kind, Choices: []string{}}
+
+		switch q.kind {
+		case model.QuestionKindCloze:
+			question.Prompt, q.sentenceID = clozePrompt(card)
+		case model.QuestionKindMultipleChoice:
+			question.Choices = choices(card, dir, pool, translationsOf[card.Polish])
+		}
+
+		if (q.kind == model.QuestionKindCloze && question.Prompt == "") || (q.kind == model.QuestionKindMultipleChoice && len(question.Choices) < 2) {
+			q.kind = model.QuestionKindTyping
+			question.Kind = q.kind
+			question.Choices = []string{}
+		}
+		if q.kind != model.QuestionKindCloze {
+			question.Prompt = card.Polish
+			if dir == model.QuizDirectionEnPl {
+				question.Prompt = card.Translation.English
+			}
+		}
+
+		question.ID = q.id()
+		questions = append(questions, question)
+	}
+	return questions, nil
+}
+
+// Returns the first sentence containing the translation with the translation blanked out and the polish word as a hint
+func clozePrompt(card *dbmodels.Card) (string, uint) {
+	pattern := clozePattern(card.Translation.English)
+	for _, s := range card.Translation.Sentences {
+		if loc := pattern.FindStringIndex(s.Sentence); loc != nil {
+			return s.Sentence[:loc[0]] + ClozeBlank + s.Sentence[loc[1]:] + " (" + card.Polish + ")", s.ID
+		}
+	}
+	return "", 0
+}
+
+// Returns the correct answer and up to QuizChoices-1 distractors taken from random words, in random order.
+// translations are all translations of the asked word, which can't be distractors
+func choices(card *dbmodels.Card, direction model.QuizDirection, pool []dbmodels.Word, translations []string) []string {
+	correct := card.Translation.English
+	excluded := map[string]bool{}
+	candidates := []string{}
+
+	if direction == model.QuizDirectionEnPl {
+		correct = card.Polish
+		for _, w := range pool {
+			if !translatedAs(w, card.Translation.English) {
+				candidates = append(candidates, w.Polish)
+			}
+		}
+	} else {
+		for _, t := range translations {
+			excluded[normalizeAnswer(t)] = true
+		}
+		for _, w := range pool {
+			for _, t := range w.Translations {
+				candidates = append(candidates, t.English)
+			}
+		}
+	}
+	excluded[normalizeAnswer(correct)] = true
+
+	rand.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+
+	result := []string{correct}
+	for _, c := range candidates {
+		if len(result) == QuizChoices {
+			break
+		}
+		if !excluded[normalizeAnswer(c)] {
+			excluded[normalizeAnswer(c)] = true
+			result = append(result, c)
+		}
+	}
+
+	rand.Shuffle(len(result), func(i, j int) { result[i], result[j] = result[j], result[i] })
+	return result
+}
+
+// Words which are also translated as the asked english word would be correct answers too
+func translatedAs(word dbmodels.Word, english string) bool {
+	for _, t := range word.Translations {
+		if normalizeAnswer(t.English) == normalizeAnswer(english) {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks answers against the dictionary. Any translation of the asked word is accepted, and in cloze
+// questions also the form of the word used in the sentence
+func (r *DictionaryService) SubmitQuiz(answers []*model.QuizAnswer) (*model.QuizResult, error) {
+	result := &model.QuizResult{Total: int32(len(answers)), Answers: []*model.QuizAnswerResult{}}
+
+	for _, a := range answers {
+		q, err := parseQuestionID(a.QuestionID)
+		if err != nil {
+			return nil, err
+		}
+
+		accepted, err := r.acceptedAnswers(q)
+		if err != nil {
+			return nil, err
+		}
+
+		correct := false
+		for _, answer := range accepted {
+			correct = correct || normalizeAnswer(answer) == normalizeAnswer(a.Answer)
+		}
+		if correct {
+			result.Score++
+		}
+		result.Answers = append(result.Answers, &model.QuizAnswerResult{
+			QuestionID: a.QuestionID, Answer: a.Answer, Correct: correct, AcceptedAnswers: accepted,
+		})
+	}
+	return result, nil
+}
+
+func (r *DictionaryService) acceptedAnswers(q quizQuestion) ([]string, error) {
+	var card dbmodels.Card
+	if err := r.repository.GetCard(q.translationID, &card); err != nil {
+		return nil, err
+	}
+
+	//questions are asked in any pair, so the words are looked up in the pair of the card
+	repository := r.repository.InPair(LanguagePair{Source: card.Language, Target: card.Translation.Language})
+
+	if q.kind == model.QuestionKindCloze {
+		accepted := []string{card.Translation.English}
+		for _, s := range card.Translation.Sentences {
+			form := clozePattern(card.Translation.English).FindString(s.Sentence)
+			if s.ID == q.sentenceID && form != "" && normalizeAnswer(form) != normalizeAnswer(card.Translation.English) {
+				accepted = append(accepted, form)
+			}
+		}
+		return accepted, nil
+	}
+
+	if q.direction == model.QuizDirectionEnPl {
+		var words []dbmodels.Word
+		if err := repository.GetWordsByEnglish(card.Translation.English, &words); err != nil {
+			return nil, err
+		}
+		accepted := []string{}
+		for _, w := range words {
+			accepted = append(accepted, w.Polish)
+		}
+		return accepted, nil
+	}
+
+	var word dbmodels.Word
+	if err := repository.GetWord(card.Polish, &word); err != nil {
+		return nil, err
+	}
+	accepted := []string{}
+	for _, t := range word.Translations {
+		accepted = append(accepted, t.English)
+	}
+	return accepted, nil
+}
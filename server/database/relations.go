@@ -0,0 +1,120 @@
+package database
+
+import (
+	"errors"
+
+	dbmodels "github.com/staszkiet/DictionaryGolang/server/database/models"
+	customerrors "github.com/staszkiet/DictionaryGolang/server/errors"
+	"github.com/staszkiet/DictionaryGolang/server/graph/model"
+)
+
+// Relations which read the same from either end. They are stored once, in the direction they were created
+var symmetricRelations = map[model.RelationKind]bool{
+	model.RelationKindSynonym:     true,
+	model.RelationKindAntonym:     true,
+	model.RelationKindFalseFriend: true,
+}
+
+// Describes an end of a relation in errors, e.g. "rower" or "rower (bicycle)"
+func relationEnd(end model.RelationEnd) string {
+	if end.Translation == nil {
+		return end.Text
+	}
+	return end.Text + " (" + *end.Translation + ")"
+}
+
+// Relations link two words or two translations, never a word with a translation or an end with itself
+func validRelation(from model.RelationEnd, to model.RelationEnd) error {
+	if (from.Translation == nil) != (to.Translation == nil) || relationEnd(from) == relationEnd(to) {
+		return customerrors.InvalidRelationError{From: relationEnd(from), To: relationEnd(to)}
+	}
+	return nil
+}
+
+// Fills the ends of relation errors returned by the repository
+func relationError(err error, from model.RelationEnd, to model.RelationEnd) error {
+	var exists customerrors.RelationExistsError
+	if errors.As(err, &exists) {
+		exists.From, exists.To = relationEnd(from), relationEnd(to)
+		return exists
+	}
+	var notExists customerrors.RelationNotExistsError
+	if errors.As(err, &notExists) {
+		notExists.From, notExists.To = relationEnd(from), relationEnd(to)
+		return notExists
+	}
+	return err
+}
+
+// Finds the ids of both ends of a relation, which are words or translations of the words
+func relationEndIDs(txRepo IRepository, from model.RelationEnd, to model.RelationEnd) (uint, uint, error) {
+	ids := [2]uint{}
+	for i, end := range []model.RelationEnd{from, to} {
+		if end.Translation == nil {
+			var word dbmodels.Word
+			if err := txRepo.GetWord(end.Text, &word); err != nil {
+				return 0, 0, err
+			}
+			ids[i] = word.ID
+		} else {
+			var translation dbmodels.Translation
+			if err := txRepo.GetTranslation(end.Text, *end.Translation, &translation); err != nil {
+				return 0, 0, err
+			}
+			ids[i] = translation.ID
+		}
+	}
+	return ids[0], ids[1], nil
+}
+
+// Links two words, or two translations when both ends give one, with a relation of given kind
+func (r *DictionaryService) AddRelation(kind model.RelationKind, from model.RelationEnd, to model.RelationEnd) (*model.MutationResult, error) {
+	if err := validRelation(from, to); err != nil {
+		return nil, err
+	}
+
+	_, err := r.repository.WithTransaction(func(txRepo IRepository) error {
+		fromID, toID, err := relationEndIDs(txRepo, from, to)
+		if err != nil {
+			return err
+		}
+
+		if from.Translation == nil {
+			err = txRepo.AddWordRelation(&dbmodels.WordRelation{Kind: string(kind), WordID: fromID, RelatedID: toID}, symmetricRelations[kind])
+		} else {
+			err = txRepo.AddTranslationRelation(&dbmodels.TranslationRelation{Kind: string(kind), TranslationID: fromID, RelatedID: toID}, symmetricRelations[kind])
+		}
+		return relationError(err, from, to)
+	}, false, false)
+
+	if err != nil {
+		return nil, err
+	}
+	return r.mutationResult(from.Text, nil, model.MutationOutcomeUpdated)
+}
+
+// Deletes a relation between two words or two translations
+func (r *DictionaryService) RemoveRelation(kind model.RelationKind, from model.RelationEnd, to model.RelationEnd) (*model.MutationResult, error) {
+	if err := validRelation(from, to); err != nil {
+		return nil, err
+	}
+
+	_, err := r.repository.WithTransaction(func(txRepo IRepository) error {
+		fromID, toID, err := relationEndIDs(txRepo, from, to)
+		if err != nil {
+			return err
+		}
+
+		if from.Translation == nil {
+			err = txRepo.DeleteWordRelation(string(kind), fromID, toID, symmetricRelations[kind])
+		} else {
+			err = txRepo.DeleteTranslationRelation(string(kind), fromID, toID, symmetricRelations[kind])
+		}
+		return relationError(err, from, to)
+	}, false, false)
+
+	if err != nil {
+		return nil, err
+	}
+	return r.mutationResult(from.Text, nil, model.MutationOutcomeDeleted)
+}
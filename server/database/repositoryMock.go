@@ -1,6 +1,8 @@
 package database
 
 import (
+	"time"
+
 	dbmodels "github.com/staszkiet/DictionaryGolang/server/database/models"
 	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
@@ -25,11 +27,71 @@ func (m *MockRepository) AddSentences(sentences []dbmodels.Sentence) error {
 	return args.Error(0)
 }
 
+func (m *MockRepository) AddWords(words []dbmodels.Word) error {
+	args := m.Called(words)
+	return args.Error(0)
+}
+
+func (m *MockRepository) AddTranslations(translations []dbmodels.Translation) error {
+	args := m.Called(translations)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetWords(polish []string, words *[]dbmodels.Word) error {
+	args := m.Called(polish, words)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteWords(polish []string) error {
+	args := m.Called(polish)
+	return args.Error(0)
+}
+
 func (m *MockRepository) GetWord(polish string, word *dbmodels.Word) error {
 	args := m.Called(word)
 	return args.Error(0)
 }
 
+func (m *MockRepository) GetWordIgnoringDiacritics(polish string, word *dbmodels.Word) error {
+	args := m.Called(polish, word)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetWordByForm(form string, word *dbmodels.Word) error {
+	args := m.Called(form, word)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetSimilarWords(polish string, limit int, words *[]string) error {
+	args := m.Called(polish, limit, words)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListWords(query WordsQuery, words *[]dbmodels.Word) error {
+	args := m.Called(query, words)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetWordsByEnglish(english string, words *[]dbmodels.Word) error {
+	args := m.Called(english, words)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Search(query SearchQuery, hits *[]dbmodels.SearchHit) error {
+	args := m.Called(query, hits)
+	return args.Error(0)
+}
+
+func (m *MockRepository) CompletePolish(prefix string, limit int, words *[]string) error {
+	args := m.Called(prefix, limit, words)
+	return args.Error(0)
+}
+
+func (m *MockRepository) CompleteEnglish(prefix string, limit int, words *[]string) error {
+	args := m.Called(prefix, limit, words)
+	return args.Error(0)
+}
+
 func (m *MockRepository) GetSentence(polish string, english string, sentence string, s *dbmodels.Sentence) error {
 
 	args := m.Called(polish, english, sentence, s)
@@ -71,12 +133,48 @@ func (m *MockRepository) UpdateTranslation(translation *dbmodels.Translation, ne
 	return args.Error(0)
 }
 
-func (m *MockRepository) UpdateSentence(sentence *dbmodels.Sentence, newSentence string) error {
+func (m *MockRepository) UpdateGrammar(word *dbmodels.Word) error {
+
+	args := m.Called(word)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UpdateCountability(translation *dbmodels.Translation, countability *string) error {
+
+	args := m.Called(translation, countability)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UpdateSentence(sentence *dbmodels.Sentence, updated dbmodels.Sentence) error {
 
-	args := m.Called(sentence, newSentence)
+	args := m.Called(sentence, updated)
 	return args.Error(0)
 }
 
+func (m *MockRepository) UpdatePronunciation(entity interface{}, pronunciation *dbmodels.Pronunciation) error {
+
+	args := m.Called(entity, pronunciation)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UpdateUsage(translation *dbmodels.Translation, usage *dbmodels.Usage) error {
+
+	args := m.Called(translation, usage)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UpdatePositions(entity interface{}, ids []uint) error {
+
+	args := m.Called(entity, ids)
+	return args.Error(0)
+}
+
+func (m *MockRepository) WithSavepoint(fn func(repo IRepository) error) error {
+
+	m.Called(fn)
+	return fn(m)
+}
+
 func (m *MockRepository) WithTransaction(fn func(repo IRepository) error, lock_words bool, lock_translations bool) (bool, error) {
 
 	args := m.Called(fn)
@@ -92,3 +190,138 @@ func (m *MockRepository) WithTransaction(fn func(repo IRepository) error, lock_w
 func (r *MockRepository) withTx(tx *gorm.DB) IRepository {
 	return &MockRepository{}
 }
+
+func (m *MockRepository) DueCards(now time.Time, limit int, cards *[]dbmodels.Card) error {
+	args := m.Called(now, limit, cards)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetCard(translationID uint, card *dbmodels.Card) error {
+	args := m.Called(translationID, card)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SaveReview(review *dbmodels.ReviewState) error {
+	args := m.Called(review)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RandomCards(limit int, cards *[]dbmodels.Card) error {
+	args := m.Called(limit, cards)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RandomWords(limit int, words *[]dbmodels.Word) error {
+	args := m.Called(limit, words)
+	return args.Error(0)
+}
+
+func (m *MockRepository) AddInflection(inflection *dbmodels.Inflection) error {
+	args := m.Called(inflection)
+	return args.Error(0)
+}
+
+func (m *MockRepository) AddInflections(inflections []dbmodels.Inflection) error {
+	args := m.Called(inflections)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteInflection(polish string, form string, tags *string) error {
+	args := m.Called(polish, form, tags)
+	return args.Error(0)
+}
+
+func (m *MockRepository) AddWordRelation(relation *dbmodels.WordRelation, symmetric bool) error {
+	args := m.Called(relation, symmetric)
+	return args.Error(0)
+}
+
+func (m *MockRepository) AddTranslationRelation(relation *dbmodels.TranslationRelation, symmetric bool) error {
+	args := m.Called(relation, symmetric)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteWordRelation(kind string, wordID uint, relatedID uint, symmetric bool) error {
+	args := m.Called(kind, wordID, relatedID, symmetric)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteTranslationRelation(kind string, translationID uint, relatedID uint, symmetric bool) error {
+	args := m.Called(kind, translationID, relatedID, symmetric)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetOrCreateTags(names []string, tags *[]dbmodels.Tag) error {
+	args := m.Called(names, tags)
+	return args.Error(0)
+}
+
+func (m *MockRepository) TagWord(word *dbmodels.Word, tags []dbmodels.Tag) error {
+	args := m.Called(word, tags)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UntagWord(word *dbmodels.Word, tags []dbmodels.Tag) error {
+	args := m.Called(word, tags)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetTag(name string, tag *dbmodels.TagCount) error {
+	args := m.Called(name, tag)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListTags(tags *[]dbmodels.TagCount) error {
+	args := m.Called(tags)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RenameTag(tagID uint, newName string) error {
+	args := m.Called(tagID, newName)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteTag(tagID uint) error {
+	args := m.Called(tagID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) AddCollection(collection *dbmodels.Collection) error {
+	args := m.Called(collection)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetCollection(name string, collection *dbmodels.Collection) error {
+	args := m.Called(name, collection)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListCollections(collections *[]dbmodels.Collection) error {
+	args := m.Called(collections)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RenameCollection(collection *dbmodels.Collection, newName string) error {
+	args := m.Called(collection, newName)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteCollection(collection *dbmodels.Collection) error {
+	args := m.Called(collection)
+	return args.Error(0)
+}
+
+func (m *MockRepository) AddCollectionItem(item *dbmodels.CollectionItem) error {
+	args := m.Called(item)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteCollectionItem(collectionID uint, translationID uint) error {
+	args := m.Called(collectionID, translationID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) InPair(pair LanguagePair) IRepository {
+	m.Called(pair)
+	return m
+}
@@ -1,12 +1,20 @@
 package database
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	dbmodels "github.com/staszkiet/DictionaryGolang/server/database/models"
 	customerrors "github.com/staszkiet/DictionaryGolang/server/errors"
+	"github.com/staszkiet/DictionaryGolang/server/events"
 
 	"github.com/staszkiet/DictionaryGolang/server/graph/model"
+	"github.com/staszkiet/DictionaryGolang/server/study"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -47,10 +55,10 @@ func TestCreateWordOrAddTranslationOrSentence_WhenDataIsValid_ShouldReturnSucces
 			assert.Equal(t, expectedWord.Translations[0].English, wordArg.Translations[0].English)
 			assert.ElementsMatch(t, expectedWord.Translations[0].Sentences, wordArg.Translations[0].Sentences)
 		})
-	success, err := dbService.CreateWordOrAddTranslationOrSentence(polish, translation)
+	result, err := dbService.CreateWordOrAddTranslationOrSentence(polish, translation)
 
 	assert.NoError(t, err)
-	assert.True(t, success)
+	assert.Equal(t, model.MutationOutcomeCreated, result.Outcome)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -103,10 +111,11 @@ func TestCreateWordOrAddTranslationOrSentence_WordExistsButTranslationDoesnt_Sho
 			assert.Equal(t, expectedTranslation, wordArg)
 		})
 
-	success, err := dbService.CreateWordOrAddTranslationOrSentence(polish, translation)
+	result, err := dbService.CreateWordOrAddTranslationOrSentence(polish, translation)
 
 	assert.NoError(t, err)
-	assert.True(t, success)
+	assert.Equal(t, model.MutationOutcomeMerged, result.Outcome)
+	assert.Equal(t, "pisać", result.Word.Polish)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -158,11 +167,11 @@ func TestCreateWordOrAddTranslationOrSentence_WordAndTranslationAlreadyExistsBut
 			assert.Equal(t, expectedSentence, wordArg)
 		})
 
-	success, err := dbService.CreateWordOrAddTranslationOrSentence(polish,
+	result, err := dbService.CreateWordOrAddTranslationOrSentence(polish,
 		model.NewTranslation{English: English, Sentences: []string{sentence}})
 
 	assert.NoError(t, err)
-	assert.True(t, success)
+	assert.Equal(t, model.MutationOutcomeMerged, result.Outcome)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -201,11 +210,11 @@ func TestCreateWord_WordTranslationAndAllSentencesAlreadyExist(t *testing.T) {
 		*(wordArg) = *(dbTranslation)
 	})
 
-	success, err := dbService.CreateWordOrAddTranslationOrSentence(polish,
+	result, err := dbService.CreateWordOrAddTranslationOrSentence(polish,
 		model.NewTranslation{English: English, Sentences: []string{sentence}})
 
 	assert.Nil(t, err)
-	assert.False(t, success)
+	assert.Equal(t, model.MutationOutcomeNoop, result.Outcome)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -233,10 +242,12 @@ func TestDeleteSentence_SentenceExists_Success(t *testing.T) {
 			assert.Equal(t, wordArg, dbSentence)
 		})
 
-	success, err := dbService.DeleteSentence(polish, English, sentence)
+	mockRepo.On("GetWord", mock.Anything).Return(nil)
+
+	result, err := dbService.DeleteSentence(polish, English, sentence)
 
 	assert.NoError(t, err)
-	assert.True(t, success)
+	assert.Equal(t, model.MutationOutcomeDeleted, result.Outcome)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -254,10 +265,10 @@ func TestDeleteSentence_SentenceDoesntExist_Success(t *testing.T) {
 	mockRepo.On("WithTransaction", mock.Anything).Return(false)
 	mockRepo.On("GetSentence", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(expectedError)
 
-	success, err := dbService.DeleteSentence(polish, English, sentence)
+	result, err := dbService.DeleteSentence(polish, English, sentence)
 
 	assert.Error(t, err)
-	assert.False(t, success)
+	assert.Nil(t, result)
 	assert.Equal(t, expectedError, err)
 
 	mockRepo.AssertExpectations(t)
@@ -292,10 +303,12 @@ func TestDeleteTranslation_TranslationExists_Success(t *testing.T) {
 			assert.ElementsMatch(t, wordArg.Sentences, dbTranslation.Sentences)
 		})
 
-	success, err := dbService.DeleteTranslation(polish, English)
+	mockRepo.On("GetWord", mock.Anything).Return(nil)
+
+	result, err := dbService.DeleteTranslation(polish, English)
 
 	assert.NoError(t, err)
-	assert.True(t, success)
+	assert.Equal(t, model.MutationOutcomeDeleted, result.Outcome)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -312,10 +325,12 @@ func TestDeleteTranslation_TranslationOrWordDoesntExist_Success(t *testing.T) {
 	mockRepo.On("WithTransaction", mock.Anything).Return(false)
 	mockRepo.On("GetTranslation", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(expectedError)
 
-	success, err := dbService.DeleteTranslation(polish, English)
+	mockRepo.On("GetWord", mock.Anything).Return(customerrors.WordNotExistsError{Word: polish})
+
+	result, err := dbService.DeleteTranslation(polish, English)
 
 	assert.Nil(t, err)
-	assert.False(t, success)
+	assert.Equal(t, model.MutationOutcomeNoop, result.Outcome)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -323,16 +338,26 @@ func TestDeleteTranslation_TranslationOrWordDoesntExist_Success(t *testing.T) {
 func TestDeleteWord_WordExists_Success(t *testing.T) {
 
 	mockRepo := new(MockRepository)
-	dbService := &DictionaryService{repository: mockRepo}
+	clips := &fakeAudioStore{}
+	dbService := &DictionaryService{repository: mockRepo, audio: clips}
 
 	polish := "książka"
+	clip, translationClip := "a1.mp3", "b2.ogg"
 
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("GetWord", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(0).(*dbmodels.Word) = dbmodels.Word{Polish: polish, Pronunciation: dbmodels.Pronunciation{Audio: &clip},
+			Translations: []dbmodels.Translation{{English: "book", Pronunciation: dbmodels.Pronunciation{Audio: &translationClip}}}}
+	}).Once()
 	mockRepo.On("DeleteWord", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("GetWord", mock.Anything).Return(customerrors.WordNotExistsError{Word: polish})
 
-	success, err := dbService.DeleteWord(polish)
+	result, err := dbService.DeleteWord(polish)
 
 	assert.NoError(t, err)
-	assert.True(t, success)
+	assert.Equal(t, model.MutationOutcomeDeleted, result.Outcome)
+	assert.Nil(t, result.Word)
+	assert.Equal(t, []string{clip, translationClip}, clips.deleted)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -344,14 +369,16 @@ func TestDeleteWord_WordDoesntExist_Success(t *testing.T) {
 
 	polish := "książka"
 
-	mockRepo.On("DeleteWord", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("WithTransaction", mock.Anything).Return(false)
+	mockRepo.On("GetWord", mock.Anything).Return(customerrors.WordNotExistsError{Word: polish})
 
-	success, err := dbService.DeleteWord(polish)
+	result, err := dbService.DeleteWord(polish)
 
 	assert.Nil(t, err)
-	assert.True(t, success)
+	assert.Equal(t, model.MutationOutcomeNoop, result.Outcome)
 
 	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "DeleteWord", mock.Anything)
 }
 
 func TestUpdateWord_WordToUpdateExists_Success(t *testing.T) {
@@ -383,10 +410,10 @@ func TestUpdateWord_WordToUpdateExists_Success(t *testing.T) {
 
 	mockRepo.On("UpdateWord", mock.Anything, mock.Anything).Return(nil)
 
-	success, err := dbService.UpdateWord(polish, newPolish)
+	result, err := dbService.UpdateWord(polish, newPolish)
 
 	assert.NoError(t, err)
-	assert.True(t, success)
+	assert.Equal(t, model.MutationOutcomeUpdated, result.Outcome)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -403,10 +430,10 @@ func TestUpdateWord_WordToUpdateDoesntExist_ShouldReturnError(t *testing.T) {
 
 	mockRepo.On("GetWord", mock.Anything, mock.Anything, mock.Anything).Return(expectedError)
 
-	success, err := dbService.UpdateWord(polish, newPolish)
+	result, err := dbService.UpdateWord(polish, newPolish)
 
 	assert.Error(t, err)
-	assert.False(t, success)
+	assert.Nil(t, result)
 	assert.Equal(t, expectedError, err)
 
 	mockRepo.AssertExpectations(t)
@@ -443,10 +470,10 @@ func TestUpdateWord_UpdatedWordExists_ShouldReturnError(t *testing.T) {
 
 	mockRepo.On("UpdateWord", mock.Anything, mock.Anything).Return(expectedError)
 
-	success, err := dbService.UpdateWord(polish, newPolish)
+	result, err := dbService.UpdateWord(polish, newPolish)
 
 	assert.Error(t, err)
-	assert.False(t, success)
+	assert.Nil(t, result)
 	assert.Equal(t, expectedError, err)
 
 	mockRepo.AssertExpectations(t)
@@ -473,10 +500,12 @@ func TestUpdateSentence_SentenceToUpdateExists_Success(t *testing.T) {
 
 	mockRepo.On("UpdateSentence", mock.Anything, mock.Anything).Return(nil)
 
-	success, err := dbService.UpdateSentence(polish, English, sentence, newSentence)
+	mockRepo.On("GetWord", mock.Anything).Return(nil)
+
+	result, err := dbService.UpdateSentence(polish, English, sentence, model.SentenceInput{Sentence: newSentence})
 
 	assert.NoError(t, err)
-	assert.True(t, success)
+	assert.Equal(t, model.MutationOutcomeUpdated, result.Outcome)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -495,10 +524,10 @@ func TestUpdateSentence_SentenceToUpdateDoesntExist_ShouldReturnError(t *testing
 
 	mockRepo.On("GetSentence", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(expectedError)
 
-	success, err := dbService.UpdateSentence(polish, English, sentence, newSentence)
+	result, err := dbService.UpdateSentence(polish, English, sentence, model.SentenceInput{Sentence: newSentence})
 
 	assert.Error(t, err)
-	assert.False(t, success)
+	assert.Nil(t, result)
 	assert.Equal(t, expectedError, err)
 
 	mockRepo.AssertExpectations(t)
@@ -528,10 +557,10 @@ func TestUpdateSentence_UpdatedSentenceExists_ShouldReturnError(t *testing.T) {
 
 	mockRepo.On("UpdateSentence", mock.Anything, mock.Anything).Return(expectedError)
 
-	success, err := dbService.UpdateSentence(polish, English, sentence, newSentence)
+	result, err := dbService.UpdateSentence(polish, English, sentence, model.SentenceInput{Sentence: newSentence})
 
 	assert.Error(t, err)
-	assert.False(t, success)
+	assert.Nil(t, result)
 	assert.Equal(t, expectedError, err)
 
 	mockRepo.AssertExpectations(t)
@@ -563,10 +592,12 @@ func TestUpdateTranslation_TranslationToUpdateExists_Success(t *testing.T) {
 
 	mockRepo.On("UpdateTranslation", mock.Anything, mock.Anything).Return(nil)
 
-	success, err := dbService.UpdateTranslation(polish, English, newEnglish)
+	mockRepo.On("GetWord", mock.Anything).Return(nil)
+
+	result, err := dbService.UpdateTranslation(polish, English, newEnglish)
 
 	assert.NoError(t, err)
-	assert.True(t, success)
+	assert.Equal(t, model.MutationOutcomeUpdated, result.Outcome)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -585,10 +616,10 @@ func TestUpdateTranslation_TranslationToUpdateDoesntExist_ShouldReturnError(t *t
 
 	mockRepo.On("GetTranslation", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(expectedError)
 
-	success, err := dbService.UpdateTranslation(polish, English, newEnglish)
+	result, err := dbService.UpdateTranslation(polish, English, newEnglish)
 
 	assert.Error(t, err)
-	assert.False(t, success)
+	assert.Nil(t, result)
 	assert.Equal(t, expectedError, err)
 
 	mockRepo.AssertExpectations(t)
@@ -622,10 +653,10 @@ func TestUpdateTranslation_UpdatedTranslationExists_ShouldReturnError(t *testing
 
 	mockRepo.On("UpdateTranslation", mock.Anything, mock.Anything).Return(expectedError)
 
-	success, err := dbService.UpdateTranslation(polish, English, newEnglish)
+	result, err := dbService.UpdateTranslation(polish, English, newEnglish)
 
 	assert.Error(t, err)
-	assert.False(t, success)
+	assert.Nil(t, result)
 	assert.Equal(t, expectedError, err)
 
 	mockRepo.AssertExpectations(t)
@@ -651,16 +682,24 @@ func TestSelectWord_WordExists_Success(t *testing.T) {
 	}
 
 	expectedWord := &model.Word{
+		Text:   "dom",
 		Polish: "dom",
 		Translations: []*model.Translation{
 			{
+				Text:    "house",
 				English: "house",
 				Sentences: []*model.Sentence{
 					{Sentence: "This is my house"},
 					{Sentence: "I bought a new house"},
 				},
+				Related: []*model.Relation{},
+				Labels:  []model.UsageLabel{},
 			},
 		},
+		Inflections:  []*model.Inflection{},
+		MatchedForms: []*model.Inflection{},
+		Related:      []*model.Relation{},
+		Tags:         []string{},
 	}
 
 	mockRepo.On("GetWord", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
@@ -685,6 +724,9 @@ func TestSelectWord_WordDoesntExist_ShouldReturnError(t *testing.T) {
 	expectedError := customerrors.WordNotExistsError{Word: polish}
 
 	mockRepo.On("GetWord", mock.Anything, mock.Anything).Return(expectedError)
+	mockRepo.On("GetWordByForm", polish, mock.Anything).Return(expectedError)
+	mockRepo.On("GetWordIgnoringDiacritics", polish, mock.Anything).Return(expectedError)
+	mockRepo.On("GetSimilarWords", polish, SuggestionsLimit, mock.Anything).Return(nil)
 
 	retWord, err := dbService.SelectWord(polish)
 
@@ -694,3 +736,1657 @@ func TestSelectWord_WordDoesntExist_ShouldReturnError(t *testing.T) {
 
 	mockRepo.AssertExpectations(t)
 }
+
+func TestListWords_MoreWordsThanPageSize_ShouldReturnPageWithNextCursor(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	first := int32(2)
+	prefix := "k"
+
+	dbWords := []dbmodels.Word{
+		{Polish: "kot", Translations: []dbmodels.Translation{{English: "cat"}}},
+		{Polish: "koń", Translations: []dbmodels.Translation{{English: "horse"}}},
+		{Polish: "krowa", Translations: []dbmodels.Translation{{English: "cow"}}},
+	}
+
+	mockRepo.On("ListWords", WordsQuery{Prefix: prefix, Limit: 3}, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		wordsArg := args.Get(1).(*[]dbmodels.Word)
+		*(wordsArg) = dbWords
+	})
+
+	connection, err := dbService.ListWords(&first, nil, &prefix, nil, nil, nil)
+
+	assert.NoError(t, err)
+	assert.Len(t, connection.Edges, 2)
+	assert.Equal(t, "kot", connection.Edges[0].Node.Polish)
+	assert.Equal(t, "koń", connection.Edges[1].Node.Polish)
+	assert.True(t, connection.PageInfo.HasNextPage)
+	assert.Equal(t, encodeCursor("koń"), *connection.PageInfo.EndCursor)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListWords_AfterCursorDescending_ShouldPassDecodedCursorToRepository(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	after := encodeCursor("koń")
+	order := model.SortOrderDesc
+
+	mockRepo.On("ListWords", WordsQuery{After: "koń", Descending: true, Limit: DefaultPageSize + 1}, mock.Anything).Return(nil)
+
+	connection, err := dbService.ListWords(nil, &after, nil, &order, nil, nil)
+
+	assert.NoError(t, err)
+	assert.Empty(t, connection.Edges)
+	assert.False(t, connection.PageInfo.HasNextPage)
+	assert.Nil(t, connection.PageInfo.EndCursor)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListWords_InvalidArguments_ShouldReturnError(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	first := int32(0)
+	after := "%%%"
+
+	_, err := dbService.ListWords(&first, nil, nil, nil, nil, nil)
+	assert.Equal(t, customerrors.InvalidPageSizeError{First: 0, Max: MaxPageSize}, err)
+
+	_, err = dbService.ListWords(nil, &after, nil, nil, nil, nil)
+	assert.Equal(t, customerrors.InvalidCursorError{Cursor: after}, err)
+
+	mockRepo.AssertNotCalled(t, "ListWords", mock.Anything, mock.Anything)
+}
+
+func TestSelectByEnglish_TranslationExists_Success(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	english := "bike"
+
+	dbWords := []dbmodels.Word{
+		{Polish: "motocykl", Translations: []dbmodels.Translation{{English: "bike"}, {English: "motorcycle"}}},
+		{Polish: "rower", Translations: []dbmodels.Translation{{English: "bike", Sentences: []dbmodels.Sentence{{Sentence: "I like my bike"}}}}},
+	}
+
+	mockRepo.On("GetWordsByEnglish", english, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		wordsArg := args.Get(1).(*[]dbmodels.Word)
+		*(wordsArg) = dbWords
+	})
+
+	words, err := dbService.SelectByEnglish(english)
+
+	assert.NoError(t, err)
+	assert.Len(t, words, 2)
+	assert.Equal(t, "motocykl", words[0].Polish)
+	assert.Equal(t, "rower", words[1].Polish)
+	assert.Equal(t, "I like my bike", words[1].Translations[0].Sentences[0].Sentence)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSelectByEnglish_TranslationDoesntExist_ShouldReturnError(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	english := "bike"
+	expectedError := customerrors.EnglishWordNotExistsError{English: english}
+
+	mockRepo.On("GetWordsByEnglish", english, mock.Anything).Return(expectedError)
+
+	words, err := dbService.SelectByEnglish(english)
+
+	assert.Error(t, err)
+	assert.Nil(t, words)
+	assert.Equal(t, expectedError, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSearch_ScopeGiven_ShouldSearchOnlyThatTableAndConvertHits(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	text := "bike"
+	scope := model.SearchScopeSentences
+
+	dbHits := []dbmodels.SearchHit{
+		{Kind: dbmodels.SearchHitSentence, Polish: "rower", English: "bike", Sentence: "I like my bike", Rank: 0.6, Snippet: "I like my <b>bike</b>"},
+	}
+
+	mockRepo.On("Search", SearchQuery{Text: text, Sentences: true, Limit: SearchLimit}, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		hitsArg := args.Get(1).(*[]dbmodels.SearchHit)
+		*(hitsArg) = dbHits
+	})
+
+	results, err := dbService.Search(text, &scope, nil, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.SearchResult{
+		&model.SentenceHit{Polish: "rower", English: "bike", Sentence: "I like my bike", Rank: 0.6, Snippet: "I like my <b>bike</b>"},
+	}, results)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSearch_NoScope_ShouldSearchEverywhere(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	mockRepo.On("Search", SearchQuery{Text: "dom", Words: true, Translations: true, Sentences: true, Limit: SearchLimit}, mock.Anything).Return(nil)
+
+	results, err := dbService.Search("dom", nil, nil, nil)
+
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSelectWord_WordWrittenWithoutDiacritics_ShouldReturnWord(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	polish := "zolw"
+
+	dbWord := &dbmodels.Word{
+		Polish:       "żółw",
+		Translations: []dbmodels.Translation{{English: "turtle"}},
+	}
+
+	mockRepo.On("GetWord", mock.Anything, mock.Anything).Return(customerrors.WordNotExistsError{Word: polish})
+	mockRepo.On("GetWordByForm", polish, mock.Anything).Return(customerrors.WordNotExistsError{Word: polish})
+	mockRepo.On("GetWordIgnoringDiacritics", polish, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		wordArg := args.Get(1).(*dbmodels.Word)
+		*(wordArg) = *(dbWord)
+	})
+
+	retWord, err := dbService.SelectWord(polish)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "żółw", retWord.Polish)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSelectWord_SimilarWordsExist_ShouldReturnErrorWithSuggestions(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	polish := "rowr"
+	notExists := customerrors.WordNotExistsError{Word: polish}
+
+	mockRepo.On("GetWord", mock.Anything, mock.Anything).Return(notExists)
+	mockRepo.On("GetWordByForm", polish, mock.Anything).Return(notExists)
+	mockRepo.On("GetWordIgnoringDiacritics", polish, mock.Anything).Return(notExists)
+	mockRepo.On("GetSimilarWords", polish, SuggestionsLimit, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		wordsArg := args.Get(2).(*[]string)
+		*(wordsArg) = []string{"rower", "rowek"}
+	})
+
+	retWord, err := dbService.SelectWord(polish)
+
+	assert.Nil(t, retWord)
+	assert.Equal(t, customerrors.WordSuggestionsError{WordNotExistsError: notExists, Suggestions: []string{"rower", "rowek"}}, err)
+	assert.ErrorIs(t, err, notExists)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAutocomplete_EnglishLanguage_ShouldCompleteTranslations(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	language := model.LanguageEn
+	limit := int32(3)
+
+	mockRepo.On("CompleteEnglish", "bi", 3, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		wordsArg := args.Get(2).(*[]string)
+		*(wordsArg) = []string{"bicycle", "bike"}
+	})
+
+	completions, err := dbService.Autocomplete("bi", &language, &limit)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bicycle", "bike"}, completions)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAutocomplete_NoLanguage_ShouldCompletePolishWords(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	mockRepo.On("CompletePolish", "ro", DefaultCompletionsLimit, mock.Anything).Return(nil)
+
+	completions, err := dbService.Autocomplete("ro", nil, nil)
+
+	assert.NoError(t, err)
+	assert.Empty(t, completions)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAutocomplete_TargetLanguageOfPair_ShouldCompleteTranslations(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	mockRepo.On("InPair", LanguagePair{Source: "DE", Target: "PL"})
+	mockRepo.On("CompleteEnglish", "ko", DefaultCompletionsLimit, mock.Anything).Return(nil)
+	mockRepo.On("CompletePolish", "Ka", DefaultCompletionsLimit, mock.Anything).Return(nil)
+
+	german, err := dbService.InPair(&model.LanguagePair{Source: model.LanguageDe, Target: model.LanguagePl})
+	assert.NoError(t, err)
+
+	polish := model.LanguagePl
+	_, err = german.Autocomplete("ko", &polish, nil)
+	assert.NoError(t, err)
+
+	english := model.LanguageEn
+	_, err = german.Autocomplete("Ka", &english, nil)
+	assert.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestInPair_SameLanguages_ShouldReturnError(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	service, err := dbService.InPair(&model.LanguagePair{Source: model.LanguageDe, Target: model.LanguageDe})
+
+	assert.Nil(t, service)
+	assert.Equal(t, customerrors.InvalidLanguagePairError{Source: "DE", Target: "DE"}, err)
+	mockRepo.AssertNotCalled(t, "InPair", mock.Anything)
+}
+
+func TestInPair_NoPair_ShouldUseDefaultPair(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	service, err := dbService.InPair(nil)
+
+	assert.NoError(t, err)
+	assert.Same(t, dbService, service)
+	assert.Equal(t, DefaultLanguagePair, service.languagePair())
+}
+
+func TestAutocomplete_InvalidLimit_ShouldReturnError(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	limit := int32(MaxCompletionsLimit + 1)
+
+	completions, err := dbService.Autocomplete("ro", nil, &limit)
+
+	assert.Nil(t, completions)
+	assert.Equal(t, customerrors.InvalidPageSizeError{First: MaxCompletionsLimit + 1, Max: MaxCompletionsLimit}, err)
+}
+
+func TestUpdateTranslation_WordIsWatched_ShouldPublishUpdatedWord(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo, events: events.NewBroker()}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	polish := "książka"
+	changes, err := dbService.WordChanged(ctx, &polish)
+	assert.NoError(t, err)
+
+	dbWord := &dbmodels.Word{Polish: polish, Translations: []dbmodels.Translation{{English: "book"}}}
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("GetTranslation", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("UpdateTranslation", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("GetWord", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		wordArg := args.Get(0).(*dbmodels.Word)
+		*(wordArg) = *(dbWord)
+	})
+
+	_, err = dbService.UpdateTranslation(polish, "bok", "book")
+	assert.NoError(t, err)
+
+	event := <-changes
+	assert.Equal(t, model.ChangeKindUpdated, event.Kind)
+	assert.Equal(t, polish, event.Polish)
+	assert.Equal(t, "book", event.Word.Translations[0].English)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeleteTranslation_LastTranslationOfWatchedWord_ShouldPublishDeletedWord(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo, events: events.NewBroker()}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes, err := dbService.WordChanged(ctx, nil)
+	assert.NoError(t, err)
+
+	polish := "książka"
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("GetTranslation", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("GetWord", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(0).(*dbmodels.Word) = dbmodels.Word{Polish: polish, Translations: []dbmodels.Translation{{English: "book"}}}
+	}).Once()
+	mockRepo.On("DeleteTranslation", mock.Anything).Return(nil)
+	mockRepo.On("GetWord", mock.Anything).Return(customerrors.WordNotExistsError{Word: polish})
+
+	result, err := dbService.DeleteTranslation(polish, "book")
+	assert.NoError(t, err)
+	assert.Equal(t, model.MutationOutcomeCascadeDeleted, result.Outcome)
+
+	event := <-changes
+	assert.Equal(t, model.ChangeKindDeleted, event.Kind)
+	assert.Equal(t, polish, event.Polish)
+	assert.Nil(t, event.Word)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWordChanged_InPair_ShouldOnlyReceiveChangesOfThatPair(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo, events: events.NewBroker()}
+
+	mockRepo.On("InPair", LanguagePair{Source: "PL", Target: "DE"}).Return()
+	german, err := dbService.InPair(&model.LanguagePair{Source: model.LanguagePl, Target: model.LanguageDe})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	englishChanges, err := dbService.WordChanged(ctx, nil)
+	assert.NoError(t, err)
+	germanChanges, err := german.WordChanged(ctx, nil)
+	assert.NoError(t, err)
+
+	german.publish(&model.WordChangedEvent{Kind: model.ChangeKindCreated, Polish: "rower"})
+	dbService.publish(&model.WordChangedEvent{Kind: model.ChangeKindCreated, Polish: "kot"})
+
+	event := <-germanChanges
+	assert.Equal(t, "rower", event.Polish)
+	assert.Equal(t, model.LanguageDe, event.Target)
+
+	event = <-englishChanges
+	assert.Equal(t, "kot", event.Polish)
+	assert.Equal(t, model.LanguageEn, event.Target)
+}
+
+func TestTranslateUniqueViolation_ShouldReturnErrorOfViolatedTable(t *testing.T) {
+	err := translateUniqueViolation(&pgconn.PgError{
+		Code:      "23505",
+		TableName: "translations",
+		Detail:    "Key (word_id, english)=(1, bike) already exists.",
+	})
+	assert.Equal(t, customerrors.TranslationExistsError{Translation: "bike"}, err)
+
+	err = translateUniqueViolation(&pgconn.PgError{
+		Code:      "23505",
+		TableName: "sentences",
+		Detail:    "Key (translation_id, sentence)=(3, I like it, really) already exists.",
+	})
+	assert.Equal(t, customerrors.SentenceExistsError{Sentence: "I like it, really"}, err)
+}
+
+func TestTranslateUniqueViolation_OtherError_ShouldBeReturnedUnchanged(t *testing.T) {
+	pgErr := &pgconn.PgError{Code: "23503", TableName: "translations"}
+
+	assert.Equal(t, error(pgErr), translateUniqueViolation(pgErr))
+}
+
+func TestImportWords_ShouldMergeEntriesAndInsertThemInBatches(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	existing := []dbmodels.Word{{
+		ID:     1,
+		Polish: "rower",
+		Translations: []dbmodels.Translation{
+			{ID: 2, WordID: 1, English: "bike", Sentences: []dbmodels.Sentence{{Sentence: "I like my bike"}}},
+		},
+	}}
+
+	entries := []*model.NewWordEntry{
+		{Polish: "kot", Translation: &model.NewTranslation{English: "cat", Sentences: []string{}}},
+		{Polish: "rower", Translation: &model.NewTranslation{English: "bicycle", Sentences: []string{}}},
+		{Polish: "rower", Translation: &model.NewTranslation{English: "bike", Sentences: []string{"I like my bike"}}},
+		{Polish: "kot", Translation: &model.NewTranslation{English: "cat", Sentences: []string{"My cat is black"}}},
+	}
+
+	expectedWords := []dbmodels.Word{{
+		Polish:       "kot",
+		Translations: []dbmodels.Translation{{English: "cat", Sentences: []dbmodels.Sentence{{Sentence: "My cat is black"}}}},
+	}}
+	expectedTranslations := []dbmodels.Translation{{WordID: 1, English: "bicycle", Sentences: []dbmodels.Sentence{}}}
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("GetWords", []string{"kot", "rower", "rower", "kot"}, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]dbmodels.Word) = existing
+	})
+	mockRepo.On("AddWords", expectedWords).Return(nil)
+	mockRepo.On("AddTranslations", expectedTranslations).Return(nil)
+
+	results, err := dbService.ImportWords(entries, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.ImportStatusCreated, results[0].Status)
+	assert.Equal(t, model.ImportStatusMerged, results[1].Status)
+	assert.Equal(t, model.ImportStatusSkipped, results[2].Status)
+	assert.Equal(t, model.ImportStatusMerged, results[3].Status)
+	mockRepo.AssertNotCalled(t, "AddSentences", mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportWords_AtomicWithInvalidEntry_ShouldReturnError(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	entries := []*model.NewWordEntry{
+		{Polish: "kot", Translation: &model.NewTranslation{English: "cat", Sentences: []string{}}},
+		{Polish: " ", Translation: &model.NewTranslation{English: "dog", Sentences: []string{}}},
+	}
+	mode := model.ImportModeAtomic
+
+	results, err := dbService.ImportWords(entries, &mode)
+
+	assert.Nil(t, results)
+	assert.Equal(t, customerrors.InvalidEntryError{Index: 1, Field: "polish"}, err)
+	mockRepo.AssertNotCalled(t, "WithTransaction", mock.Anything)
+}
+
+func TestImportWords_BestEffortWithInvalidEntry_ShouldImportTheRest(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	entries := []*model.NewWordEntry{
+		{Polish: "kot", Translation: &model.NewTranslation{English: "", Sentences: []string{}}},
+		{Polish: "pies", Translation: &model.NewTranslation{English: "dog", Sentences: []string{}}},
+	}
+	mode := model.ImportModeBestEffort
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("WithSavepoint", mock.Anything).Return(nil)
+	mockRepo.On("GetWords", []string{"pies"}, mock.Anything).Return(nil)
+	mockRepo.On("AddWords", mock.Anything).Return(nil)
+
+	results, err := dbService.ImportWords(entries, &mode)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.ImportStatusFailed, results[0].Status)
+	assert.Equal(t, customerrors.CodeInvalidEntry, *results[0].ErrorCode)
+	assert.Equal(t, model.ImportStatusCreated, results[1].Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportWords_BestEffortWithEntryRejectedByDatabase_ShouldFailOnlyThatEntry(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	entries := []*model.NewWordEntry{
+		{Polish: "kot", Translation: &model.NewTranslation{English: "cat", Sentences: []string{}}},
+		{Polish: "pies", Translation: &model.NewTranslation{English: "dog", Sentences: []string{}}},
+		{Polish: "kot", Translation: &model.NewTranslation{English: "tomcat", Sentences: []string{}}},
+	}
+	mode := model.ImportModeBestEffort
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("WithSavepoint", mock.Anything).Return(nil).Times(3)
+	mockRepo.On("GetWords", []string{"kot"}, mock.Anything).Return(nil).Once()
+	mockRepo.On("GetWords", []string{"pies"}, mock.Anything).Return(nil)
+	mockRepo.On("GetWords", []string{"kot"}, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]dbmodels.Word) = []dbmodels.Word{{ID: 1, Polish: "kot", Translations: []dbmodels.Translation{{ID: 1, English: "cat"}}}}
+	})
+	mockRepo.On("AddWords", mock.MatchedBy(func(words []dbmodels.Word) bool { return words[0].Polish == "kot" })).Return(nil)
+	mockRepo.On("AddWords", mock.MatchedBy(func(words []dbmodels.Word) bool { return words[0].Polish == "pies" })).Return(customerrors.WordExistsError{Word: "pies"})
+	mockRepo.On("AddTranslations", mock.Anything).Return(nil)
+
+	results, err := dbService.ImportWords(entries, &mode)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.ImportStatusCreated, results[0].Status)
+	assert.Equal(t, model.ImportStatusFailed, results[1].Status)
+	assert.Equal(t, customerrors.CodeWordExists, *results[1].ErrorCode)
+	assert.Equal(t, model.ImportStatusMerged, results[2].Status)
+	assert.Nil(t, results[2].ErrorCode)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportFile_SkipStrategy_ShouldSkipRowsOfExistingWords(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	file := "polish,english,sentence\n" +
+		"rower,bicycle,\n" +
+		"kot,cat,My cat is black\n" +
+		",dog,\n"
+	conflict := model.ConflictStrategySkip
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("GetWords", []string{"rower", "kot"}, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]dbmodels.Word) = []dbmodels.Word{{ID: 1, Polish: "rower"}}
+	})
+	mockRepo.On("AddWords", mock.Anything).Return(nil)
+
+	report, err := dbService.ImportFile(strings.NewReader(file), &model.FileImportOptions{Conflict: &conflict})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), report.Created)
+	assert.Equal(t, int32(1), report.Skipped)
+	assert.Equal(t, int32(1), report.Rejected)
+	assert.Equal(t, int32(4), report.Rows[2].Line)
+	assert.Equal(t, customerrors.CodeInvalidEntry, *report.Rows[2].ErrorCode)
+	mockRepo.AssertNotCalled(t, "AddTranslations", mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportFile_OverwriteStrategy_ShouldReplaceExistingWords(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	file := "rower\tbicycle\n"
+	delimiter := "\t"
+	header := false
+	conflict := model.ConflictStrategyOverwrite
+
+	expectedWords := []dbmodels.Word{{
+		Polish:       "rower",
+		Translations: []dbmodels.Translation{{English: "bicycle", Sentences: []dbmodels.Sentence{}}},
+	}}
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("GetWords", []string{"rower"}, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]dbmodels.Word) = []dbmodels.Word{{ID: 1, Polish: "rower", Translations: []dbmodels.Translation{{ID: 2, English: "bike"}}}}
+	})
+	mockRepo.On("DeleteWords", []string{"rower"}).Return(nil)
+	mockRepo.On("AddWords", expectedWords).Return(nil)
+
+	report, err := dbService.ImportFile(strings.NewReader(file), &model.FileImportOptions{Delimiter: &delimiter, Header: &header, Conflict: &conflict})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), report.Overwritten)
+	assert.Equal(t, model.ImportStatusOverwritten, report.Rows[0].Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportFile_InvalidDelimiter_ShouldReturnError(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	delimiter := ";;"
+
+	report, err := dbService.ImportFile(strings.NewReader(""), &model.FileImportOptions{Delimiter: &delimiter})
+
+	assert.Nil(t, report)
+	assert.Equal(t, customerrors.InvalidDelimiterError{Delimiter: ";;"}, err)
+}
+
+func TestExportWords_ShouldReadDictionaryInBatches(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	firstBatch := make([]dbmodels.Word, ExportBatchSize)
+	for i := range firstBatch {
+		firstBatch[i] = dbmodels.Word{Polish: fmt.Sprintf("słowo%03d", i)}
+	}
+
+	mockRepo.On("ListWords", WordsQuery{Limit: ExportBatchSize}, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]dbmodels.Word) = firstBatch
+	})
+	mockRepo.On("ListWords", WordsQuery{After: "słowo099", Limit: ExportBatchSize}, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]dbmodels.Word) = []dbmodels.Word{{Polish: "żaba"}}
+	})
+
+	exported := []string{}
+	err := dbService.ExportWords(ExportFilter{}, func(word *model.Word) error {
+		exported = append(exported, word.Polish)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, exported, ExportBatchSize+1)
+	assert.Equal(t, "żaba", exported[ExportBatchSize])
+	mockRepo.AssertExpectations(t)
+}
+
+func TestExportWords_WithPrefix_ShouldExportOnlyMatchingWords(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	mockRepo.On("ListWords", WordsQuery{Prefix: "ro", Limit: ExportBatchSize}, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]dbmodels.Word) = []dbmodels.Word{{Polish: "rower"}, {Polish: "rozmowa"}}
+	})
+
+	exported := []string{}
+	err := dbService.ExportWords(ExportFilter{Prefix: "ro"}, func(word *model.Word) error {
+		exported = append(exported, word.Polish)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rower", "rozmowa"}, exported)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestExportWords_WithTag_ShouldExportOnlyTaggedWords(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	mockRepo.On("ListWords", WordsQuery{Tag: "travel", Limit: ExportBatchSize}, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]dbmodels.Word) = []dbmodels.Word{{Polish: "bilet"}}
+	})
+
+	exported := []string{}
+	err := dbService.ExportWords(ExportFilter{Tag: "  Travel "}, func(word *model.Word) error {
+		exported = append(exported, word.Polish)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bilet"}, exported)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestExportWords_InPair_ShouldExportWordsOfThatPair(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	mockRepo.On("InPair", LanguagePair{Source: "DE", Target: "EN"}).Return()
+	mockRepo.On("ListWords", WordsQuery{Limit: ExportBatchSize}, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]dbmodels.Word) = []dbmodels.Word{{Polish: "Haus"}}
+	})
+
+	exported := []string{}
+	err := dbService.ExportWords(ExportFilter{Pair: &model.LanguagePair{Source: model.LanguageDe, Target: model.LanguageEn}}, func(word *model.Word) error {
+		exported = append(exported, word.Polish)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Haus"}, exported)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDueCards_ShouldReturnCardsWithReviewState(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	due := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("DueCards", mock.Anything, DefaultDueCardsLimit, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(2).(*[]dbmodels.Card) = []dbmodels.Card{
+			{Polish: "rower", Translation: dbmodels.Translation{ID: 7, English: "bike", Review: &dbmodels.ReviewState{
+				TranslationID: 7, EaseFactor: 2.2, Interval: 6, Repetitions: 2, Due: due,
+			}}},
+			{Polish: "kot", Translation: dbmodels.Translation{ID: 8, English: "cat"}},
+		}
+	})
+
+	cards, err := dbService.DueCards(nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "7", cards[0].TranslationID)
+	assert.Equal(t, int32(6), cards[0].Interval)
+	assert.Equal(t, due, cards[0].Due)
+	assert.Equal(t, "kot", cards[1].Polish)
+	assert.Equal(t, study.InitialEaseFactor, cards[1].EaseFactor)
+	assert.Equal(t, int32(0), cards[1].Repetitions)
+}
+
+func TestDueCards_InvalidLimit_ShouldReturnError(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	limit := int32(0)
+	cards, err := dbService.DueCards(&limit)
+
+	assert.Nil(t, cards)
+	assert.Equal(t, customerrors.InvalidPageSizeError{First: 0, Max: MaxDueCardsLimit}, err)
+}
+
+func TestGradeCard_ShouldSaveNextReview(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("GetCard", uint(7), mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(1).(*dbmodels.Card) = dbmodels.Card{Polish: "rower", Translation: dbmodels.Translation{ID: 7, English: "bike", Review: &dbmodels.ReviewState{
+			TranslationID: 7, EaseFactor: 2.5, Interval: 6, Repetitions: 2,
+		}}}
+	})
+	mockRepo.On("SaveReview", mock.MatchedBy(func(r *dbmodels.ReviewState) bool {
+		return r.TranslationID == 7 && r.Interval == 15 && r.Repetitions == 3
+	})).Return(nil)
+
+	card, err := dbService.GradeCard("7", 4)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(15), card.Interval)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, 15), card.Due, time.Minute)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGradeCard_InvalidGrade_ShouldReturnError(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	card, err := dbService.GradeCard("7", 6)
+
+	assert.Nil(t, card)
+	assert.Equal(t, customerrors.InvalidGradeError{Grade: 6, Min: 0, Max: 5}, err)
+	mockRepo.AssertNotCalled(t, "GetCard", mock.Anything, mock.Anything)
+}
+
+func TestGradeCard_InvalidTranslationID_ShouldReturnCardNotExistsError(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	card, err := dbService.GradeCard("rower", 4)
+
+	assert.Nil(t, card)
+	assert.Equal(t, customerrors.CardNotExistsError{TranslationID: "rower"}, err)
+}
+
+func TestGenerateQuiz_ShouldUseKindsInTurns(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	size := int32(3)
+	kinds := []model.QuestionKind{model.QuestionKindMultipleChoice, model.QuestionKindCloze}
+
+	mockRepo.On("RandomCards", 3, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]dbmodels.Card) = []dbmodels.Card{
+			{Polish: "zamek", Translation: dbmodels.Translation{ID: 1, English: "castle"}},
+			{Polish: "rower", Translation: dbmodels.Translation{ID: 2, English: "bike", Sentences: []dbmodels.Sentence{
+				{ID: 5, Sentence: "The sun is shining"},
+				{ID: 6, Sentence: "Bikes are fast"},
+			}}},
+			{Polish: "kot", Translation: dbmodels.Translation{ID: 3, English: "cat"}},
+		}
+	})
+	mockRepo.On("RandomWords", 3*QuizChoices, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]dbmodels.Word) = []dbmodels.Word{
+			{Polish: "zamek", Translations: []dbmodels.Translation{{English: "castle"}, {English: "lock"}}},
+			{Polish: "pies", Translations: []dbmodels.Translation{{English: "dog"}}},
+		}
+	})
+	mockRepo.On("GetWords", []string{"zamek", "rower", "kot"}, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]dbmodels.Word) = []dbmodels.Word{
+			{Polish: "zamek", Translations: []dbmodels.Translation{{English: "castle"}, {English: "lock"}}},
+		}
+	})
+
+	questions, err := dbService.GenerateQuiz(&size, nil, kinds)
+
+	assert.NoError(t, err)
+	assert.Len(t, questions, 3)
+
+	//other translations of the asked word aren't distractors
+	assert.Equal(t, model.QuestionKindMultipleChoice, questions[0].Kind)
+	assert.Equal(t, "zamek", questions[0].Prompt)
+	assert.ElementsMatch(t, []string{"castle", "dog"}, questions[0].Choices)
+
+	assert.Equal(t, model.QuestionKindCloze, questions[1].Kind)
+	assert.Equal(t, "____ are fast (rower)", questions[1].Prompt)
+
+	assert.Equal(t, model.QuestionKindMultipleChoice, questions[2].Kind)
+	assert.Contains(t, questions[2].Choices, "cat")
+}
+
+func TestGenerateQuiz_ClozeWithoutSentence_ShouldAskTypingQuestion(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	direction := model.QuizDirectionEnPl
+
+	mockRepo.On("RandomCards", DefaultQuizSize, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]dbmodels.Card) = []dbmodels.Card{{Polish: "kot", Translation: dbmodels.Translation{ID: 3, English: "cat"}}}
+	})
+	mockRepo.On("RandomWords", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("GetWords", mock.Anything, mock.Anything).Return(nil)
+
+	questions, err := dbService.GenerateQuiz(nil, &direction, []model.QuestionKind{model.QuestionKindCloze})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.QuestionKindTyping, questions[0].Kind)
+	assert.Equal(t, "cat", questions[0].Prompt)
+	assert.Empty(t, questions[0].Choices)
+}
+
+func TestSubmitQuiz_ShouldAcceptAnyTranslationOfTheWord(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	typing := quizQuestion{kind: model.QuestionKindTyping, direction: model.QuizDirectionPlEn, translationID: 1}.id()
+	cloze := quizQuestion{kind: model.QuestionKindCloze, direction: model.QuizDirectionPlEn, translationID: 2, sentenceID: 6}.id()
+
+	mockRepo.On("GetCard", uint(1), mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(1).(*dbmodels.Card) = dbmodels.Card{Polish: "zamek", Language: "PL", Translation: dbmodels.Translation{ID: 1, Language: "EN", English: "castle"}}
+	})
+	mockRepo.On("InPair", LanguagePair{Source: "PL", Target: "EN"})
+	mockRepo.On("GetWord", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(0).(*dbmodels.Word) = dbmodels.Word{Polish: "zamek", Translations: []dbmodels.Translation{{English: "castle"}, {English: "lock"}}}
+	})
+	mockRepo.On("GetCard", uint(2), mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(1).(*dbmodels.Card) = dbmodels.Card{Polish: "rower", Language: "PL", Translation: dbmodels.Translation{ID: 2, Language: "EN", English: "bike", Sentences: []dbmodels.Sentence{
+			{ID: 6, Sentence: "Bikes are fast"},
+		}}}
+	})
+
+	result, err := dbService.SubmitQuiz([]*model.QuizAnswer{
+		{QuestionID: typing, Answer: " Lock "},
+		{QuestionID: cloze, Answer: "car"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), result.Score)
+	assert.Equal(t, int32(2), result.Total)
+	assert.True(t, result.Answers[0].Correct)
+	assert.False(t, result.Answers[1].Correct)
+	assert.Equal(t, []string{"bike", "Bikes"}, result.Answers[1].AcceptedAnswers)
+}
+
+func TestSubmitQuiz_InvalidQuestionID_ShouldReturnError(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	result, err := dbService.SubmitQuiz([]*model.QuizAnswer{{QuestionID: "abc", Answer: "cat"}})
+
+	assert.Nil(t, result)
+	assert.Equal(t, customerrors.InvalidQuestionError{QuestionID: "abc"}, err)
+}
+
+func TestSetGrammar_WithAspectPartner_ShouldLinkBothWords(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo, events: events.NewBroker()}
+
+	aspect := model.AspectImperfective
+	partner := "zrobić"
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("GetWord", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(0).(*dbmodels.Word) = dbmodels.Word{ID: 1, Polish: "robić"}
+	}).Once()
+	mockRepo.On("GetWord", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(0).(*dbmodels.Word) = dbmodels.Word{ID: 2, Polish: "zrobić"}
+	}).Once()
+	mockRepo.On("UpdateGrammar", mock.MatchedBy(func(w *dbmodels.Word) bool {
+		return w.ID == 2 && *w.AspectPartnerID == 1 && *w.PartOfSpeech == "VERB" && *w.Aspect == "PERFECTIVE"
+	})).Return(nil).Once()
+	mockRepo.On("UpdateGrammar", mock.MatchedBy(func(w *dbmodels.Word) bool {
+		return w.ID == 1 && *w.AspectPartnerID == 2 && *w.Aspect == "IMPERFECTIVE" && w.Gender == nil
+	})).Return(nil).Once()
+	mockRepo.On("GetWord", mock.Anything).Return(nil)
+
+	result, err := dbService.SetGrammar("robić", model.GrammarInput{PartOfSpeech: model.PartOfSpeechVerb, Aspect: &aspect, AspectPartner: &partner})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.MutationOutcomeUpdated, result.Outcome)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSetGrammar_ReplacedAspectPartner_ShouldBeUnlinked(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo, events: events.NewBroker()}
+
+	previousPartner := uint(3)
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("GetWord", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(0).(*dbmodels.Word) = dbmodels.Word{ID: 1, Polish: "robić", AspectPartnerID: &previousPartner, AspectPartner: &dbmodels.Word{ID: 3, Polish: "zrobić"}}
+	}).Once()
+	mockRepo.On("GetWord", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(0).(*dbmodels.Word) = dbmodels.Word{ID: 3, Polish: "zrobić", AspectPartnerID: new(uint)}
+	}).Once()
+	mockRepo.On("UpdateGrammar", mock.MatchedBy(func(w *dbmodels.Word) bool {
+		return w.ID == 3 && w.AspectPartnerID == nil
+	})).Return(nil).Once()
+	mockRepo.On("UpdateGrammar", mock.MatchedBy(func(w *dbmodels.Word) bool {
+		return w.ID == 1 && w.AspectPartnerID == nil && *w.PartOfSpeech == "NOUN"
+	})).Return(nil).Once()
+	mockRepo.On("GetWord", mock.Anything).Return(nil)
+
+	_, err := dbService.SetGrammar("robić", model.GrammarInput{PartOfSpeech: model.PartOfSpeechNoun})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSetGrammar_FieldOfOtherPartOfSpeech_ShouldReturnMismatchError(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	gender := model.GenderFeminine
+	aspect := model.AspectPerfective
+
+	_, err := dbService.SetGrammar("robić", model.GrammarInput{PartOfSpeech: model.PartOfSpeechVerb, Gender: &gender})
+	assert.Equal(t, customerrors.GrammarMismatchError{Field: "gender", PartOfSpeech: "VERB"}, err)
+
+	_, err = dbService.SetGrammar("kot", model.GrammarInput{PartOfSpeech: model.PartOfSpeechNoun, Aspect: &aspect})
+	assert.Equal(t, customerrors.GrammarMismatchError{Field: "aspect", PartOfSpeech: "NOUN"}, err)
+
+	mockRepo.AssertNotCalled(t, "WithTransaction", mock.Anything)
+}
+
+func TestSetGrammar_PartnerOfTheSameAspect_ShouldReturnError(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	aspect := model.AspectPerfective
+	partner := "zrobić"
+	partnerAspect := "PERFECTIVE"
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(false)
+	mockRepo.On("GetWord", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(0).(*dbmodels.Word) = dbmodels.Word{ID: 1, Polish: "zjeść"}
+	}).Once()
+	mockRepo.On("GetWord", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(0).(*dbmodels.Word) = dbmodels.Word{ID: 2, Polish: "zrobić", Aspect: &partnerAspect}
+	}).Once()
+
+	result, err := dbService.SetGrammar("zjeść", model.GrammarInput{PartOfSpeech: model.PartOfSpeechVerb, Aspect: &aspect, AspectPartner: &partner})
+
+	assert.Nil(t, result)
+	assert.Equal(t, customerrors.InvalidAspectPartnerError{Word: "zjeść", Partner: "zrobić"}, err)
+	mockRepo.AssertNotCalled(t, "UpdateGrammar", mock.Anything)
+}
+
+func TestSetCountability_ShouldUpdateTranslation(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo, events: events.NewBroker()}
+
+	countability := model.CountabilityUncountable
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("GetTranslation", "rada", "advice", mock.Anything).Return(nil)
+	mockRepo.On("UpdateCountability", mock.Anything, mock.MatchedBy(func(c *string) bool { return *c == "UNCOUNTABLE" })).Return(nil)
+	mockRepo.On("GetWord", mock.Anything).Return(nil)
+
+	result, err := dbService.SetCountability("rada", "advice", &countability)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.MutationOutcomeUpdated, result.Outcome)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListWords_GrammarFilter_ShouldBePassedToRepository(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	partOfSpeech := model.PartOfSpeechNoun
+	gender := model.GenderNeuter
+
+	mockRepo.On("ListWords", WordsQuery{Limit: DefaultPageSize + 1, Grammar: GrammarFilter{PartOfSpeech: "NOUN", Gender: "NEUTER"}}, mock.Anything).Return(nil)
+
+	_, err := dbService.ListWords(nil, nil, nil, nil, &model.GrammarFilter{PartOfSpeech: &partOfSpeech, Gender: &gender}, nil)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListWords_TagFilter_ShouldBeNormalized(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	tag := " Lesson  5 "
+
+	mockRepo.On("ListWords", WordsQuery{Limit: DefaultPageSize + 1, Tag: "lesson 5"}, mock.Anything).Return(nil)
+
+	_, err := dbService.ListWords(nil, nil, nil, nil, nil, &tag)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSelectWord_InflectedForm_ShouldReturnLemmaWithMatchedForms(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	polish := "kota"
+
+	mockRepo.On("GetWord", mock.Anything, mock.Anything).Return(customerrors.WordNotExistsError{Word: polish})
+	mockRepo.On("GetWordByForm", polish, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(1).(*dbmodels.Word) = dbmodels.Word{Polish: "kot", Inflections: []dbmodels.Inflection{
+			{Form: "kota", Tags: "genitive singular"},
+			{Form: "kotem", Tags: "instrumental singular"},
+			{Form: "kota", Tags: "accusative singular"},
+		}}
+	})
+
+	retWord, err := dbService.SelectWord(polish)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "kot", retWord.Polish)
+	assert.Len(t, retWord.Inflections, 3)
+	assert.Equal(t, []*model.Inflection{
+		{Form: "kota", Tags: []string{"genitive", "singular"}},
+		{Form: "kota", Tags: []string{"accusative", "singular"}},
+	}, retWord.MatchedForms)
+	mockRepo.AssertNotCalled(t, "GetWordIgnoringDiacritics", mock.Anything, mock.Anything)
+}
+
+func TestAddInflection_ShouldNormalizeTags(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo, events: events.NewBroker()}
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("GetWord", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(0).(*dbmodels.Word) = dbmodels.Word{ID: 4, Polish: "rower"}
+	})
+	mockRepo.On("AddInflection", &dbmodels.Inflection{WordID: 4, Form: "rowerem", Tags: "instrumental singular"}).Return(nil)
+
+	result, err := dbService.AddInflection("rower", " rowerem", []string{"Instrumental", " singular "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.MutationOutcomeUpdated, result.Outcome)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAddInflection_FormExists_ShouldReturnErrorWithWord(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(false)
+	mockRepo.On("GetWord", mock.Anything).Return(nil)
+	mockRepo.On("AddInflection", mock.Anything).Return(customerrors.InflectionExistsError{Form: "rowerem", Tags: "instrumental singular"})
+
+	result, err := dbService.AddInflection("rower", "rowerem", []string{"instrumental", "singular"})
+
+	assert.Nil(t, result)
+	assert.Equal(t, customerrors.InflectionExistsError{Word: "rower", Form: "rowerem", Tags: "instrumental singular"}, err)
+}
+
+func TestDeleteInflection_WithoutTags_ShouldDeleteEveryEntryOfTheForm(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo, events: events.NewBroker()}
+
+	mockRepo.On("DeleteInflection", "kot", "kota", (*string)(nil)).Return(nil)
+	mockRepo.On("GetWord", mock.Anything).Return(nil)
+
+	result, err := dbService.DeleteInflection("kot", "kota", nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.MutationOutcomeDeleted, result.Outcome)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportInflections_BestEffort_ShouldSkipKnownFormsAndReportFailures(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo, events: events.NewBroker()}
+
+	mode := model.ImportModeBestEffort
+	entries := []*model.NewInflection{
+		{Lemma: "rower", Form: "rowerem", Tags: []string{"instrumental", "singular"}},
+		{Lemma: "rower", Form: "roweru", Tags: []string{"genitive", "singular"}},
+		{Lemma: "rower", Form: "", Tags: []string{}},
+		{Lemma: "samochód", Form: "samochodem", Tags: []string{"instrumental", "singular"}},
+		{Lemma: "rower", Form: "roweru", Tags: []string{"Genitive singular"}},
+	}
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("GetWords", []string{"rower", "rower", "samochód", "rower"}, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]dbmodels.Word) = []dbmodels.Word{
+			{ID: 1, Polish: "rower", Inflections: []dbmodels.Inflection{{WordID: 1, Form: "rowerem", Tags: "instrumental singular"}}},
+		}
+	})
+	mockRepo.On("AddInflections", []dbmodels.Inflection{{WordID: 1, Form: "roweru", Tags: "genitive singular"}}).Return(nil)
+
+	results, err := dbService.ImportInflections(entries, &mode)
+
+	assert.NoError(t, err)
+	statuses := []model.ImportStatus{}
+	for _, r := range results {
+		statuses = append(statuses, r.Status)
+	}
+	assert.Equal(t, []model.ImportStatus{
+		model.ImportStatusSkipped, model.ImportStatusCreated, model.ImportStatusFailed, model.ImportStatusFailed, model.ImportStatusSkipped,
+	}, statuses)
+	assert.Equal(t, customerrors.CodeInvalidEntry, *results[2].ErrorCode)
+	assert.Equal(t, customerrors.CodeWordNotFound, *results[3].ErrorCode)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportInflections_Atomic_ShouldFailOnFirstInvalidEntry(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(false)
+	mockRepo.On("GetWords", mock.Anything, mock.Anything).Return(nil)
+
+	results, err := dbService.ImportInflections([]*model.NewInflection{{Lemma: "rower", Form: "rowerem", Tags: []string{}}}, nil)
+
+	assert.Nil(t, results)
+	assert.Equal(t, customerrors.ImportFailedError{Index: 0, Reason: customerrors.CodeWordNotFound}, err)
+	mockRepo.AssertNotCalled(t, "AddInflections", mock.Anything)
+}
+
+func TestAddRelation_Synonym_ShouldLinkWordsSymmetrically(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo, events: events.NewBroker()}
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("GetWord", mock.Anything).Return(nil).Once().Run(func(args mock.Arguments) {
+		*args.Get(0).(*dbmodels.Word) = dbmodels.Word{ID: 1, Polish: "rower"}
+	})
+	mockRepo.On("GetWord", mock.Anything).Return(nil).Once().Run(func(args mock.Arguments) {
+		*args.Get(0).(*dbmodels.Word) = dbmodels.Word{ID: 2, Polish: "bicykl"}
+	})
+	mockRepo.On("GetWord", mock.Anything).Return(nil)
+	mockRepo.On("AddWordRelation", &dbmodels.WordRelation{Kind: "SYNONYM", WordID: 1, RelatedID: 2}, true).Return(nil)
+
+	result, err := dbService.AddRelation(model.RelationKindSynonym, model.RelationEnd{Text: "rower"}, model.RelationEnd{Text: "bicykl"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.MutationOutcomeUpdated, result.Outcome)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAddRelation_WordWithTranslation_ShouldReturnError(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	translation := "bike"
+	result, err := dbService.AddRelation(model.RelationKindSeeAlso, model.RelationEnd{Text: "rower"}, model.RelationEnd{Text: "rower", Translation: &translation})
+
+	assert.Nil(t, result)
+	assert.Equal(t, customerrors.InvalidRelationError{From: "rower", To: "rower (bike)"}, err)
+	mockRepo.AssertNotCalled(t, "WithTransaction", mock.Anything)
+}
+
+func TestAddRelation_TranslationsRelated_ShouldReturnErrorWithEnds(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	bicycle, bike := "bicycle", "bike"
+	mockRepo.On("WithTransaction", mock.Anything).Return(false)
+	mockRepo.On("GetTranslation", "rower", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("AddTranslationRelation", mock.Anything, true).Return(customerrors.RelationExistsError{Kind: "SYNONYM"})
+
+	result, err := dbService.AddRelation(model.RelationKindSynonym, model.RelationEnd{Text: "rower", Translation: &bicycle}, model.RelationEnd{Text: "rower", Translation: &bike})
+
+	assert.Nil(t, result)
+	assert.Equal(t, customerrors.RelationExistsError{Kind: "SYNONYM", From: "rower (bicycle)", To: "rower (bike)"}, err)
+}
+
+func TestRemoveRelation_DerivedFrom_ShouldDeleteEdgeInGivenDirectionOnly(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo, events: events.NewBroker()}
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("GetWord", mock.Anything).Return(nil).Once().Run(func(args mock.Arguments) {
+		*args.Get(0).(*dbmodels.Word) = dbmodels.Word{ID: 3, Polish: "rowerzysta"}
+	})
+	mockRepo.On("GetWord", mock.Anything).Return(nil).Once().Run(func(args mock.Arguments) {
+		*args.Get(0).(*dbmodels.Word) = dbmodels.Word{ID: 1, Polish: "rower"}
+	})
+	mockRepo.On("GetWord", mock.Anything).Return(nil)
+	mockRepo.On("DeleteWordRelation", "DERIVED_FROM", uint(3), uint(1), false).Return(nil)
+
+	result, err := dbService.RemoveRelation(model.RelationKindDerivedFrom, model.RelationEnd{Text: "rowerzysta"}, model.RelationEnd{Text: "rower"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.MutationOutcomeDeleted, result.Outcome)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDBWordToGQLWord_ShouldListRelationsOfBothEnds(t *testing.T) {
+	bike := "bike"
+	word := &dbmodels.Word{
+		Polish:    "rower",
+		Relations: []dbmodels.WordRelation{{Kind: "SYNONYM", Related: &dbmodels.Word{Polish: "bicykl"}}},
+		RelatedBy: []dbmodels.WordRelation{{Kind: "DERIVED_FROM", Word: &dbmodels.Word{Polish: "rowerzysta"}}},
+		Translations: []dbmodels.Translation{{
+			English: "bicycle",
+			Relations: []dbmodels.TranslationRelation{{
+				Kind:    "SYNONYM",
+				Related: &dbmodels.RelatedTranslation{English: "bike", Word: &dbmodels.Word{Polish: "rower"}},
+			}},
+		}},
+	}
+
+	result := dbmodels.DBWordToGQLWord(word)
+
+	assert.Equal(t, []*model.Relation{
+		{Kind: model.RelationKindSynonym, Word: "bicykl"},
+		{Kind: model.RelationKindDerivedFrom, Word: "rowerzysta", Incoming: true},
+	}, result.Related)
+	assert.Equal(t, []*model.Relation{{Kind: model.RelationKindSynonym, Word: "rower", Translation: &bike}}, result.Translations[0].Related)
+}
+
+func TestTagWord_ShouldAddOnlyTagsTheWordDoesntHave(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo, events: events.NewBroker()}
+
+	kitchen, travel := dbmodels.Tag{ID: 1, Name: "kitchen"}, dbmodels.Tag{ID: 2, Name: "travel"}
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("GetWord", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(0).(*dbmodels.Word) = dbmodels.Word{ID: 4, Polish: "garnek", Tags: []dbmodels.Tag{kitchen}}
+	})
+	mockRepo.On("GetOrCreateTags", []string{"kitchen", "travel"}, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]dbmodels.Tag) = []dbmodels.Tag{kitchen, travel}
+	})
+	mockRepo.On("TagWord", mock.Anything, []dbmodels.Tag{travel}).Return(nil)
+
+	result, err := dbService.TagWord("garnek", []string{"Kitchen", "travel", "kitchen"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.MutationOutcomeUpdated, result.Outcome)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTagWord_EmptyTag_ShouldReturnError(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	result, err := dbService.TagWord("garnek", []string{"kitchen", "  "})
+
+	assert.Nil(t, result)
+	assert.Equal(t, customerrors.InvalidNameError{Name: "  "}, err)
+	mockRepo.AssertNotCalled(t, "WithTransaction", mock.Anything)
+}
+
+func TestUntagWord_TagNotOnWord_ShouldBeNoop(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo, events: events.NewBroker()}
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("GetWord", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(0).(*dbmodels.Word) = dbmodels.Word{ID: 4, Polish: "garnek", Tags: []dbmodels.Tag{{ID: 1, Name: "kitchen"}}}
+	})
+
+	result, err := dbService.UntagWord("garnek", []string{"travel"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.MutationOutcomeNoop, result.Outcome)
+	mockRepo.AssertNotCalled(t, "UntagWord", mock.Anything, mock.Anything)
+}
+
+func TestRenameTag_ShouldNormalizeNames(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	mockRepo.On("GetTag", "lesson 5", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(1).(*dbmodels.TagCount) = dbmodels.TagCount{ID: 3, Name: "lesson 5", Words: 12}
+	})
+	mockRepo.On("RenameTag", uint(3), "lesson 6").Return(nil)
+
+	tag, err := dbService.RenameTag("Lesson 5", " Lesson 6")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &model.Tag{Name: "lesson 6", Words: 12}, tag)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAddToCollection_TranslationInCollection_ShouldReturnErrorWithItem(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(false)
+	mockRepo.On("GetCollection", "Lesson 5", mock.Anything).Return(nil)
+	mockRepo.On("GetTranslation", "garnek", "pot", mock.Anything).Return(nil)
+	mockRepo.On("AddCollectionItem", mock.Anything).Return(customerrors.CollectionItemExistsError{})
+
+	result, err := dbService.AddToCollection(" Lesson 5", "garnek", "pot")
+
+	assert.Nil(t, result)
+	assert.Equal(t, customerrors.CollectionItemExistsError{Collection: "Lesson 5", Word: "garnek", Translation: "pot"}, err)
+}
+
+func TestDBCollectionToGQLCollection_ShouldNumberItemsFromOne(t *testing.T) {
+	collection := &dbmodels.Collection{
+		Name: "Lesson 5",
+		Items: []dbmodels.CollectionItem{
+			{Position: 2, Translation: &dbmodels.RelatedTranslation{Language: "EN", English: "pot", Word: &dbmodels.Word{Polish: "garnek", Language: "PL"}}},
+			{Position: 5, Translation: &dbmodels.RelatedTranslation{Language: "EN", English: "Kitchen", Word: &dbmodels.Word{Polish: "Küche", Language: "DE"}}},
+		},
+	}
+
+	result := dbmodels.DBCollectionToGQLCollection(collection)
+
+	assert.Equal(t, &model.Collection{Name: "Lesson 5", Items: []*model.CollectionItem{
+		{Position: 1, Text: "garnek", Language: model.LanguagePl, Translation: "pot", TranslationLanguage: model.LanguageEn},
+		{Position: 2, Text: "Küche", Language: model.LanguageDe, Translation: "Kitchen", TranslationLanguage: model.LanguageEn},
+	}}, result)
+}
+
+// Audio store remembering clips in memory
+type fakeAudioStore struct {
+	saved   map[string]string
+	deleted []string
+}
+
+func (s *fakeAudioStore) Save(name string, r io.Reader) error {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if s.saved == nil {
+		s.saved = map[string]string{}
+	}
+	s.saved[name] = string(content)
+	return nil
+}
+
+func (s *fakeAudioStore) Delete(name string) error {
+	delete(s.saved, name)
+	s.deleted = append(s.deleted, name)
+	return nil
+}
+
+func TestSetPronunciation_ShouldStripIPADelimiters(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo, events: events.NewBroker()}
+
+	clip := "a1.mp3"
+	ipa, stress := " /ˈrɔ.vɛr/ ", int32(1)
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("GetWord", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(0).(*dbmodels.Word) = dbmodels.Word{Polish: "rower", Pronunciation: dbmodels.Pronunciation{Audio: &clip}}
+	})
+	mockRepo.On("UpdatePronunciation", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		pronunciation := args.Get(1).(*dbmodels.Pronunciation)
+		assert.Equal(t, "ˈrɔ.vɛr", *pronunciation.IPA)
+		assert.Equal(t, 1, *pronunciation.Stress)
+		assert.Equal(t, &clip, pronunciation.Audio)
+	})
+
+	result, err := dbService.SetPronunciation("rower", nil, model.PronunciationInput{Ipa: &ipa, Stress: &stress})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.MutationOutcomeUpdated, result.Outcome)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSetPronunciation_StressBelowOne_ShouldReturnError(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	stress := int32(0)
+
+	result, err := dbService.SetPronunciation("rower", nil, model.PronunciationInput{Stress: &stress})
+
+	assert.Nil(t, result)
+	assert.Equal(t, customerrors.InvalidStressError{Stress: 0}, err)
+	mockRepo.AssertNotCalled(t, "WithTransaction", mock.Anything)
+}
+
+func TestUploadAudio_ShouldReplacePreviousClip(t *testing.T) {
+	mockRepo := new(MockRepository)
+	clips := &fakeAudioStore{}
+	dbService := &DictionaryService{repository: mockRepo, events: events.NewBroker(), audio: clips}
+
+	previous := "old.ogg"
+	translation := "bike"
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("GetTranslation", "rower", "bike", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(2).(*dbmodels.Translation) = dbmodels.Translation{English: "bike", Pronunciation: dbmodels.Pronunciation{Audio: &previous}}
+	})
+	mockRepo.On("UpdatePronunciation", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("GetWord", mock.Anything).Return(nil)
+
+	_, err := dbService.UploadAudio("rower", &translation, "Bike.MP3", strings.NewReader("clip"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{previous}, clips.deleted)
+	assert.Len(t, clips.saved, 1)
+	for name, content := range clips.saved {
+		assert.True(t, strings.HasSuffix(name, ".mp3"))
+		assert.Equal(t, "clip", content)
+	}
+}
+
+func TestUploadAudio_WordDoesntExist_ShouldDeleteSavedClip(t *testing.T) {
+	mockRepo := new(MockRepository)
+	clips := &fakeAudioStore{}
+	dbService := &DictionaryService{repository: mockRepo, audio: clips}
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(false)
+	mockRepo.On("GetWord", mock.Anything).Return(customerrors.WordNotExistsError{Word: "rower"})
+
+	result, err := dbService.UploadAudio("rower", nil, "rower.wav", strings.NewReader("clip"))
+
+	assert.Nil(t, result)
+	assert.Equal(t, customerrors.WordNotExistsError{Word: "rower"}, err)
+	assert.Empty(t, clips.saved)
+	assert.Len(t, clips.deleted, 1)
+}
+
+func TestUploadAudio_UnsupportedFile_ShouldReturnError(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo, audio: &fakeAudioStore{}}
+
+	result, err := dbService.UploadAudio("rower", nil, "rower.txt", strings.NewReader("clip"))
+
+	assert.Nil(t, result)
+	assert.Equal(t, customerrors.UnsupportedAudioError{File: "rower.txt"}, err)
+	mockRepo.AssertNotCalled(t, "WithTransaction", mock.Anything)
+}
+
+func TestCreateWordOrAddTranslationOrSentence_WithUsage_ShouldStoreNormalizedUsage(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	domain, note := " Informal  Speech ", "  mostly British "
+	translation := model.NewTranslation{
+		English:   "bloke",
+		Sentences: []string{},
+		Labels:    []model.UsageLabel{model.UsageLabelRegional, model.UsageLabelColloquial, model.UsageLabelRegional},
+		Domain:    &domain,
+		Note:      &note,
+	}
+
+	mockRepo.On("GetWord", mock.Anything, mock.Anything).Return(customerrors.WordNotExistsError{Word: "facet"})
+	mockRepo.On("WithTransaction", mock.Anything).Return(true)
+	mockRepo.On("AddWord", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		usage := args.Get(0).(*dbmodels.Word).Translations[0].Usage
+		assert.Equal(t, "COLLOQUIAL REGIONAL", usage.Labels)
+		assert.Equal(t, "informal speech", *usage.Domain)
+		assert.Equal(t, "mostly British", *usage.Note)
+	})
+
+	result, err := dbService.CreateWordOrAddTranslationOrSentence("facet", translation)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.MutationOutcomeCreated, result.Outcome)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSetUsage_EmptyDomainAndNote_ShouldClearThem(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo, events: events.NewBroker()}
+
+	blank := "  "
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("GetTranslation", "facet", "bloke", mock.Anything).Return(nil)
+	mockRepo.On("UpdateUsage", mock.Anything, &dbmodels.Usage{Labels: "FORMAL"}).Return(nil)
+	mockRepo.On("GetWord", mock.Anything).Return(nil)
+
+	result, err := dbService.SetUsage("facet", "bloke", model.UsageInput{Labels: []model.UsageLabel{model.UsageLabelFormal}, Domain: &blank, Note: &blank})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.MutationOutcomeUpdated, result.Outcome)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListWords_UsageFilter_ShouldBePassedToRepository(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	label, domain := model.UsageLabelArchaic, " Legal "
+
+	mockRepo.On("ListWords", WordsQuery{Limit: DefaultPageSize + 1, Grammar: GrammarFilter{Label: "ARCHAIC", Domain: "legal"}}, mock.Anything).Return(nil)
+
+	_, err := dbService.ListWords(nil, nil, nil, nil, &model.GrammarFilter{Label: &label, Domain: &domain}, nil)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGrammarFilterConditions_Label_ShouldMatchWholeLabels(t *testing.T) {
+	conditions, args := GrammarFilter{Label: "FORMAL", Domain: "legal"}.conditions("w", "")
+
+	assert.Equal(t, []string{
+		"EXISTS (SELECT 1 FROM translations c WHERE c.word_id = w.id AND ' ' || c.labels || ' ' LIKE ?)",
+		"EXISTS (SELECT 1 FROM translations c WHERE c.word_id = w.id AND c.domain = ?)",
+	}, conditions)
+	assert.Equal(t, []interface{}{"% FORMAL %", "legal"}, args)
+}
+
+func TestCreateWordOrAddTranslationOrSentence_SentencePairs_ShouldBeMergedWithSentences(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	original, attribution, blank := " Jeżdżę na rowerze. ", "Tatoeba", ""
+	translation := model.NewTranslation{
+		English:   "bike",
+		Sentences: []string{"I ride a bike", "I like my bike"},
+		SentencePairs: []*model.SentenceInput{
+			{Sentence: "I ride a bike", Original: &original},
+			{Sentence: "My bike is green", Original: &original, Attribution: &attribution, License: &blank},
+		},
+	}
+
+	mockRepo.On("GetWord", mock.Anything, mock.Anything).Return(customerrors.WordNotExistsError{Word: "rower"})
+	mockRepo.On("WithTransaction", mock.Anything).Return(true)
+	mockRepo.On("AddWord", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		original := "Jeżdżę na rowerze."
+		assert.Equal(t, []dbmodels.Sentence{
+			{Sentence: "I ride a bike"},
+			{Sentence: "I like my bike"},
+			{Sentence: "My bike is green", Original: &original, Attribution: &attribution},
+		}, args.Get(0).(*dbmodels.Word).Translations[0].Sentences)
+	})
+
+	_, err := dbService.CreateWordOrAddTranslationOrSentence("rower", translation)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateSentence_NullFieldsShouldBeKeptAndEmptyOnesCleared(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo, events: events.NewBroker()}
+
+	original, attribution, license := "Lubię mój rower.", "Tatoeba", "CC BY 2.0 FR"
+	blank := " "
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("GetSentence", "rower", "bike", "Lubię mój rower.", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(3).(*dbmodels.Sentence) = dbmodels.Sentence{Sentence: "I like my bike", Original: &original, Attribution: &attribution, License: &license}
+	})
+	mockRepo.On("UpdateSentence", mock.Anything, dbmodels.Sentence{Sentence: "I love my bike", Original: &original, License: &license}).Return(nil)
+	mockRepo.On("GetWord", mock.Anything).Return(nil)
+
+	result, err := dbService.UpdateSentence("rower", "bike", "Lubię mój rower.", model.SentenceInput{Sentence: "I love my bike", Attribution: &blank})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.MutationOutcomeUpdated, result.Outcome)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportWords_EmptySentenceOfPair_ShouldFailEntry(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	mode := model.ImportModeAtomic
+	entries := []*model.NewWordEntry{
+		{Polish: "rower", Translation: &model.NewTranslation{English: "bike", Sentences: []string{}, SentencePairs: []*model.SentenceInput{{Sentence: " "}}}},
+	}
+
+	_, err := dbService.ImportWords(entries, &mode)
+
+	assert.Equal(t, customerrors.InvalidEntryError{Index: 0, Field: "sentencePairs"}, err)
+}
+
+func TestNewOrder_ShouldPutListedItemsFirst(t *testing.T) {
+	names := [][]string{{"bike"}, {"bicycle"}, {"cycle"}}
+
+	order, missing := newOrder([]uint{1, 2, 3}, names, []string{"cycle", "bike", "cycle"})
+	assert.Nil(t, missing)
+	assert.Equal(t, []uint{3, 1, 2}, order)
+
+	_, missing = newOrder([]uint{1, 2, 3}, names, []string{"velocipede"})
+	assert.Equal(t, "velocipede", *missing)
+}
+
+func TestReorderTranslations_ShouldUpdatePositions(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo, events: events.NewBroker()}
+
+	word := dbmodels.Word{Polish: "zamek", Translations: []dbmodels.Translation{{ID: 1, English: "zipper"}, {ID: 2, English: "lock"}, {ID: 3, English: "castle"}}}
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("GetWord", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(0).(*dbmodels.Word) = word
+	})
+	mockRepo.On("UpdatePositions", &dbmodels.Translation{}, []uint{3, 2, 1}).Return(nil)
+
+	result, err := dbService.ReorderTranslations("zamek", []string{"castle", "lock"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.MutationOutcomeUpdated, result.Outcome)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestReorderTranslations_SameOrder_ShouldReturnNoop(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("GetWord", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(0).(*dbmodels.Word) = dbmodels.Word{Polish: "zamek", Translations: []dbmodels.Translation{{ID: 1, English: "castle"}, {ID: 2, English: "lock"}}}
+	})
+
+	result, err := dbService.ReorderTranslations("zamek", []string{"castle"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.MutationOutcomeNoop, result.Outcome)
+	mockRepo.AssertNotCalled(t, "UpdatePositions", mock.Anything, mock.Anything)
+}
+
+func TestReorderTranslations_UnknownTranslation_ShouldReturnError(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo}
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(false, nil)
+	mockRepo.On("GetWord", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(0).(*dbmodels.Word) = dbmodels.Word{Polish: "zamek", Translations: []dbmodels.Translation{{ID: 1, English: "castle"}}}
+	})
+
+	_, err := dbService.ReorderTranslations("zamek", []string{"palace"})
+
+	assert.Equal(t, customerrors.TranslationNotExistsError{Word: "zamek", Translation: "palace"}, err)
+}
+
+func TestReorderSentences_PairsShouldBeListedByOriginal(t *testing.T) {
+	mockRepo := new(MockRepository)
+	dbService := &DictionaryService{repository: mockRepo, events: events.NewBroker()}
+
+	original := "Zamek stoi na wzgórzu."
+	word := dbmodels.Word{Polish: "zamek", Translations: []dbmodels.Translation{{ID: 1, English: "castle", Sentences: []dbmodels.Sentence{
+		{ID: 10, Sentence: "The castle is old."},
+		{ID: 11, Sentence: "The castle stands on a hill.", Original: &original},
+	}}}}
+
+	mockRepo.On("WithTransaction", mock.Anything).Return(true, nil)
+	mockRepo.On("GetWord", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(0).(*dbmodels.Word) = word
+	})
+	mockRepo.On("UpdatePositions", &dbmodels.Sentence{}, []uint{11, 10}).Return(nil)
+
+	result, err := dbService.ReorderSentences("zamek", "castle", []string{original})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.MutationOutcomeUpdated, result.Outcome)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,42 @@
+package database
+
+import (
+	dbmodels "github.com/staszkiet/DictionaryGolang/server/database/models"
+	"github.com/staszkiet/DictionaryGolang/server/graph/model"
+)
+
+// Returns the sentences and sentence pairs of a translation as new sentences, a sentence given more than once is kept
+// the first time
+func exampleSentences(translation model.NewTranslation) []dbmodels.Sentence {
+	sentences := []dbmodels.Sentence{}
+	seen := map[string]bool{}
+
+	add := func(s dbmodels.Sentence) {
+		if !seen[s.Sentence] {
+			seen[s.Sentence] = true
+			sentences = append(sentences, s)
+		}
+	}
+
+	for _, s := range translation.Sentences {
+		add(dbmodels.Sentence{Sentence: s})
+	}
+	for _, p := range translation.SentencePairs {
+		add(dbmodels.Sentence{Sentence: p.Sentence, Original: optionalText(p.Original), Attribution: optionalText(p.Attribution), License: optionalText(p.License)})
+	}
+	return sentences
+}
+
+// Applies an edit to a sentence. Null original, attribution and license are kept, empty ones clear the field
+func editedSentence(s dbmodels.Sentence, edit model.SentenceInput) dbmodels.Sentence {
+	s.Sentence = edit.Sentence
+	for _, f := range []struct {
+		field **string
+		value *string
+	}{{&s.Original, edit.Original}, {&s.Attribution, edit.Attribution}, {&s.License, edit.License}} {
+		if f.value != nil {
+			*f.field = optionalText(f.value)
+		}
+	}
+	return s
+}
@@ -0,0 +1,188 @@
+package database
+
+import (
+	"strings"
+
+	dbmodels "github.com/staszkiet/DictionaryGolang/server/database/models"
+	customerrors "github.com/staszkiet/DictionaryGolang/server/errors"
+	"github.com/staszkiet/DictionaryGolang/server/graph/model"
+)
+
+// Tag names are lowercase with single spaces, so "Lesson  5" and "lesson 5" are the same tag
+func tagName(name string) (string, error) {
+	normalized := strings.ToLower(strings.Join(strings.Fields(name), " "))
+	if normalized == "" {
+		return "", customerrors.InvalidNameError{Name: name}
+	}
+	return normalized, nil
+}
+
+// Converts the tag argument of list and search queries into the tag their words must have, empty for every word
+func tagFilter(tag *string) string {
+	if tag == nil {
+		return ""
+	}
+	return strings.ToLower(strings.Join(strings.Fields(*tag), " "))
+}
+
+// Normalizes tag names and leaves out the repeated ones
+func tagNames(names []string) ([]string, error) {
+	normalized := []string{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		tag, err := tagName(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, nil
+}
+
+// Returns the tags the word doesn't have yet
+func newTags(word *dbmodels.Word, tags []dbmodels.Tag) []dbmodels.Tag {
+	has := map[uint]bool{}
+	for _, t := range word.Tags {
+		has[t.ID] = true
+	}
+
+	added := []dbmodels.Tag{}
+	for _, t := range tags {
+		if !has[t.ID] {
+			added = append(added, t)
+		}
+	}
+	return added
+}
+
+// Tags a word, creating the tags which don't exist yet. Tags the word already has are left as they are
+func (r *DictionaryService) TagWord(text string, tags []string) (*model.MutationResult, error) {
+	names, err := tagNames(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	outcome := model.MutationOutcomeNoop
+
+	_, err = r.repository.WithTransaction(func(txRepo IRepository) error {
+		var word dbmodels.Word
+		if err := txRepo.GetWord(text, &word); err != nil {
+			return err
+		}
+
+		var found []dbmodels.Tag
+		if err := txRepo.GetOrCreateTags(names, &found); err != nil {
+			return err
+		}
+
+		added := newTags(&word, found)
+		if len(added) == 0 {
+			return nil
+		}
+		outcome = model.MutationOutcomeUpdated
+		return txRepo.TagWord(&word, added)
+	}, false, false)
+
+	if err != nil {
+		return nil, err
+	}
+	return r.mutationResult(text, nil, outcome)
+}
+
+// Takes tags off a word. Tags the word doesn't have are ignored, tags stay in the dictionary even when no word has them
+func (r *DictionaryService) UntagWord(text string, tags []string) (*model.MutationResult, error) {
+	names, err := tagNames(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	outcome := model.MutationOutcomeNoop
+
+	_, err = r.repository.WithTransaction(func(txRepo IRepository) error {
+		var word dbmodels.Word
+		if err := txRepo.GetWord(text, &word); err != nil {
+			return err
+		}
+
+		removed := []dbmodels.Tag{}
+		for _, t := range word.Tags {
+			for _, name := range names {
+				if t.Name == name {
+					removed = append(removed, t)
+				}
+			}
+		}
+		if len(removed) == 0 {
+			return nil
+		}
+		outcome = model.MutationOutcomeUpdated
+		return txRepo.UntagWord(&word, removed)
+	}, false, false)
+
+	if err != nil {
+		return nil, err
+	}
+	return r.mutationResult(text, nil, outcome)
+}
+
+// Returns every tag with the number of its words
+func (r *DictionaryService) Tags() ([]*model.Tag, error) {
+	var tags []dbmodels.TagCount
+	if err := r.repository.ListTags(&tags); err != nil {
+		return nil, err
+	}
+
+	result := []*model.Tag{}
+	for _, t := range tags {
+		result = append(result, dbmodels.DBTagCountToGQLTag(&t))
+	}
+	return result, nil
+}
+
+// Renames a tag on every word. Renaming to the name of another tag fails, tags aren't merged
+func (r *DictionaryService) RenameTag(name string, newName string) (*model.Tag, error) {
+	current, err := tagName(name)
+	if err != nil {
+		return nil, err
+	}
+	normalized, err := tagName(newName)
+	if err != nil {
+		return nil, err
+	}
+
+	var tag dbmodels.TagCount
+	if err := r.repository.GetTag(current, &tag); err != nil {
+		return nil, err
+	}
+
+	if err := r.repository.RenameTag(tag.ID, normalized); err != nil {
+		return nil, err
+	}
+	tag.Name = normalized
+	return dbmodels.DBTagCountToGQLTag(&tag), nil
+}
+
+// Deletes a tag and takes it off its words. Returns the tag as it was before
+func (r *DictionaryService) DeleteTag(name string) (*model.Tag, error) {
+	current, err := tagName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var tag dbmodels.TagCount
+
+	_, err = r.repository.WithTransaction(func(txRepo IRepository) error {
+		if err := txRepo.GetTag(current, &tag); err != nil {
+			return err
+		}
+		return txRepo.DeleteTag(tag.ID)
+	}, false, false)
+
+	if err != nil {
+		return nil, err
+	}
+	return dbmodels.DBTagCountToGQLTag(&tag), nil
+}
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"strings"
+
+	dbmodels "github.com/staszkiet/DictionaryGolang/server/database/models"
+	"github.com/staszkiet/DictionaryGolang/server/graph/model"
+)
+
+// Domains are lowercase with single spaces like tags, empty ones are left out
+func usageDomain(domain *string) *string {
+	if domain == nil {
+		return nil
+	}
+	normalized := strings.ToLower(strings.Join(strings.Fields(*domain), " "))
+	if normalized == "" {
+		return nil
+	}
+	return &normalized
+}
+
+// Free text such as notes keeps its case, only surrounding spaces are removed and empty text is left out
+func optionalText(text *string) *string {
+	if text == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*text)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// Converts usage given through the API into stored usage. Labels are kept once each, in the order of the UsageLabel enum
+func usageOf(labels []model.UsageLabel, domain *string, note *string) dbmodels.Usage {
+	given := map[model.UsageLabel]bool{}
+	for _, l := range labels {
+		given[l] = true
+	}
+
+	stored := []string{}
+	for _, l := range model.AllUsageLabel {
+		if given[l] {
+			stored = append(stored, string(l))
+		}
+	}
+
+	return dbmodels.Usage{Labels: strings.Join(stored, " "), Domain: usageDomain(domain), Note: optionalText(note)}
+}
+
+// Replaces the labels, domain and note of a translation
+func (r *DictionaryService) SetUsage(text string, translation string, usage model.UsageInput) (*model.MutationResult, error) {
+	updated := usageOf(usage.Labels, usage.Domain, usage.Note)
+
+	_, err := r.repository.WithTransaction(func(txRepo IRepository) error {
+		var dbtranslation dbmodels.Translation
+		if err := txRepo.GetTranslation(text, translation, &dbtranslation); err != nil {
+			return err
+		}
+		return txRepo.UpdateUsage(&dbtranslation, &updated)
+	}, false, false)
+
+	if err != nil {
+		return nil, err
+	}
+	return r.mutationResult(text, nil, model.MutationOutcomeUpdated)
+}
@@ -6,6 +6,51 @@ import (
 	dbmodels "github.com/staszkiet/DictionaryGolang/server/database/models"
 )
 
+// Stable codes sent to the client in the "code" error extension
+const (
+	CodeWordExists          = "WORD_EXISTS"
+	CodeTranslationExists   = "TRANSLATION_EXISTS"
+	CodeSentenceExists      = "SENTENCE_EXISTS"
+	CodeWordNotFound        = "WORD_NOT_FOUND"
+	CodeEnglishWordNotFound = "ENGLISH_WORD_NOT_FOUND"
+	CodeTranslationNotFound = "TRANSLATION_NOT_FOUND"
+	CodeSentenceNotFound    = "SENTENCE_NOT_FOUND"
+	CodeInvalidCursor       = "INVALID_CURSOR"
+	CodeInvalidPageSize     = "INVALID_PAGE_SIZE"
+	CodeInvalidEntry        = "INVALID_ENTRY"
+	CodeImportFailed        = "IMPORT_FAILED"
+	CodeMalformedRow        = "MALFORMED_ROW"
+	CodeMissingColumn       = "MISSING_COLUMN"
+	CodeInvalidDelimiter    = "INVALID_DELIMITER"
+	CodeInvalidExportFormat = "INVALID_EXPORT_FORMAT"
+	CodeCardNotFound        = "CARD_NOT_FOUND"
+	CodeInvalidGrade        = "INVALID_GRADE"
+	CodeInvalidQuestion     = "INVALID_QUESTION"
+	CodeGrammarMismatch     = "GRAMMAR_MISMATCH"
+	CodeInvalidPartner      = "INVALID_ASPECT_PARTNER"
+	CodeInflectionExists    = "INFLECTION_EXISTS"
+	CodeInflectionNotFound  = "INFLECTION_NOT_FOUND"
+	CodeInvalidLanguagePair = "INVALID_LANGUAGE_PAIR"
+	CodeRelationExists      = "RELATION_EXISTS"
+	CodeRelationNotFound    = "RELATION_NOT_FOUND"
+	CodeInvalidRelation     = "INVALID_RELATION"
+	CodeInvalidName         = "INVALID_NAME"
+	CodeTagExists           = "TAG_EXISTS"
+	CodeTagNotFound         = "TAG_NOT_FOUND"
+	CodeCollectionExists    = "COLLECTION_EXISTS"
+	CodeCollectionNotFound  = "COLLECTION_NOT_FOUND"
+	CodeItemExists          = "COLLECTION_ITEM_EXISTS"
+	CodeItemNotFound        = "COLLECTION_ITEM_NOT_FOUND"
+	CodeInvalidStress       = "INVALID_STRESS"
+	CodeUnsupportedAudio    = "UNSUPPORTED_AUDIO"
+	CodeInternal            = "INTERNAL_ERROR"
+)
+
+// Errors implementing this interface carry data which is sent to the client as GraphQL error extensions
+type ExtendedError interface {
+	Extensions() map[string]interface{}
+}
+
 //errors for adding values to DB
 
 type WordExistsError struct {
@@ -13,7 +58,11 @@ type WordExistsError struct {
 }
 
 func (e WordExistsError) Error() string {
-	return fmt.Sprintf("słowo %s znajduje się już w słowniku", e.Word)
+	return Message(CodeWordExists, DefaultLanguage, e.Extensions())
+}
+
+func (e WordExistsError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeWordExists, "word": e.Word}
 }
 
 type SentenceExistsError struct {
@@ -21,7 +70,11 @@ type SentenceExistsError struct {
 }
 
 func (e SentenceExistsError) Error() string {
-	return fmt.Sprintf("zdanie %s już jest dodane do tłumaczenia danego słowa", e.Sentence)
+	return Message(CodeSentenceExists, DefaultLanguage, e.Extensions())
+}
+
+func (e SentenceExistsError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeSentenceExists, "sentence": e.Sentence}
 }
 
 type TranslationExistsError struct {
@@ -29,7 +82,11 @@ type TranslationExistsError struct {
 }
 
 func (e TranslationExistsError) Error() string {
-	return fmt.Sprintf("tłumaczenie %s już jest dodane do danego słowa", e.Translation)
+	return Message(CodeTranslationExists, DefaultLanguage, e.Extensions())
+}
+
+func (e TranslationExistsError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeTranslationExists, "translation": e.Translation}
 }
 
 func GetEntityExistsError(entity interface{}) error {
@@ -83,7 +140,40 @@ type WordNotExistsError struct {
 }
 
 func (e WordNotExistsError) Error() string {
-	return fmt.Sprintf("słowa %s nie ma w słowniku", e.Word)
+	return Message(CodeWordNotFound, DefaultLanguage, e.Extensions())
+}
+
+func (e WordNotExistsError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeWordNotFound, "word": e.Word}
+}
+
+// Returned when the word doesn't exist, but there are similar ones in the dictionary.
+// It unwraps to WordNotExistsError, so it can be handled the same way
+type WordSuggestionsError struct {
+	WordNotExistsError
+	Suggestions []string
+}
+
+func (e WordSuggestionsError) Unwrap() error {
+	return e.WordNotExistsError
+}
+
+func (e WordSuggestionsError) Extensions() map[string]interface{} {
+	extensions := e.WordNotExistsError.Extensions()
+	extensions["suggestions"] = e.Suggestions
+	return extensions
+}
+
+type EnglishWordNotExistsError struct {
+	English string
+}
+
+func (e EnglishWordNotExistsError) Error() string {
+	return Message(CodeEnglishWordNotFound, DefaultLanguage, e.Extensions())
+}
+
+func (e EnglishWordNotExistsError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeEnglishWordNotFound, "translation": e.English}
 }
 
 type SentenceNotExistsError struct {
@@ -93,7 +183,11 @@ type SentenceNotExistsError struct {
 }
 
 func (e SentenceNotExistsError) Error() string {
-	return fmt.Sprintf("zdanie %s prezentujące tłumaczenie %s słowa %s nie istnieje w słowniku", e.Sentence, e.Translation, e.Word)
+	return Message(CodeSentenceNotFound, DefaultLanguage, e.Extensions())
+}
+
+func (e SentenceNotExistsError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeSentenceNotFound, "word": e.Word, "translation": e.Translation, "sentence": e.Sentence}
 }
 
 type TranslationNotExistsError struct {
@@ -102,7 +196,11 @@ type TranslationNotExistsError struct {
 }
 
 func (e TranslationNotExistsError) Error() string {
-	return fmt.Sprintf("tłumaczenie %s słowa %s nie istnieje w słowniku", e.Translation, e.Word)
+	return Message(CodeTranslationNotFound, DefaultLanguage, e.Extensions())
+}
+
+func (e TranslationNotExistsError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeTranslationNotFound, "word": e.Word, "translation": e.Translation}
 }
 
 //errors for deleting values form DB. They are not tested because they in every function are preceded by a get function which should call
@@ -113,21 +211,417 @@ type CantDeleteWordError struct {
 }
 
 func (e CantDeleteWordError) Error() string {
-	return fmt.Sprintf("słowa %s nie ma w słowniku", e.Word)
+	return Message(CodeWordNotFound, DefaultLanguage, e.Extensions())
+}
+
+func (e CantDeleteWordError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeWordNotFound, "word": e.Word}
 }
 
 type CantDeleteSentenceError struct {
-	Sentence string
+	Word        string
+	Translation string
+	Sentence    string
 }
 
 func (e CantDeleteSentenceError) Error() string {
-	return fmt.Sprintf("zdanie %s dla podanego słowa i tłumaczenia nie istnieje. Sprawdź czy słowo i tłumaczenie są w słowniku", e.Sentence)
+	return Message(CodeSentenceNotFound, DefaultLanguage, e.Extensions())
+}
+
+func (e CantDeleteSentenceError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeSentenceNotFound, "word": e.Word, "translation": e.Translation, "sentence": e.Sentence}
 }
 
 type CantDeleteTranslationError struct {
+	Word        string
 	Translation string
 }
 
 func (e CantDeleteTranslationError) Error() string {
-	return fmt.Sprintf("tłumaczenie %s podanego słowa nie istnieje. Sprawdź czy słowo znajduje się w słowniku ", e.Translation)
+	return Message(CodeTranslationNotFound, DefaultLanguage, e.Extensions())
+}
+
+func (e CantDeleteTranslationError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeTranslationNotFound, "word": e.Word, "translation": e.Translation}
+}
+
+//errors for browsing the dictionary
+
+type InvalidCursorError struct {
+	Cursor string
+}
+
+func (e InvalidCursorError) Error() string {
+	return Message(CodeInvalidCursor, DefaultLanguage, e.Extensions())
+}
+
+func (e InvalidCursorError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeInvalidCursor, "cursor": e.Cursor}
+}
+
+type InvalidPageSizeError struct {
+	First int
+	Max   int
+}
+
+func (e InvalidPageSizeError) Error() string {
+	return Message(CodeInvalidPageSize, DefaultLanguage, e.Extensions())
+}
+
+func (e InvalidPageSizeError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeInvalidPageSize, "first": e.First, "max": e.Max}
+}
+
+//errors for importing many words at once
+
+type InvalidEntryError struct {
+	Index int
+	Field string
+}
+
+func (e InvalidEntryError) Error() string {
+	return Message(CodeInvalidEntry, DefaultLanguage, e.Extensions())
+}
+
+func (e InvalidEntryError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeInvalidEntry, "index": e.Index, "field": e.Field}
+}
+
+// Returned by an atomic import when one of the entries fails. Reason is the code of the entry's error
+type ImportFailedError struct {
+	Index  int
+	Reason string
+}
+
+func (e ImportFailedError) Error() string {
+	return Message(CodeImportFailed, DefaultLanguage, e.Extensions())
+}
+
+func (e ImportFailedError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeImportFailed, "index": e.Index, "reason": e.Reason}
+}
+
+//errors for importing files
+
+type MalformedRowError struct {
+	Line int
+}
+
+func (e MalformedRowError) Error() string {
+	return Message(CodeMalformedRow, DefaultLanguage, e.Extensions())
+}
+
+func (e MalformedRowError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeMalformedRow, "line": e.Line}
+}
+
+type MissingColumnError struct {
+	Column string
+}
+
+func (e MissingColumnError) Error() string {
+	return Message(CodeMissingColumn, DefaultLanguage, e.Extensions())
+}
+
+func (e MissingColumnError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeMissingColumn, "column": e.Column}
+}
+
+type InvalidDelimiterError struct {
+	Delimiter string
+}
+
+func (e InvalidDelimiterError) Error() string {
+	return Message(CodeInvalidDelimiter, DefaultLanguage, e.Extensions())
+}
+
+func (e InvalidDelimiterError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeInvalidDelimiter, "delimiter": e.Delimiter}
+}
+
+//errors for exporting the dictionary
+
+type InvalidExportFormatError struct {
+	Format string
+}
+
+func (e InvalidExportFormatError) Error() string {
+	return Message(CodeInvalidExportFormat, DefaultLanguage, e.Extensions())
+}
+
+func (e InvalidExportFormatError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeInvalidExportFormat, "format": e.Format}
+}
+
+//errors for studying flashcards
+
+type CardNotExistsError struct {
+	TranslationID string
+}
+
+func (e CardNotExistsError) Error() string {
+	return Message(CodeCardNotFound, DefaultLanguage, e.Extensions())
+}
+
+func (e CardNotExistsError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeCardNotFound, "translationId": e.TranslationID}
+}
+
+type InvalidGradeError struct {
+	Grade int
+	Min   int
+	Max   int
+}
+
+func (e InvalidGradeError) Error() string {
+	return Message(CodeInvalidGrade, DefaultLanguage, e.Extensions())
+}
+
+func (e InvalidGradeError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeInvalidGrade, "grade": e.Grade, "min": e.Min, "max": e.Max}
+}
+
+//errors for quizzes
+
+type InvalidQuestionError struct {
+	QuestionID string
+}
+
+func (e InvalidQuestionError) Error() string {
+	return Message(CodeInvalidQuestion, DefaultLanguage, e.Extensions())
+}
+
+func (e InvalidQuestionError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeInvalidQuestion, "questionId": e.QuestionID}
+}
+
+//errors for grammatical metadata
+
+type GrammarMismatchError struct {
+	Field        string
+	PartOfSpeech string
+}
+
+func (e GrammarMismatchError) Error() string {
+	return Message(CodeGrammarMismatch, DefaultLanguage, e.Extensions())
+}
+
+func (e GrammarMismatchError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeGrammarMismatch, "field": e.Field, "partOfSpeech": e.PartOfSpeech}
+}
+
+type InvalidAspectPartnerError struct {
+	Word    string
+	Partner string
+}
+
+func (e InvalidAspectPartnerError) Error() string {
+	return Message(CodeInvalidPartner, DefaultLanguage, e.Extensions())
+}
+
+func (e InvalidAspectPartnerError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeInvalidPartner, "word": e.Word, "partner": e.Partner}
+}
+
+//errors for inflected forms
+
+type InflectionExistsError struct {
+	Word string
+	Form string
+	Tags string
+}
+
+func (e InflectionExistsError) Error() string {
+	return Message(CodeInflectionExists, DefaultLanguage, e.Extensions())
+}
+
+func (e InflectionExistsError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeInflectionExists, "word": e.Word, "form": e.Form, "tags": e.Tags}
+}
+
+type InflectionNotExistsError struct {
+	Word string
+	Form string
+}
+
+func (e InflectionNotExistsError) Error() string {
+	return Message(CodeInflectionNotFound, DefaultLanguage, e.Extensions())
+}
+
+func (e InflectionNotExistsError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeInflectionNotFound, "word": e.Word, "form": e.Form}
+}
+
+//errors for language pairs
+
+type InvalidLanguagePairError struct {
+	Source string
+	Target string
+}
+
+func (e InvalidLanguagePairError) Error() string {
+	return Message(CodeInvalidLanguagePair, DefaultLanguage, e.Extensions())
+}
+
+func (e InvalidLanguagePairError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeInvalidLanguagePair, "source": e.Source, "target": e.Target}
+}
+
+//errors for relations, From and To are words or translations written as "word (translation)"
+
+type RelationExistsError struct {
+	Kind string
+	From string
+	To   string
+}
+
+func (e RelationExistsError) Error() string {
+	return Message(CodeRelationExists, DefaultLanguage, e.Extensions())
+}
+
+func (e RelationExistsError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeRelationExists, "kind": e.Kind, "from": e.From, "to": e.To}
+}
+
+type RelationNotExistsError struct {
+	Kind string
+	From string
+	To   string
+}
+
+func (e RelationNotExistsError) Error() string {
+	return Message(CodeRelationNotFound, DefaultLanguage, e.Extensions())
+}
+
+func (e RelationNotExistsError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeRelationNotFound, "kind": e.Kind, "from": e.From, "to": e.To}
+}
+
+type InvalidRelationError struct {
+	From string
+	To   string
+}
+
+func (e InvalidRelationError) Error() string {
+	return Message(CodeInvalidRelation, DefaultLanguage, e.Extensions())
+}
+
+func (e InvalidRelationError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeInvalidRelation, "from": e.From, "to": e.To}
+}
+
+//errors for tags and collections
+
+type InvalidNameError struct {
+	Name string
+}
+
+func (e InvalidNameError) Error() string {
+	return Message(CodeInvalidName, DefaultLanguage, e.Extensions())
+}
+
+func (e InvalidNameError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeInvalidName, "name": e.Name}
+}
+
+type TagExistsError struct {
+	Tag string
+}
+
+func (e TagExistsError) Error() string {
+	return Message(CodeTagExists, DefaultLanguage, e.Extensions())
+}
+
+func (e TagExistsError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeTagExists, "tag": e.Tag}
+}
+
+type TagNotExistsError struct {
+	Tag string
+}
+
+func (e TagNotExistsError) Error() string {
+	return Message(CodeTagNotFound, DefaultLanguage, e.Extensions())
+}
+
+func (e TagNotExistsError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeTagNotFound, "tag": e.Tag}
+}
+
+type CollectionExistsError struct {
+	Collection string
+}
+
+func (e CollectionExistsError) Error() string {
+	return Message(CodeCollectionExists, DefaultLanguage, e.Extensions())
+}
+
+func (e CollectionExistsError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeCollectionExists, "collection": e.Collection}
+}
+
+type CollectionNotExistsError struct {
+	Collection string
+}
+
+func (e CollectionNotExistsError) Error() string {
+	return Message(CodeCollectionNotFound, DefaultLanguage, e.Extensions())
+}
+
+func (e CollectionNotExistsError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeCollectionNotFound, "collection": e.Collection}
+}
+
+type CollectionItemExistsError struct {
+	Collection  string
+	Word        string
+	Translation string
+}
+
+func (e CollectionItemExistsError) Error() string {
+	return Message(CodeItemExists, DefaultLanguage, e.Extensions())
+}
+
+func (e CollectionItemExistsError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeItemExists, "collection": e.Collection, "word": e.Word, "translation": e.Translation}
+}
+
+type CollectionItemNotExistsError struct {
+	Collection  string
+	Word        string
+	Translation string
+}
+
+func (e CollectionItemNotExistsError) Error() string {
+	return Message(CodeItemNotFound, DefaultLanguage, e.Extensions())
+}
+
+func (e CollectionItemNotExistsError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeItemNotFound, "collection": e.Collection, "word": e.Word, "translation": e.Translation}
+}
+
+//errors for pronunciation
+
+type InvalidStressError struct {
+	Stress int
+}
+
+func (e InvalidStressError) Error() string {
+	return Message(CodeInvalidStress, DefaultLanguage, e.Extensions())
+}
+
+func (e InvalidStressError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeInvalidStress, "stress": e.Stress}
+}
+
+type UnsupportedAudioError struct {
+	File string
+}
+
+func (e UnsupportedAudioError) Error() string {
+	return Message(CodeUnsupportedAudio, DefaultLanguage, e.Extensions())
+}
+
+func (e UnsupportedAudioError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeUnsupportedAudio, "file": e.File}
 }
@@ -0,0 +1,261 @@
+package customerrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Language string
+
+const (
+	Polish  Language = "pl"
+	English Language = "en"
+
+	// Language of messages when the client didn't ask for any supported one
+	DefaultLanguage = Polish
+)
+
+var supportedLanguages = map[Language]bool{Polish: true, English: true}
+
+// Message templates keyed by error code. {name} is replaced with the error field of that name
+var messages = map[string]map[Language]string{
+	CodeWordExists: {
+		Polish:  "słowo {word} znajduje się już w słowniku",
+		English: "word {word} is already in the dictionary",
+	},
+	CodeTranslationExists: {
+		Polish:  "tłumaczenie {translation} już jest dodane do danego słowa",
+		English: "translation {translation} is already added to the word",
+	},
+	CodeSentenceExists: {
+		Polish:  "zdanie {sentence} już jest dodane do tłumaczenia danego słowa",
+		English: "sentence {sentence} is already added to the translation of the word",
+	},
+	CodeWordNotFound: {
+		Polish:  "słowa {word} nie ma w słowniku",
+		English: "word {word} is not in the dictionary",
+	},
+	CodeEnglishWordNotFound: {
+		Polish:  "żadne słowo w słowniku nie ma tłumaczenia {translation}",
+		English: "no word in the dictionary is translated as {translation}",
+	},
+	CodeTranslationNotFound: {
+		Polish:  "tłumaczenie {translation} słowa {word} nie istnieje w słowniku",
+		English: "translation {translation} of word {word} doesn't exist in the dictionary",
+	},
+	CodeSentenceNotFound: {
+		Polish:  "zdanie {sentence} prezentujące tłumaczenie {translation} słowa {word} nie istnieje w słowniku",
+		English: "sentence {sentence} showing translation {translation} of word {word} doesn't exist in the dictionary",
+	},
+	CodeInvalidCursor: {
+		Polish:  "kursor {cursor} jest niepoprawny",
+		English: "cursor {cursor} is invalid",
+	},
+	CodeInvalidPageSize: {
+		Polish:  "rozmiar strony {first} jest niepoprawny, podaj liczbę od 1 do {max}",
+		English: "page size {first} is invalid, give a number from 1 to {max}",
+	},
+	CodeInvalidEntry: {
+		Polish:  "wpis {index} ma puste pole {field}",
+		English: "entry {index} has empty field {field}",
+	},
+	CodeImportFailed: {
+		Polish:  "import przerwany, wpis {index} jest niepoprawny ({reason})",
+		English: "import aborted, entry {index} is invalid ({reason})",
+	},
+	CodeMalformedRow: {
+		Polish:  "wiersz {line} pliku jest niepoprawny",
+		English: "row {line} of the file is malformed",
+	},
+	CodeMissingColumn: {
+		Polish:  "plik nie ma kolumny {column}",
+		English: "the file has no column {column}",
+	},
+	CodeInvalidDelimiter: {
+		Polish:  "separator {delimiter} jest niepoprawny, podaj jeden znak",
+		English: "delimiter {delimiter} is invalid, give a single character",
+	},
+	CodeInvalidExportFormat: {
+		Polish:  "format eksportu {format} jest niepoprawny, dostępne: json, ndjson, csv, anki, apkg",
+		English: "export format {format} is invalid, available: json, ndjson, csv, anki, apkg",
+	},
+	CodeCardNotFound: {
+		Polish:  "fiszka {translationId} nie istnieje",
+		English: "card {translationId} doesn't exist",
+	},
+	CodeInvalidGrade: {
+		Polish:  "ocena {grade} jest niepoprawna, podaj liczbę od {min} do {max}",
+		English: "grade {grade} is invalid, give a number from {min} to {max}",
+	},
+	CodeInvalidQuestion: {
+		Polish:  "pytanie {questionId} jest niepoprawne",
+		English: "question {questionId} is invalid",
+	},
+	CodeGrammarMismatch: {
+		Polish:  "pole {field} nie dotyczy części mowy {partOfSpeech}",
+		English: "{field} doesn't apply to part of speech {partOfSpeech}",
+	},
+	CodeInvalidPartner: {
+		Polish:  "słowo {partner} nie może być parą aspektową słowa {word}",
+		English: "word {partner} can't be the aspect partner of word {word}",
+	},
+	CodeInflectionExists: {
+		Polish:  "forma {form} ({tags}) słowa {word} już jest w słowniku",
+		English: "form {form} ({tags}) of word {word} is already in the dictionary",
+	},
+	CodeInflectionNotFound: {
+		Polish:  "forma {form} słowa {word} nie istnieje w słowniku",
+		English: "form {form} of word {word} doesn't exist in the dictionary",
+	},
+	CodeInvalidLanguagePair: {
+		Polish:  "nie można tłumaczyć z języka {source} na język {target}",
+		English: "can't translate from language {source} to language {target}",
+	},
+	CodeRelationExists: {
+		Polish:  "relacja {kind} między {from} i {to} już jest w słowniku",
+		English: "relation {kind} between {from} and {to} is already in the dictionary",
+	},
+	CodeRelationNotFound: {
+		Polish:  "relacja {kind} między {from} i {to} nie istnieje w słowniku",
+		English: "relation {kind} between {from} and {to} doesn't exist in the dictionary",
+	},
+	CodeInvalidRelation: {
+		Polish:  "nie można powiązać {from} z {to}",
+		English: "can't relate {from} to {to}",
+	},
+	CodeInvalidName: {
+		Polish:  "niepoprawna nazwa \"{name}\"",
+		English: "invalid name \"{name}\"",
+	},
+	CodeTagExists: {
+		Polish:  "tag {tag} już istnieje",
+		English: "tag {tag} already exists",
+	},
+	CodeTagNotFound: {
+		Polish:  "tag {tag} nie istnieje",
+		English: "tag {tag} doesn't exist",
+	},
+	CodeCollectionExists: {
+		Polish:  "kolekcja {collection} już istnieje",
+		English: "collection {collection} already exists",
+	},
+	CodeCollectionNotFound: {
+		Polish:  "kolekcja {collection} nie istnieje",
+		English: "collection {collection} doesn't exist",
+	},
+	CodeItemExists: {
+		Polish:  "tłumaczenie {translation} słowa {word} już jest w kolekcji {collection}",
+		English: "translation {translation} of word {word} is already in collection {collection}",
+	},
+	CodeItemNotFound: {
+		Polish:  "tłumaczenia {translation} słowa {word} nie ma w kolekcji {collection}",
+		English: "translation {translation} of word {word} isn't in collection {collection}",
+	},
+	CodeInvalidStress: {
+		Polish:  "niepoprawny akcent {stress}, sylaby są liczone od 1",
+		English: "invalid stress {stress}, syllables are counted from 1",
+	},
+	CodeUnsupportedAudio: {
+		Polish:  "plik {file} nie jest obsługiwanym nagraniem (mp3, ogg, opus, wav, m4a, webm, flac)",
+		English: "file {file} isn't a supported audio clip (mp3, ogg, opus, wav, m4a, webm, flac)",
+	},
+	CodeInternal: {
+		Polish:  "wewnętrzny błąd serwera (id: {errorId})",
+		English: "internal server error (id: {errorId})",
+	},
+}
+
+// Returns the message for given error code in given language, filled with the error fields
+func Message(code string, language Language, fields map[string]interface{}) string {
+	templates := messages[code]
+	template, ok := templates[language]
+	if !ok {
+		template = templates[DefaultLanguage]
+	}
+
+	replacements := make([]string, 0, 2*len(fields))
+	for name, value := range fields {
+		replacements = append(replacements, "{"+name+"}", fmt.Sprint(value))
+	}
+	return strings.NewReplacer(replacements...).Replace(template)
+}
+
+// Returns the message of err in given language. Errors without a code keep their own message
+func Localize(err error, language Language) string {
+	var extended ExtendedError
+	if !errors.As(err, &extended) {
+		return err.Error()
+	}
+
+	fields := extended.Extensions()
+	code, _ := fields["code"].(string)
+	if _, ok := messages[code]; !ok {
+		return err.Error()
+	}
+	return Message(code, language, fields)
+}
+
+// Returns the code of err, CodeInternal for errors without one
+func Code(err error) string {
+	var extended ExtendedError
+	if errors.As(err, &extended) {
+		if code, ok := extended.Extensions()["code"].(string); ok {
+			return code
+		}
+	}
+	return CodeInternal
+}
+
+type languageKey struct{}
+
+func WithLanguage(ctx context.Context, language Language) context.Context {
+	return context.WithValue(ctx, languageKey{}, language)
+}
+
+func LanguageFromContext(ctx context.Context) Language {
+	if language, ok := ctx.Value(languageKey{}).(Language); ok {
+		return language
+	}
+	return DefaultLanguage
+}
+
+// Picks the supported language with the highest quality from an Accept-Language header,
+// e.g. "en-GB,en;q=0.9,pl;q=0.8". Returns DefaultLanguage if none of them is supported
+func ParseAcceptLanguage(header string) Language {
+	type candidate struct {
+		language Language
+		quality  float64
+	}
+
+	candidates := []candidate{}
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+
+		language := Language(base)
+		if supportedLanguages[language] && quality > 0 {
+			candidates = append(candidates, candidate{language: language, quality: quality})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return DefaultLanguage
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	return candidates[0].language
+}
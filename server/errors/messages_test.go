@@ -0,0 +1,30 @@
+package customerrors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAcceptLanguage_ShouldPickSupportedLanguageWithHighestQuality(t *testing.T) {
+	assert.Equal(t, English, ParseAcceptLanguage("en-GB,en;q=0.9,pl;q=0.8"))
+	assert.Equal(t, Polish, ParseAcceptLanguage("de-DE,pl;q=0.5,en;q=0.4"))
+	assert.Equal(t, English, ParseAcceptLanguage("pl;q=0.2, en;q=0.7"))
+	assert.Equal(t, DefaultLanguage, ParseAcceptLanguage("de,fr;q=0.9"))
+	assert.Equal(t, DefaultLanguage, ParseAcceptLanguage(""))
+}
+
+func TestLocalize_ShouldFillMessageWithErrorFields(t *testing.T) {
+	err := SentenceNotExistsError{Word: "rower", Translation: "bike", Sentence: "I like my bike"}
+
+	assert.Equal(t, "sentence I like my bike showing translation bike of word rower doesn't exist in the dictionary", Localize(err, English))
+	assert.Equal(t, err.Error(), Localize(err, Polish))
+}
+
+func TestMessages_EveryCodeShouldHaveEverySupportedLanguage(t *testing.T) {
+	for code, templates := range messages {
+		for language := range supportedLanguages {
+			assert.NotEmpty(t, templates[language], "%s has no %s message", code, language)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/staszkiet/DictionaryGolang/server/graph/model"
+)
+
+// how many events can wait for a slow subscriber before next ones are dropped
+const subscriberBuffer = 16
+
+// Broker passes word change events from mutations to every active subscription.
+// A nil Broker is valid and drops all events
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[chan *model.WordChangedEvent]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{subscribers: make(map[chan *model.WordChangedEvent]struct{})}
+}
+
+// Returns a channel receiving published events until ctx is done, then the channel is closed
+func (b *Broker) Subscribe(ctx context.Context) <-chan *model.WordChangedEvent {
+	ch := make(chan *model.WordChangedEvent, subscriberBuffer)
+
+	if b == nil {
+		go func() {
+			<-ctx.Done()
+			close(ch)
+		}()
+		return ch
+	}
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subscribers, ch)
+		close(ch)
+		b.mu.Unlock()
+	}()
+
+	return ch
+}
+
+func (b *Broker) HasSubscribers() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscribers) > 0
+}
+
+// Sends the event to all subscribers without blocking the publishing mutation
+func (b *Broker) Publish(event *model.WordChangedEvent) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
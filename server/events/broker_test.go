@@ -0,0 +1,34 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/staszkiet/DictionaryGolang/server/graph/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBroker_Publish_ShouldDeliverEventToEverySubscriber(t *testing.T) {
+	broker := NewBroker()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	first := broker.Subscribe(ctx)
+	second := broker.Subscribe(ctx)
+	event := &model.WordChangedEvent{Kind: model.ChangeKindCreated, Polish: "rower"}
+
+	broker.Publish(event)
+
+	assert.Equal(t, event, <-first)
+	assert.Equal(t, event, <-second)
+
+	cancel()
+	_, open := <-first
+	assert.False(t, open)
+}
+
+func TestBroker_Nil_ShouldDropEvents(t *testing.T) {
+	var broker *Broker
+
+	assert.False(t, broker.HasSubscribers())
+	assert.NotPanics(t, func() { broker.Publish(&model.WordChangedEvent{}) })
+}
@@ -0,0 +1,356 @@
+package exporter
+
+import (
+	"archive/zip"
+	"bufio"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/staszkiet/DictionaryGolang/server/graph/model"
+	_ "modernc.org/sqlite"
+)
+
+// Names of a language in Anki: the note field of its words and the polish adjective in the names of the deck
+// and the card, e.g. "Słownik polsko-angielski"
+type ankiLanguage struct {
+	field     string
+	adjective string
+	prefix    string
+}
+
+var ankiLanguages = map[model.Language]ankiLanguage{
+	model.LanguagePl: {field: "Polish", adjective: "polski", prefix: "polsko"},
+	model.LanguageEn: {field: "English", adjective: "angielski", prefix: "angielsko"},
+	model.LanguageDe: {field: "German", adjective: "niemiecki", prefix: "niemiecko"},
+	model.LanguageEs: {field: "Spanish", adjective: "hiszpański", prefix: "hiszpańsko"},
+}
+
+// Every translation becomes a note with the word, its translation and sentences as fields. Fields are HTML, as Anki expects
+func ankiFields(pair model.LanguagePair) []string {
+	return []string{ankiLanguages[pair.Source].field, ankiLanguages[pair.Target].field, "Sentences"}
+}
+
+func ankiDeck(pair model.LanguagePair) string {
+	return "Słownik " + ankiLanguages[pair.Source].prefix + "-" + ankiLanguages[pair.Target].adjective
+}
+
+// Ids of the note type and the deck stay the same in every export of a pair, so importing another export
+// adds notes to the deck created before. Polish-english keeps the ids it had before other pairs were added
+const ankiBaseID = 1702143755
+
+func ankiIDs(pair model.LanguagePair) (modelID int64, deckID int64) {
+	offset := 0
+	for i, language := range model.AllLanguage {
+		if language == pair.Source {
+			offset += i * len(model.AllLanguage)
+		}
+		if language == pair.Target {
+			offset += i
+		}
+	}
+	modelID = ankiBaseID + 2*int64(offset)
+	return modelID, modelID + 1
+}
+
+// Returns the note fields of a translation: the words and its sentences in separate lines,
+// with the english word and its forms (e.g. bikes for bike) in bold
+func ankiNote(polish string, translation *model.Translation) []string {
+	headword := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(html.EscapeString(translation.English)) + `\w*`)
+
+	sentences := make([]string, 0, len(translation.Sentences))
+	for _, s := range translation.Sentences {
+		sentences = append(sentences, headword.ReplaceAllString(html.EscapeString(s.Sentence), "<b>$0</b>"))
+	}
+	return []string{html.EscapeString(polish), html.EscapeString(translation.English), strings.Join(sentences, "<br>")}
+}
+
+// Anki recognizes notes by their GUID, so deriving it from the translation lets a later export
+// update notes imported before instead of duplicating them. Other pairs than polish-english add the pair to it,
+// so the same words of two pairs don't update each other's notes
+func ankiGUID(pair model.LanguagePair, polish string, english string) string {
+	key := polish + "\x1f" + english
+	if pair != DefaultPair {
+		key = string(pair.Source) + "-" + string(pair.Target) + "\x1f" + key
+	}
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
+type ankiTextEncoder struct {
+	pair     model.LanguagePair
+	buffered *bufio.Writer
+	w        *csv.Writer
+}
+
+// Writes file headers understood by Anki 2.1.55+, so the columns don't have to be set up by hand.
+// Like the notes, they are buffered until the first flush
+func newAnkiTextEncoder(pair model.LanguagePair, w io.Writer) (*ankiTextEncoder, error) {
+	buffered := bufio.NewWriter(w)
+	header := "#separator:tab\n#html:true\n#guid column:1\n#columns:GUID\t" + strings.Join(ankiFields(pair), "\t") + "\n"
+	if _, err := buffered.WriteString(header); err != nil {
+		return nil, err
+	}
+
+	encoder := &ankiTextEncoder{pair: pair, buffered: buffered, w: csv.NewWriter(buffered)}
+	encoder.w.Comma = '\t'
+	return encoder, nil
+}
+
+func (e *ankiTextEncoder) Encode(word *model.Word) error {
+	for _, t := range word.Translations {
+		if err := e.w.Write(append([]string{ankiGUID(e.pair, word.Polish, t.English)}, ankiNote(word.Polish, t)...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *ankiTextEncoder) Close() error {
+	e.w.Flush()
+	if err := e.w.Error(); err != nil {
+		return err
+	}
+	return e.buffered.Flush()
+}
+
+// Schema of the collection in the legacy .anki2 format, which every Anki version can import
+const ankiSchema = `
+CREATE TABLE col (id integer primary key, crt integer not null, mod integer not null, scm integer not null,
+	ver integer not null, dty integer not null, usn integer not null, ls integer not null, conf text not null,
+	models text not null, decks text not null, dconf text not null, tags text not null);
+CREATE TABLE notes (id integer primary key, guid text not null, mid integer not null, mod integer not null,
+	usn integer not null, tags text not null, flds text not null, sfld integer not null, csum integer not null,
+	flags integer not null, data text not null);
+CREATE TABLE cards (id integer primary key, nid integer not null, did integer not null, ord integer not null,
+	mod integer not null, usn integer not null, type integer not null, queue integer not null, due integer not null,
+	ivl integer not null, factor integer not null, reps integer not null, lapses integer not null, left integer not null,
+	odue integer not null, odid integer not null, flags integer not null, data text not null);
+CREATE TABLE revlog (id integer primary key, cid integer not null, usn integer not null, ease integer not null,
+	ivl integer not null, lastIvl integer not null, factor integer not null, time integer not null, type integer not null);
+CREATE TABLE graves (usn integer not null, oid integer not null, type integer not null);
+CREATE INDEX ix_notes_usn on notes (usn);
+CREATE INDEX ix_cards_usn on cards (usn);
+CREATE INDEX ix_revlog_usn on revlog (usn);
+CREATE INDEX ix_cards_nid on cards (nid);
+CREATE INDEX ix_cards_sched on cards (did, queue, due);
+CREATE INDEX ix_revlog_cid on revlog (cid);
+CREATE INDEX ix_notes_csum on notes (csum);`
+
+// Builds the deck in a temporary SQLite file, because the package can only be zipped when it's complete
+type apkgEncoder struct {
+	w       io.Writer
+	pair    model.LanguagePair
+	modelID int64
+	deckID  int64
+	dir     string
+	db      *sql.DB
+	tx      *sql.Tx
+	now     time.Time
+	nextID  int64
+	due     int
+}
+
+func newApkgEncoder(pair model.LanguagePair, w io.Writer) (*apkgEncoder, error) {
+	dir, err := os.MkdirTemp("", "anki-export-")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	e := &apkgEncoder{w: w, pair: pair, dir: dir, now: now, nextID: now.UnixMilli()}
+	e.modelID, e.deckID = ankiIDs(pair)
+	if err := e.createCollection(); err != nil {
+		e.Discard()
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *apkgEncoder) createCollection() error {
+	db, err := sql.Open("sqlite", filepath.Join(e.dir, "collection.anki2"))
+	if err != nil {
+		return err
+	}
+	e.db = db
+
+	if _, err := db.Exec(ankiSchema); err != nil {
+		return err
+	}
+
+	conf, models, decks, dconf, err := ankiCollectionConfig(e.now, e.pair)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`INSERT INTO col VALUES (1, ?, ?, ?, 11, 0, 0, 0, ?, ?, ?, ?, '{}')`,
+		e.now.Unix(), e.now.UnixMilli(), e.now.UnixMilli(), conf, models, decks, dconf)
+	if err != nil {
+		return err
+	}
+
+	e.tx, err = db.Begin()
+	return err
+}
+
+func (e *apkgEncoder) Encode(word *model.Word) error {
+	for _, t := range word.Translations {
+		noteID := e.id()
+		fields := ankiNote(word.Polish, t)
+		_, err := e.tx.Exec(`INSERT INTO notes VALUES (?, ?, ?, ?, -1, '', ?, ?, ?, 0, '')`,
+			noteID, ankiGUID(e.pair, word.Polish, t.English), e.modelID, e.now.Unix(), strings.Join(fields, "\x1f"), word.Polish, ankiChecksum(word.Polish))
+		if err != nil {
+			return err
+		}
+
+		//new card, shown in the order of the export
+		e.due++
+		_, err = e.tx.Exec(`INSERT INTO cards VALUES (?, ?, ?, 0, ?, -1, 0, 0, ?, 0, 0, 0, 0, 0, 0, 0, 0, '')`,
+			e.id(), noteID, e.deckID, e.now.Unix(), e.due)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *apkgEncoder) id() int64 {
+	e.nextID++
+	return e.nextID
+}
+
+// Zips the collection into the package and removes the temporary files
+func (e *apkgEncoder) Close() error {
+	defer e.Discard()
+
+	if err := e.tx.Commit(); err != nil {
+		return err
+	}
+	if err := e.db.Close(); err != nil {
+		return err
+	}
+
+	archive := zip.NewWriter(e.w)
+	collection, err := os.Open(filepath.Join(e.dir, "collection.anki2"))
+	if err != nil {
+		return err
+	}
+	defer collection.Close()
+
+	part, err := archive.Create("collection.anki2")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, collection); err != nil {
+		return err
+	}
+
+	//the package has no media files
+	media, err := archive.Create("media")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(media, "{}"); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+func (e *apkgEncoder) Discard() {
+	if e.tx != nil {
+		e.tx.Rollback()
+	}
+	if e.db != nil {
+		e.db.Close()
+	}
+	os.RemoveAll(e.dir)
+}
+
+// Anki finds duplicates by the checksum of the first field: first 8 hex digits of its SHA1 as a number
+func ankiChecksum(field string) int64 {
+	sum := sha1.Sum([]byte(field))
+	checksum, _ := strconv.ParseInt(hex.EncodeToString(sum[:4]), 16, 64)
+	return checksum
+}
+
+// Returns the JSON columns of the collection: its configuration, the note type, the decks and their options
+func ankiCollectionConfig(now time.Time, pair model.LanguagePair) (conf, models, decks, dconf string, err error) {
+	modelID, deckID := ankiIDs(pair)
+	deck := ankiDeck(pair)
+	names := ankiFields(pair)
+	source := ankiLanguages[pair.Source].adjective
+
+	fields := make([]map[string]interface{}, 0, len(names))
+	for i, name := range names {
+		fields = append(fields, map[string]interface{}{
+			"name": name, "ord": i, "sticky": false, "rtl": false, "font": "Arial", "size": 20, "media": []string{},
+		})
+	}
+
+	values := []interface{}{
+		map[string]interface{}{
+			"activeDecks": []int{1}, "curDeck": 1, "newSpread": 0, "collapseTime": 1200, "timeLim": 0,
+			"estTimes": true, "dueCounts": true, "curModel": nil, "nextPos": 1, "sortType": "noteFld",
+			"sortBackwards": false, "addToCur": true,
+		},
+		map[string]interface{}{
+			strconv.FormatInt(modelID, 10): map[string]interface{}{
+				"id": modelID, "name": deck, "type": 0, "mod": now.Unix(), "usn": -1, "sortf": 0, "did": deckID,
+				"tmpls": []map[string]interface{}{{
+					"name": strings.ToUpper(source[:1]) + source[1:] + " → " + ankiLanguages[pair.Target].adjective, "ord": 0, "did": nil, "bqfmt": "", "bafmt": "",
+					"qfmt": "{{" + names[0] + "}}",
+					"afmt": "{{FrontSide}}<hr id=answer>{{" + names[1] + "}}{{#Sentences}}<div class=sentences>{{Sentences}}</div>{{/Sentences}}",
+				}},
+				"flds": fields,
+				"css": ".card { font-family: arial; font-size: 20px; text-align: center; color: black; background-color: white; }\n" +
+					".sentences { margin-top: 1em; font-size: 16px; }",
+				"latexPre":  "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\begin{document}\n",
+				"latexPost": "\\end{document}", "latexsvg": false,
+				"req": []interface{}{[]interface{}{0, "any", []int{0}}}, "tags": []string{}, "vers": []int{},
+			},
+		},
+		map[string]interface{}{
+			"1":                           ankiDeckConfig(1, "Default", now),
+			strconv.FormatInt(deckID, 10): ankiDeckConfig(deckID, deck, now),
+		},
+		map[string]interface{}{
+			"1": map[string]interface{}{
+				"id": 1, "name": "Default", "mod": 0, "usn": 0, "maxTaken": 60, "autoplay": true, "timer": 0, "replayq": true,
+				"new": map[string]interface{}{
+					"bury": true, "delays": []int{1, 10}, "initialFactor": 2500, "ints": []int{1, 4, 7}, "order": 1, "perDay": 20, "separate": true,
+				},
+				"lapse": map[string]interface{}{"delays": []int{10}, "leechAction": 0, "leechFails": 8, "minInt": 1, "mult": 0},
+				"rev": map[string]interface{}{
+					"bury": true, "ease4": 1.3, "fuzz": 0.05, "ivlFct": 1, "maxIvl": 36500, "minSpace": 1, "perDay": 100,
+				},
+			},
+		},
+	}
+
+	encoded := make([]string, len(values))
+	for i, v := range values {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", "", "", "", err
+		}
+		encoded[i] = string(b)
+	}
+	return encoded[0], encoded[1], encoded[2], encoded[3], nil
+}
+
+func ankiDeckConfig(id int64, name string, now time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"id": id, "name": name, "desc": "", "mod": now.Unix(), "usn": -1, "conf": 1, "dyn": 0,
+		"collapsed": false, "browserCollapsed": false, "extendNew": 10, "extendRev": 50,
+		"newToday": []int{0, 0}, "revToday": []int{0, 0}, "lrnToday": []int{0, 0}, "timeToday": []int{0, 0},
+	}
+}
@@ -0,0 +1,108 @@
+package exporter
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/staszkiet/DictionaryGolang/server/graph/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnkiNote_ShouldHighlightHeadwordInSentences(t *testing.T) {
+	translation := &model.Translation{English: "bike", Sentences: []*model.Sentence{
+		{Sentence: "Bikes are <fast>"},
+		{Sentence: "I ride my bike"},
+	}}
+
+	note := ankiNote("rower", translation)
+
+	assert.Equal(t, []string{"rower", "bike", "<b>Bikes</b> are &lt;fast&gt;<br>I ride my <b>bike</b>"}, note)
+}
+
+func TestEncoder_Anki_ShouldWriteNotePerTranslation(t *testing.T) {
+	word := &model.Word{Polish: "zamek", Translations: []*model.Translation{
+		{English: "castle", Sentences: []*model.Sentence{{Sentence: "The castle is old"}}},
+		{English: "lock", Sentences: []*model.Sentence{}},
+	}}
+
+	assert.Equal(t,
+		"#separator:tab\n#html:true\n#guid column:1\n#columns:GUID\tPolish\tEnglish\tSentences\n"+
+			ankiGUID(DefaultPair, "zamek", "castle")+"\tzamek\tcastle\tThe <b>castle</b> is old\n"+
+			ankiGUID(DefaultPair, "zamek", "lock")+"\tzamek\tlock\t\n",
+		encode(t, FormatAnki, []*model.Word{word}))
+}
+
+func TestEncoder_AnkiOfOtherPair_ShouldNameFieldsAfterLanguages(t *testing.T) {
+	pair := model.LanguagePair{Source: model.LanguageDe, Target: model.LanguageEn}
+	word := &model.Word{Polish: "Haus", Translations: []*model.Translation{{English: "house", Sentences: []*model.Sentence{}}}}
+
+	assert.Equal(t,
+		"#separator:tab\n#html:true\n#guid column:1\n#columns:GUID\tGerman\tEnglish\tSentences\n"+
+			ankiGUID(pair, "Haus", "house")+"\tHaus\thouse\t\n",
+		encodePair(t, FormatAnki, pair, []*model.Word{word}))
+	assert.NotEqual(t, ankiGUID(DefaultPair, "Haus", "house"), ankiGUID(pair, "Haus", "house"))
+}
+
+func TestAnkiIDs_ShouldKeepPolishEnglishIDsAndDifferBetweenPairs(t *testing.T) {
+	modelID, deckID := ankiIDs(DefaultPair)
+	assert.Equal(t, int64(1702143757), modelID)
+	assert.Equal(t, int64(1702143758), deckID)
+
+	seen := map[int64]bool{}
+	for _, source := range model.AllLanguage {
+		for _, target := range model.AllLanguage {
+			modelID, deckID := ankiIDs(model.LanguagePair{Source: source, Target: target})
+			assert.False(t, seen[modelID] || seen[deckID])
+			seen[modelID], seen[deckID] = true, true
+		}
+	}
+	assert.Equal(t, "Słownik niemiecko-angielski", ankiDeck(model.LanguagePair{Source: model.LanguageDe, Target: model.LanguageEn}))
+}
+
+func TestEncoder_Apkg_ShouldWriteCollectionWithNotesAndCards(t *testing.T) {
+	content := encode(t, FormatApkg, words)
+
+	archive, err := zip.NewReader(strings.NewReader(content), int64(len(content)))
+	assert.NoError(t, err)
+	assert.Len(t, archive.File, 2)
+
+	collection, err := archive.Open("collection.anki2")
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(collection)
+	path := filepath.Join(t.TempDir(), "collection.anki2")
+	os.WriteFile(path, data, 0644)
+
+	db, err := sql.Open("sqlite", path)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	var guid, fields string
+	var deck int64
+	err = db.QueryRow(`SELECT n.guid, n.flds, c.did FROM notes n JOIN cards c ON c.nid = n.id WHERE n.sfld = 'rower'`).Scan(&guid, &fields, &deck)
+	assert.NoError(t, err)
+	assert.Equal(t, ankiGUID(DefaultPair, "rower", "bike"), guid)
+	assert.Equal(t, "rower\x1fbike\x1fI like my <b>bike</b>, really<br>My <b>bike</b> is green", fields)
+	_, deckID := ankiIDs(DefaultPair)
+	assert.Equal(t, deckID, deck)
+
+	var notes int
+	db.QueryRow(`SELECT count(*) FROM notes`).Scan(&notes)
+	assert.Equal(t, 2, notes)
+}
+
+func TestEncoder_ApkgDiscarded_ShouldRemoveTemporaryFiles(t *testing.T) {
+	encoder, err := NewEncoder(FormatApkg, DefaultPair, &bytes.Buffer{})
+	assert.NoError(t, err)
+	dir := encoder.(*apkgEncoder).dir
+
+	encoder.(discarder).Discard()
+
+	_, err = os.Stat(dir)
+	assert.True(t, os.IsNotExist(err))
+}
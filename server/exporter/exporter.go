@@ -0,0 +1,160 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+
+	customerrors "github.com/staszkiet/DictionaryGolang/server/errors"
+	"github.com/staszkiet/DictionaryGolang/server/graph/model"
+)
+
+type Format string
+
+const (
+	// A single JSON array of words
+	FormatJSON Format = "json"
+	// One JSON word per line, which can be processed without reading the whole file
+	FormatNDJSON Format = "ndjson"
+	// One row per sentence with columns named after the languages (polish, english and sentence by default), the same as the import expects
+	FormatCSV Format = "csv"
+	// Anki's tab-separated import format, one note per translation
+	FormatAnki Format = "anki"
+	// Anki deck package, which can be opened in Anki without any import settings
+	FormatApkg Format = "apkg"
+)
+
+var contentTypes = map[Format]string{
+	FormatJSON:   "application/json",
+	FormatNDJSON: "application/x-ndjson",
+	FormatCSV:    "text/csv",
+	FormatAnki:   "text/tab-separated-values",
+	FormatApkg:   "application/octet-stream",
+}
+
+// Pair exported when the request doesn't choose one
+var DefaultPair = model.LanguagePair{Source: model.LanguagePl, Target: model.LanguageEn}
+
+// Names of the languages in CSV headers, which the import takes for column names
+var languageNames = map[model.Language]string{
+	model.LanguagePl: "polish",
+	model.LanguageEn: "english",
+	model.LanguageDe: "german",
+	model.LanguageEs: "spanish",
+}
+
+// Extensions of downloaded files, if they differ from the format name
+var extensions = map[Format]string{
+	FormatAnki: "txt",
+}
+
+// Writes words one by one, so the export doesn't have to hold the whole dictionary in memory
+type Encoder interface {
+	Encode(word *model.Word) error
+	// Finishes the document and flushes everything written so far
+	Close() error
+}
+
+// Returns an encoder writing words of the language pair, whose languages name columns and fields of the file
+func NewEncoder(format Format, pair model.LanguagePair, w io.Writer) (Encoder, error) {
+	buffered := bufio.NewWriter(w)
+
+	switch format {
+	case FormatJSON:
+		return &jsonEncoder{w: buffered, encoder: json.NewEncoder(buffered)}, nil
+	case FormatNDJSON:
+		return &ndjsonEncoder{w: buffered, encoder: json.NewEncoder(buffered)}, nil
+	case FormatCSV:
+		encoder := &csvEncoder{w: csv.NewWriter(w)}
+		if err := encoder.w.Write([]string{languageNames[pair.Source], languageNames[pair.Target], "sentence"}); err != nil {
+			return nil, err
+		}
+		return encoder, nil
+	case FormatAnki:
+		return newAnkiTextEncoder(pair, w)
+	case FormatApkg:
+		return newApkgEncoder(pair, w)
+	}
+	return nil, customerrors.InvalidExportFormatError{Format: string(format)}
+}
+
+func ContentType(format Format) string {
+	return contentTypes[format]
+}
+
+func Extension(format Format) string {
+	if extension, ok := extensions[format]; ok {
+		return extension
+	}
+	return string(format)
+}
+
+type jsonEncoder struct {
+	w       *bufio.Writer
+	encoder *json.Encoder
+	count   int
+}
+
+func (e *jsonEncoder) Encode(word *model.Word) error {
+	separator := ","
+	if e.count == 0 {
+		separator = "["
+	}
+	e.count++
+
+	if _, err := e.w.WriteString(separator); err != nil {
+		return err
+	}
+	return e.encoder.Encode(word)
+}
+
+func (e *jsonEncoder) Close() error {
+	end := "]\n"
+	if e.count == 0 {
+		end = "[]\n"
+	}
+	if _, err := e.w.WriteString(end); err != nil {
+		return err
+	}
+	return e.w.Flush()
+}
+
+type ndjsonEncoder struct {
+	w       *bufio.Writer
+	encoder *json.Encoder
+}
+
+func (e *ndjsonEncoder) Encode(word *model.Word) error {
+	return e.encoder.Encode(word)
+}
+
+func (e *ndjsonEncoder) Close() error {
+	return e.w.Flush()
+}
+
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvEncoder) Encode(word *model.Word) error {
+	for _, t := range word.Translations {
+		if len(t.Sentences) == 0 {
+			if err := e.w.Write([]string{word.Polish, t.English, ""}); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, s := range t.Sentences {
+			if err := e.w.Write([]string{word.Polish, t.English, s.Sentence}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *csvEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
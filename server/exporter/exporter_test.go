@@ -0,0 +1,185 @@
+package exporter
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/staszkiet/DictionaryGolang/server/database"
+	customerrors "github.com/staszkiet/DictionaryGolang/server/errors"
+	"github.com/staszkiet/DictionaryGolang/server/graph/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var words = []*model.Word{
+	{Polish: "kot", Translations: []*model.Translation{{English: "cat", Sentences: []*model.Sentence{}}}},
+	{Polish: "rower", Translations: []*model.Translation{{English: "bike", Sentences: []*model.Sentence{{Sentence: "I like my bike, really"}, {Sentence: "My bike is green"}}}}},
+}
+
+type fakeSource struct {
+	words []*model.Word
+	err   error
+	//receives the filter of the export, if set
+	filter *database.ExportFilter
+}
+
+func (s fakeSource) ExportWords(filter database.ExportFilter, fn func(word *model.Word) error) error {
+	if s.filter != nil {
+		*s.filter = filter
+	}
+	for _, w := range s.words {
+		if !strings.HasPrefix(w.Polish, filter.Prefix) {
+			continue
+		}
+		if err := fn(w); err != nil {
+			return err
+		}
+	}
+	return s.err
+}
+
+func encode(t *testing.T, format Format, words []*model.Word) string {
+	return encodePair(t, format, DefaultPair, words)
+}
+
+func encodePair(t *testing.T, format Format, pair model.LanguagePair, words []*model.Word) string {
+	var out bytes.Buffer
+	encoder, err := NewEncoder(format, pair, &out)
+	assert.NoError(t, err)
+	for _, w := range words {
+		assert.NoError(t, encoder.Encode(w))
+	}
+	assert.NoError(t, encoder.Close())
+	return out.String()
+}
+
+func TestEncoder_JSON_ShouldWriteArrayOfWords(t *testing.T) {
+	assert.JSONEq(t, `[
+		{"polish": "kot", "translations": [{"english": "cat", "sentences": []}]},
+		{"polish": "rower", "translations": [{"english": "bike", "sentences": [{"sentence": "I like my bike, really"}, {"sentence": "My bike is green"}]}]}
+	]`, encode(t, FormatJSON, words))
+	assert.JSONEq(t, `[]`, encode(t, FormatJSON, nil))
+}
+
+func TestEncoder_NDJSON_ShouldWriteWordPerLine(t *testing.T) {
+	assert.Equal(t,
+		`{"polish":"kot","translations":[{"english":"cat","sentences":[]}]}`+"\n"+
+			`{"polish":"rower","translations":[{"english":"bike","sentences":[{"sentence":"I like my bike, really"},{"sentence":"My bike is green"}]}]}`+"\n",
+		encode(t, FormatNDJSON, words))
+}
+
+func TestEncoder_CSV_ShouldWriteRowPerSentence(t *testing.T) {
+	assert.Equal(t,
+		"polish,english,sentence\n"+
+			"kot,cat,\n"+
+			"rower,bike,\"I like my bike, really\"\n"+
+			"rower,bike,My bike is green\n",
+		encode(t, FormatCSV, words))
+}
+
+func TestEncoder_CSVOfOtherPair_ShouldNameColumnsAfterLanguages(t *testing.T) {
+	word := &model.Word{Polish: "Haus", Translations: []*model.Translation{{English: "house", Sentences: []*model.Sentence{}}}}
+
+	assert.Equal(t,
+		"german,english,sentence\n"+
+			"Haus,house,\n",
+		encodePair(t, FormatCSV, model.LanguagePair{Source: model.LanguageDe, Target: model.LanguageEn}, []*model.Word{word}))
+}
+
+func TestNewEncoder_InvalidFormat_ShouldReturnError(t *testing.T) {
+	_, err := NewEncoder("xml", DefaultPair, &bytes.Buffer{})
+
+	assert.Equal(t, customerrors.InvalidExportFormatError{Format: "xml"}, err)
+}
+
+func TestHandler_ShouldStreamDownloadWithStatusTrailer(t *testing.T) {
+	res := httptest.NewRecorder()
+
+	Handler(fakeSource{words: words}).ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/export?format=ndjson", nil))
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "application/x-ndjson", res.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="dictionary.ndjson"`, res.Header().Get("Content-Disposition"))
+	assert.Equal(t, "ok", res.Result().Trailer.Get(StatusTrailer))
+}
+
+func TestHandler_EveryFormat_ShouldSendHeadersBeforeFileAndStatusTrailer(t *testing.T) {
+	for _, format := range []Format{FormatJSON, FormatNDJSON, FormatCSV, FormatAnki, FormatApkg} {
+		t.Run(string(format), func(t *testing.T) {
+			res := httptest.NewRecorder()
+
+			Handler(fakeSource{words: words}).ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/export?format="+string(format), nil))
+
+			assert.Equal(t, http.StatusOK, res.Code)
+			assert.Equal(t, ContentType(format), res.Header().Get("Content-Type"))
+			assert.Equal(t, `attachment; filename="dictionary.`+Extension(format)+`"`, res.Header().Get("Content-Disposition"))
+			assert.Equal(t, StatusTrailer, res.Header().Get("Trailer"))
+			assert.Equal(t, "ok", res.Result().Trailer.Get(StatusTrailer))
+			assert.NotEmpty(t, res.Body.String())
+		})
+	}
+}
+
+func TestHandler_WithPrefix_ShouldExportMatchingWords(t *testing.T) {
+	res := httptest.NewRecorder()
+
+	Handler(fakeSource{words: words}).ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/export?format=csv&prefix=ro", nil))
+
+	assert.NotContains(t, res.Body.String(), "kot")
+	assert.Contains(t, res.Body.String(), "rower")
+}
+
+func TestHandler_WithTag_ShouldPassTagToSource(t *testing.T) {
+	res := httptest.NewRecorder()
+	filter := database.ExportFilter{}
+
+	Handler(fakeSource{words: words, filter: &filter}).ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/export?format=anki&prefix=ro&tag=travel", nil))
+
+	assert.Equal(t, database.ExportFilter{Pair: &DefaultPair, Prefix: "ro", Tag: "travel"}, filter)
+	assert.Equal(t, "ok", res.Result().Trailer.Get(StatusTrailer))
+}
+
+func TestHandler_WithPair_ShouldExportWordsOfThatPair(t *testing.T) {
+	res := httptest.NewRecorder()
+	filter := database.ExportFilter{}
+
+	Handler(fakeSource{words: words, filter: &filter}).ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/export?format=csv&source=de&target=en", nil))
+
+	assert.Equal(t, &model.LanguagePair{Source: model.LanguageDe, Target: model.LanguageEn}, filter.Pair)
+	assert.True(t, strings.HasPrefix(res.Body.String(), "german,english,sentence\n"))
+	assert.Equal(t, "ok", res.Result().Trailer.Get(StatusTrailer))
+}
+
+func TestHandler_InvalidPair_ShouldReturnBadRequest(t *testing.T) {
+	for _, query := range []string{"source=de", "source=de&target=de", "source=fr&target=en"} {
+		res := httptest.NewRecorder()
+
+		Handler(fakeSource{words: words}).ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/export?"+query, nil))
+
+		assert.Equal(t, http.StatusBadRequest, res.Code, query)
+		assert.Empty(t, res.Header().Get("Content-Disposition"), query)
+	}
+}
+
+func TestHandler_FailedExport_ShouldSetErrorTrailer(t *testing.T) {
+	res := httptest.NewRecorder()
+
+	Handler(fakeSource{words: words, err: errors.New("connection lost")}).ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/export", nil))
+
+	assert.Equal(t, "error", res.Result().Trailer.Get(StatusTrailer))
+}
+
+func TestHandler_InvalidFormat_ShouldReturnBadRequest(t *testing.T) {
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/export?format=xml", nil)
+	req = req.WithContext(customerrors.WithLanguage(req.Context(), customerrors.English))
+
+	Handler(fakeSource{}).ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Empty(t, res.Header().Get("Content-Disposition"))
+	assert.Contains(t, res.Body.String(), "export format xml is invalid")
+}
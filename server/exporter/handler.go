@@ -0,0 +1,94 @@
+package exporter
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/staszkiet/DictionaryGolang/server/database"
+	customerrors "github.com/staszkiet/DictionaryGolang/server/errors"
+	"github.com/staszkiet/DictionaryGolang/server/graph/model"
+)
+
+// Trailer telling the client whether the whole dictionary was sent. The status code is sent before
+// the first word, so an export which fails in the middle can only be detected this way
+const StatusTrailer = "X-Export-Status"
+
+type Source interface {
+	// Calls fn with every word of the dictionary matching the filter
+	ExportWords(filter database.ExportFilter, fn func(word *model.Word) error) error
+}
+
+// Encoders which keep the export in temporary files remove them when the export fails
+type discarder interface {
+	Discard()
+}
+
+// Streams the dictionary as a file download in the format given by the "format" query parameter (json by default).
+// The "prefix" parameter limits the export to words starting with it, the "tag" parameter to words with that tag.
+// The "source" and "target" parameters choose the language pair (polish-english by default)
+func Handler(source Source) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		format := Format(r.URL.Query().Get("format"))
+		if format == "" {
+			format = FormatJSON
+		}
+
+		pair, err := exportPair(r.URL.Query())
+		if err != nil {
+			http.Error(w, customerrors.Localize(err, customerrors.LanguageFromContext(r.Context())), http.StatusBadRequest)
+			return
+		}
+
+		//headers are set first, because encoders may write the beginning of the file right away
+		w.Header().Set("Content-Type", ContentType(format))
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="dictionary.%s"`, Extension(format)))
+		w.Header().Set("Trailer", StatusTrailer)
+
+		encoder, err := NewEncoder(format, pair, w)
+		if err != nil {
+			w.Header().Del("Content-Disposition")
+			w.Header().Del("Trailer")
+			http.Error(w, customerrors.Localize(err, customerrors.LanguageFromContext(r.Context())), http.StatusBadRequest)
+			return
+		}
+
+		filter := database.ExportFilter{Pair: &pair, Prefix: r.URL.Query().Get("prefix"), Tag: r.URL.Query().Get("tag")}
+		if err := source.ExportWords(filter, encoder.Encode); err != nil {
+			log.Printf("export failed: %v", err)
+			if d, ok := encoder.(discarder); ok {
+				d.Discard()
+			}
+			w.Header().Set(StatusTrailer, "error")
+			return
+		}
+		if err := encoder.Close(); err != nil {
+			log.Printf("export failed: %v", err)
+			w.Header().Set(StatusTrailer, "error")
+			return
+		}
+		w.Header().Set(StatusTrailer, "ok")
+	})
+}
+
+// Both languages of the pair have to be given, or none of them
+func exportPair(query url.Values) (model.LanguagePair, error) {
+	source, target := query.Get("source"), query.Get("target")
+	if source == "" && target == "" {
+		return DefaultPair, nil
+	}
+
+	pair := model.LanguagePair{Source: model.Language(strings.ToUpper(source)), Target: model.Language(strings.ToUpper(target))}
+	if !pair.Source.IsValid() || !pair.Target.IsValid() || pair.Source == pair.Target {
+		return pair, customerrors.InvalidLanguagePairError{Source: source, Target: target}
+	}
+	return pair, nil
+}
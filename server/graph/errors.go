@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql"
+	customerrors "github.com/staszkiet/DictionaryGolang/server/errors"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Presents resolver errors to the client. Dictionary errors get a stable code and their fields
+// as GraphQL error extensions, and their message in the language of the request. Unknown errors
+// (e.g. from the database) are logged and masked behind an error ID, so their details don't reach the client
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	language := customerrors.LanguageFromContext(ctx)
+
+	var extended customerrors.ExtendedError
+	if errors.As(err, &extended) {
+		gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+		gqlErr.Message = customerrors.Localize(err, language)
+		gqlErr.Extensions = extended.Extensions()
+		return gqlErr
+	}
+
+	//errors created by gqlgen itself, e.g. invalid arguments, are meant for the client. gqlgen wraps resolver
+	//errors in a gqlerror.Error too, but those keep the original error in Err
+	var gqlErr *gqlerror.Error
+	if errors.As(err, &gqlErr) && gqlErr.Err == nil {
+		return graphql.DefaultErrorPresenter(ctx, err)
+	}
+
+	id := newErrorID()
+	log.Printf("internal error %s: %v", id, err)
+
+	masked := &internalError{id: id}
+	presented := graphql.DefaultErrorPresenter(ctx, masked)
+	presented.Message = customerrors.Localize(masked, language)
+	presented.Extensions = masked.Extensions()
+	if gqlErr != nil {
+		presented.Path = gqlErr.Path
+	}
+	return presented
+}
+
+type internalError struct {
+	id string
+}
+
+func (e *internalError) Error() string {
+	return customerrors.Message(customerrors.CodeInternal, customerrors.DefaultLanguage, e.Extensions())
+}
+
+func (e *internalError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": customerrors.CodeInternal, "errorId": e.id}
+}
+
+// Stores the language negotiated from the Accept-Language header in the request context,
+// so error messages can be presented in it
+func AcceptLanguage(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		language := customerrors.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+		w.Header().Set("Content-Language", string(language))
+		next.ServeHTTP(w, r.WithContext(customerrors.WithLanguage(r.Context(), language)))
+	})
+}
+
+func newErrorID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,64 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	customerrors "github.com/staszkiet/DictionaryGolang/server/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestErrorPresenter_DictionaryError_ShouldHaveCodeAndFields(t *testing.T) {
+	err := customerrors.TranslationNotExistsError{Word: "rower", Translation: "car"}
+
+	gqlErr := ErrorPresenter(context.Background(), graphql.ErrorOnPath(context.Background(), err))
+
+	assert.Equal(t, err.Error(), gqlErr.Message)
+	assert.Equal(t, customerrors.CodeTranslationNotFound, gqlErr.Extensions["code"])
+	assert.Equal(t, "rower", gqlErr.Extensions["word"])
+	assert.Equal(t, "car", gqlErr.Extensions["translation"])
+}
+
+func TestErrorPresenter_WordWithSuggestions_ShouldHaveWordNotFoundCode(t *testing.T) {
+	err := customerrors.WordSuggestionsError{
+		WordNotExistsError: customerrors.WordNotExistsError{Word: "rowr"},
+		Suggestions:        []string{"rower"},
+	}
+
+	gqlErr := ErrorPresenter(context.Background(), err)
+
+	assert.Equal(t, customerrors.CodeWordNotFound, gqlErr.Extensions["code"])
+	assert.Equal(t, []string{"rower"}, gqlErr.Extensions["suggestions"])
+}
+
+func TestErrorPresenter_UnknownError_ShouldBeMasked(t *testing.T) {
+	//gqlgen wraps every resolver error like this before presenting it
+	err := graphql.ErrorOnPath(context.Background(), errors.New(`pq: duplicate key value violates unique constraint "words_polish_key"`))
+
+	gqlErr := ErrorPresenter(context.Background(), err)
+
+	assert.NotContains(t, gqlErr.Message, "duplicate key")
+	assert.Equal(t, customerrors.CodeInternal, gqlErr.Extensions["code"])
+	assert.Contains(t, gqlErr.Message, gqlErr.Extensions["errorId"])
+}
+
+func TestErrorPresenter_GraphQLError_ShouldBePassedThrough(t *testing.T) {
+	err := graphql.ErrorOnPath(context.Background(), gqlerror.Errorf("FOO is not a valid SortOrder"))
+
+	gqlErr := ErrorPresenter(context.Background(), err)
+
+	assert.Equal(t, "FOO is not a valid SortOrder", gqlErr.Message)
+	assert.Nil(t, gqlErr.Extensions["code"])
+}
+
+func TestErrorPresenter_EnglishRequest_ShouldHaveEnglishMessage(t *testing.T) {
+	ctx := customerrors.WithLanguage(context.Background(), customerrors.English)
+
+	gqlErr := ErrorPresenter(ctx, customerrors.WordNotExistsError{Word: "rowr"})
+
+	assert.Equal(t, "word rowr is not in the dictionary", gqlErr.Message)
+	assert.Equal(t, customerrors.CodeWordNotFound, gqlErr.Extensions["code"])
+}
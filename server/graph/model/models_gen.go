@@ -2,27 +2,1106 @@
 
 package model
 
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type SearchResult interface {
+	IsSearchResult()
+}
+
+// Translation studied as a flashcard, with its spaced repetition state
+type Card struct {
+	TranslationID string `json:"translationId"`
+	// Headword the translation belongs to
+	Text        string       `json:"text"`
+	Polish      string       `json:"polish"`
+	Translation *Translation `json:"translation"`
+	// SM-2 ease factor, at least 1.3
+	EaseFactor float64 `json:"easeFactor"`
+	// Days between the last review and the next one
+	Interval int32 `json:"interval"`
+	// Successful reviews in a row
+	Repetitions int32 `json:"repetitions"`
+	// Times the card was forgotten after it was recalled
+	Lapses int32     `json:"lapses"`
+	Due    time.Time `json:"due"`
+}
+
+// Ordered list of translations put together by the user
+type Collection struct {
+	Name  string            `json:"name"`
+	Items []*CollectionItem `json:"items"`
+}
+
+type CollectionItem struct {
+	// Place of the item in the collection, starting from 1
+	Position int32 `json:"position"`
+	// Word the translation belongs to
+	Text                string   `json:"text"`
+	Language            Language `json:"language"`
+	Translation         string   `json:"translation"`
+	TranslationLanguage Language `json:"translationLanguage"`
+}
+
+type FileImportOptions struct {
+	// Column separator, a single character. Comma by default
+	Delimiter *string `json:"delimiter,omitempty"`
+	// Whether the first row names the columns. Without it the columns are polish, english and example sentences
+	Header        *bool   `json:"header,omitempty"`
+	PolishColumn  *string `json:"polishColumn,omitempty"`
+	EnglishColumn *string `json:"englishColumn,omitempty"`
+	// Columns with example sentences, by default every column which name starts with sentence
+	SentenceColumns []string          `json:"sentenceColumns,omitempty"`
+	Conflict        *ConflictStrategy `json:"conflict,omitempty"`
+}
+
+// Limits words to those with all given values
+type GrammarFilter struct {
+	PartOfSpeech *PartOfSpeech `json:"partOfSpeech,omitempty"`
+	Gender       *Gender       `json:"gender,omitempty"`
+	Aspect       *Aspect       `json:"aspect,omitempty"`
+	// Words with at least one translation of that countability. In search translations and sentences are matched by their own translation
+	Countability *Countability `json:"countability,omitempty"`
+	// Words with at least one translation with the label, matched like countability
+	Label *UsageLabel `json:"label,omitempty"`
+	// Words with at least one translation of the domain, matched like countability
+	Domain *string `json:"domain,omitempty"`
+}
+
+// Grammatical metadata of a word. Values which aren't given are cleared
+type GrammarInput struct {
+	PartOfSpeech PartOfSpeech `json:"partOfSpeech"`
+	// Only for nouns
+	Gender *Gender `json:"gender,omitempty"`
+	// Only for verbs
+	Aspect *Aspect `json:"aspect,omitempty"`
+	// Polish verb of the opposite aspect. The link is set on both words
+	AspectPartner *string `json:"aspectPartner,omitempty"`
+}
+
+type ImportEntryResult struct {
+	// Position of the entry in the imported list
+	Index   int32        `json:"index"`
+	Polish  string       `json:"polish"`
+	English string       `json:"english"`
+	Status  ImportStatus `json:"status"`
+	// Code of the error when the entry failed
+	ErrorCode *string `json:"errorCode,omitempty"`
+}
+
+type ImportReport struct {
+	Created     int32              `json:"created"`
+	Merged      int32              `json:"merged"`
+	Overwritten int32              `json:"overwritten"`
+	Skipped     int32              `json:"skipped"`
+	Rejected    int32              `json:"rejected"`
+	Rows        []*ImportRowResult `json:"rows"`
+}
+
+type ImportRowResult struct {
+	Line    int32        `json:"line"`
+	Polish  string       `json:"polish"`
+	English string       `json:"english"`
+	Status  ImportStatus `json:"status"`
+	// Code of the error when the row was rejected
+	ErrorCode *string `json:"errorCode,omitempty"`
+}
+
+// Inflected form of a word, e.g. rowerem with tags [instrumental, singular] for rower
+type Inflection struct {
+	Form string   `json:"form"`
+	Tags []string `json:"tags"`
+}
+
+type InflectionImportResult struct {
+	// Position of the entry in the imported list
+	Index int32  `json:"index"`
+	Lemma string `json:"lemma"`
+	Form  string `json:"form"`
+	// CREATED for added forms, SKIPPED for forms which already exist
+	Status ImportStatus `json:"status"`
+	// Code of the error when the entry failed
+	ErrorCode *string `json:"errorCode,omitempty"`
+}
+
+// Languages words are translated from and into. When it isn't given, polish words are translated into english
+type LanguagePair struct {
+	Source Language `json:"source"`
+	Target Language `json:"target"`
+}
+
 type Mutation struct {
 }
 
+type MutationResult struct {
+	Outcome MutationOutcome `json:"outcome"`
+	Word    *Word           `json:"word,omitempty"`
+}
+
+type NewInflection struct {
+	// Polish word the form belongs to
+	Lemma string   `json:"lemma"`
+	Form  string   `json:"form"`
+	Tags  []string `json:"tags"`
+}
+
+// Labels, domain and note are set when the translation is created, setUsage changes them later
 type NewTranslation struct {
 	English   string   `json:"english"`
 	Sentences []string `json:"sentences"`
+	// Sentences with an original or attribution, added together with sentences
+	SentencePairs []*SentenceInput `json:"sentencePairs,omitempty"`
+	Labels        []UsageLabel     `json:"labels,omitempty"`
+	Domain        *string          `json:"domain,omitempty"`
+	Note          *string          `json:"note,omitempty"`
+}
+
+type NewWordEntry struct {
+	Polish      string          `json:"polish"`
+	Translation *NewTranslation `json:"translation"`
+}
+
+type PageInfo struct {
+	EndCursor   *string `json:"endCursor,omitempty"`
+	HasNextPage bool    `json:"hasNextPage"`
+}
+
+// How a word or a translation is pronounced. Every field is optional
+type Pronunciation struct {
+	// IPA transcription without enclosing slashes or brackets, e.g. ˈrɔ.vɛr
+	Ipa *string `json:"ipa,omitempty"`
+	// Stressed syllable counted from 1 at the start of the word
+	Stress *int32 `json:"stress,omitempty"`
+	// Path of the audio clip on the server, e.g. /audio/3f2a.mp3. The clip can be fetched in parts with range requests
+	AudioURL *string `json:"audioUrl,omitempty"`
+}
+
+// Null fields clear the transcription or the stress
+type PronunciationInput struct {
+	Ipa    *string `json:"ipa,omitempty"`
+	Stress *int32  `json:"stress,omitempty"`
 }
 
 type Query struct {
 }
 
+type QuizAnswer struct {
+	QuestionID string `json:"questionId"`
+	Answer     string `json:"answer"`
+}
+
+type QuizAnswerResult struct {
+	QuestionID      string   `json:"questionId"`
+	Answer          string   `json:"answer"`
+	Correct         bool     `json:"correct"`
+	AcceptedAnswers []string `json:"acceptedAnswers"`
+}
+
+type QuizQuestion struct {
+	// Identifies what was asked, sent back with the answer
+	ID     string       `json:"id"`
+	Kind   QuestionKind `json:"kind"`
+	Prompt string       `json:"prompt"`
+	// Options of multiple choice questions, empty for other kinds
+	Choices []string `json:"choices"`
+}
+
+type QuizResult struct {
+	Score   int32               `json:"score"`
+	Total   int32               `json:"total"`
+	Answers []*QuizAnswerResult `json:"answers"`
+}
+
+// Other end of a relation of a word or a translation
+type Relation struct {
+	Kind RelationKind `json:"kind"`
+	Word string       `json:"word"`
+	// Null for relations between words
+	Translation *string `json:"translation,omitempty"`
+	// True when the relation was created from the other end, e.g. rowerzysta DERIVED_FROM rower is an incoming relation of rower
+	Incoming bool `json:"incoming"`
+}
+
+// End of a relation, a word or one of its translations
+type RelationEnd struct {
+	Text        string  `json:"text"`
+	Translation *string `json:"translation,omitempty"`
+}
+
+// Example sentence in the language of translations. A sentence pair also has its original in the language of the word
 type Sentence struct {
 	Sentence string `json:"sentence"`
+	// The same sentence in the language of the word, e.g. Polish
+	Original *string `json:"original,omitempty"`
+	// Where the sentence comes from, e.g. a book or a corpus
+	Attribution *string `json:"attribution,omitempty"`
+	License     *string `json:"license,omitempty"`
+}
+
+type SentenceHit struct {
+	Polish   string  `json:"polish"`
+	English  string  `json:"english"`
+	Sentence string  `json:"sentence"`
+	Rank     float64 `json:"rank"`
+	Snippet  string  `json:"snippet"`
+}
+
+func (SentenceHit) IsSearchResult() {}
+
+// Only sentence is required, so a single string still makes an example sentence
+type SentenceInput struct {
+	Sentence    string  `json:"sentence"`
+	Original    *string `json:"original,omitempty"`
+	Attribution *string `json:"attribution,omitempty"`
+	License     *string `json:"license,omitempty"`
+}
+
+type Subscription struct {
+}
+
+// Topic label of words, e.g. kitchen or lesson 5
+type Tag struct {
+	Name string `json:"name"`
+	// Number of words tagged with it, in every language pair
+	Words int32 `json:"words"`
 }
 
 type Translation struct {
-	English   string      `json:"english"`
+	Text     string   `json:"-"`
+	Language Language `json:"-"`
+	English  string   `json:"english"`
+	// Whether the english noun can be counted
+	Countability *Countability `json:"countability,omitempty"`
+	// In the order set by reorderSentences
 	Sentences []*Sentence `json:"sentences"`
+	// Translations linked with this one by addRelation
+	Related []*Relation `json:"related,omitempty"`
+	// Null until a transcription, stress or audio clip is set
+	Pronunciation *Pronunciation `json:"pronunciation,omitempty"`
+	// Register and usage labels, e.g. COLLOQUIAL for bloke
+	Labels []UsageLabel `json:"labels,omitempty"`
+	// Field the translation belongs to, e.g. medical or legal
+	Domain *string `json:"domain,omitempty"`
+	// Remark on when the translation is used
+	Note *string `json:"note,omitempty"`
+}
+
+type TranslationHit struct {
+	Polish  string  `json:"polish"`
+	English string  `json:"english"`
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+func (TranslationHit) IsSearchResult() {}
+
+// Labels, domain and note are set when the translation is created, setUsage changes them later
+type TranslationInput struct {
+	Text      string   `json:"text"`
+	Sentences []string `json:"sentences"`
+	// Sentences with an original or attribution, added together with sentences
+	SentencePairs []*SentenceInput `json:"sentencePairs,omitempty"`
+	Labels        []UsageLabel     `json:"labels,omitempty"`
+	Domain        *string          `json:"domain,omitempty"`
+	Note          *string          `json:"note,omitempty"`
+}
+
+// Replaces all usage information of a translation, null fields clear it
+type UsageInput struct {
+	Labels []UsageLabel `json:"labels"`
+	// Stored lowercase
+	Domain *string `json:"domain,omitempty"`
+	Note   *string `json:"note,omitempty"`
 }
 
+// Headword of a language pair with its translations into the target language
 type Word struct {
-	Polish       string         `json:"polish"`
+	Text         string        `json:"-"`
+	Language     Language      `json:"-"`
+	Polish       string        `json:"polish"`
+	PartOfSpeech *PartOfSpeech `json:"partOfSpeech,omitempty"`
+	// Only nouns have a gender
+	Gender *Gender `json:"gender,omitempty"`
+	// Only verbs have an aspect
+	Aspect *Aspect `json:"aspect,omitempty"`
+	// Verb of the opposite aspect with the same meaning, e.g. zrobić for robić
+	AspectPartner *string `json:"aspectPartner,omitempty"`
+	// In the order set by reorderTranslations, the first one is the primary sense
 	Translations []*Translation `json:"translations"`
+	Inflections  []*Inflection  `json:"inflections,omitempty"`
+	// Inflected forms selectWord was given instead of the word itself, empty when the word was matched directly
+	MatchedForms []*Inflection `json:"matchedForms,omitempty"`
+	// Words linked with this one by addRelation
+	Related []*Relation `json:"related,omitempty"`
+	// Topics the word is tagged with, in alphabetical order
+	Tags []string `json:"tags,omitempty"`
+	// Null until a transcription, stress or audio clip is set
+	Pronunciation *Pronunciation `json:"pronunciation,omitempty"`
+}
+
+type WordChangedEvent struct {
+	Kind ChangeKind `json:"kind"`
+	// Language pair of the dictionary the word belongs to
+	Source         Language `json:"source"`
+	Target         Language `json:"target"`
+	Polish         string   `json:"polish"`
+	PreviousPolish *string  `json:"previousPolish,omitempty"`
+	Word           *Word    `json:"word,omitempty"`
+}
+
+type WordConnection struct {
+	Edges    []*WordEdge `json:"edges"`
+	PageInfo *PageInfo   `json:"pageInfo"`
+}
+
+type WordEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *Word  `json:"node"`
+}
+
+type WordHit struct {
+	Polish  string  `json:"polish"`
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+func (WordHit) IsSearchResult() {}
+
+type Aspect string
+
+const (
+	AspectImperfective Aspect = "IMPERFECTIVE"
+	AspectPerfective   Aspect = "PERFECTIVE"
+)
+
+var AllAspect = []Aspect{
+	AspectImperfective,
+	AspectPerfective,
+}
+
+func (e Aspect) IsValid() bool {
+	switch e {
+	case AspectImperfective, AspectPerfective:
+		return true
+	}
+	return false
+}
+
+func (e Aspect) String() string {
+	return string(e)
+}
+
+func (e *Aspect) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Aspect(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Aspect", str)
+	}
+	return nil
+}
+
+func (e Aspect) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ChangeKind string
+
+const (
+	ChangeKindCreated ChangeKind = "CREATED"
+	ChangeKindUpdated ChangeKind = "UPDATED"
+	ChangeKindDeleted ChangeKind = "DELETED"
+)
+
+var AllChangeKind = []ChangeKind{
+	ChangeKindCreated,
+	ChangeKindUpdated,
+	ChangeKindDeleted,
+}
+
+func (e ChangeKind) IsValid() bool {
+	switch e {
+	case ChangeKindCreated, ChangeKindUpdated, ChangeKindDeleted:
+		return true
+	}
+	return false
+}
+
+func (e ChangeKind) String() string {
+	return string(e)
+}
+
+func (e *ChangeKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ChangeKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ChangeKind", str)
+	}
+	return nil
+}
+
+func (e ChangeKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// What happens to words from an imported file which already exist in the dictionary
+type ConflictStrategy string
+
+const (
+	// Rows of existing words are skipped
+	ConflictStrategySkip ConflictStrategy = "SKIP"
+	// Rows are merged into existing words
+	ConflictStrategyMerge ConflictStrategy = "MERGE"
+	// Existing words are replaced with the translations from the file
+	ConflictStrategyOverwrite ConflictStrategy = "OVERWRITE"
+)
+
+var AllConflictStrategy = []ConflictStrategy{
+	ConflictStrategySkip,
+	ConflictStrategyMerge,
+	ConflictStrategyOverwrite,
+}
+
+func (e ConflictStrategy) IsValid() bool {
+	switch e {
+	case ConflictStrategySkip, ConflictStrategyMerge, ConflictStrategyOverwrite:
+		return true
+	}
+	return false
+}
+
+func (e ConflictStrategy) String() string {
+	return string(e)
+}
+
+func (e *ConflictStrategy) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ConflictStrategy(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ConflictStrategy", str)
+	}
+	return nil
+}
+
+func (e ConflictStrategy) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type Countability string
+
+const (
+	CountabilityCountable   Countability = "COUNTABLE"
+	CountabilityUncountable Countability = "UNCOUNTABLE"
+	// Countable in some meanings, e.g. a coffee and coffee
+	CountabilityBoth Countability = "BOTH"
+)
+
+var AllCountability = []Countability{
+	CountabilityCountable,
+	CountabilityUncountable,
+	CountabilityBoth,
+}
+
+func (e Countability) IsValid() bool {
+	switch e {
+	case CountabilityCountable, CountabilityUncountable, CountabilityBoth:
+		return true
+	}
+	return false
+}
+
+func (e Countability) String() string {
+	return string(e)
+}
+
+func (e *Countability) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Countability(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Countability", str)
+	}
+	return nil
+}
+
+func (e Countability) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// Gender of polish nouns. Masculine nouns are personal (student), animate (pies) or inanimate (stół)
+type Gender string
+
+const (
+	GenderMasculinePersonal  Gender = "MASCULINE_PERSONAL"
+	GenderMasculineAnimate   Gender = "MASCULINE_ANIMATE"
+	GenderMasculineInanimate Gender = "MASCULINE_INANIMATE"
+	GenderFeminine           Gender = "FEMININE"
+	GenderNeuter             Gender = "NEUTER"
+)
+
+var AllGender = []Gender{
+	GenderMasculinePersonal,
+	GenderMasculineAnimate,
+	GenderMasculineInanimate,
+	GenderFeminine,
+	GenderNeuter,
+}
+
+func (e Gender) IsValid() bool {
+	switch e {
+	case GenderMasculinePersonal, GenderMasculineAnimate, GenderMasculineInanimate, GenderFeminine, GenderNeuter:
+		return true
+	}
+	return false
+}
+
+func (e Gender) String() string {
+	return string(e)
+}
+
+func (e *Gender) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Gender(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Gender", str)
+	}
+	return nil
+}
+
+func (e Gender) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ImportMode string
+
+const (
+	// Nothing is imported if any entry fails
+	ImportModeAtomic ImportMode = "ATOMIC"
+	// Failed entries, invalid or rejected by the database, are reported and the rest is imported
+	ImportModeBestEffort ImportMode = "BEST_EFFORT"
+)
+
+var AllImportMode = []ImportMode{
+	ImportModeAtomic,
+	ImportModeBestEffort,
+}
+
+func (e ImportMode) IsValid() bool {
+	switch e {
+	case ImportModeAtomic, ImportModeBestEffort:
+		return true
+	}
+	return false
+}
+
+func (e ImportMode) String() string {
+	return string(e)
+}
+
+func (e *ImportMode) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImportMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImportMode", str)
+	}
+	return nil
+}
+
+func (e ImportMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ImportStatus string
+
+const (
+	ImportStatusCreated     ImportStatus = "CREATED"
+	ImportStatusMerged      ImportStatus = "MERGED"
+	ImportStatusOverwritten ImportStatus = "OVERWRITTEN"
+	ImportStatusSkipped     ImportStatus = "SKIPPED"
+	ImportStatusFailed      ImportStatus = "FAILED"
+)
+
+var AllImportStatus = []ImportStatus{
+	ImportStatusCreated,
+	ImportStatusMerged,
+	ImportStatusOverwritten,
+	ImportStatusSkipped,
+	ImportStatusFailed,
+}
+
+func (e ImportStatus) IsValid() bool {
+	switch e {
+	case ImportStatusCreated, ImportStatusMerged, ImportStatusOverwritten, ImportStatusSkipped, ImportStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e ImportStatus) String() string {
+	return string(e)
+}
+
+func (e *ImportStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImportStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImportStatus", str)
+	}
+	return nil
+}
+
+func (e ImportStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type Language string
+
+const (
+	LanguagePl Language = "PL"
+	LanguageEn Language = "EN"
+	LanguageDe Language = "DE"
+	LanguageEs Language = "ES"
+)
+
+var AllLanguage = []Language{
+	LanguagePl,
+	LanguageEn,
+	LanguageDe,
+	LanguageEs,
+}
+
+func (e Language) IsValid() bool {
+	switch e {
+	case LanguagePl, LanguageEn, LanguageDe, LanguageEs:
+		return true
+	}
+	return false
+}
+
+func (e Language) String() string {
+	return string(e)
+}
+
+func (e *Language) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Language(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Language", str)
+	}
+	return nil
+}
+
+func (e Language) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type MutationOutcome string
+
+const (
+	MutationOutcomeCreated        MutationOutcome = "CREATED"
+	MutationOutcomeMerged         MutationOutcome = "MERGED"
+	MutationOutcomeUpdated        MutationOutcome = "UPDATED"
+	MutationOutcomeDeleted        MutationOutcome = "DELETED"
+	MutationOutcomeCascadeDeleted MutationOutcome = "CASCADE_DELETED"
+	MutationOutcomeNoop           MutationOutcome = "NOOP"
+)
+
+var AllMutationOutcome = []MutationOutcome{
+	MutationOutcomeCreated,
+	MutationOutcomeMerged,
+	MutationOutcomeUpdated,
+	MutationOutcomeDeleted,
+	MutationOutcomeCascadeDeleted,
+	MutationOutcomeNoop,
+}
+
+func (e MutationOutcome) IsValid() bool {
+	switch e {
+	case MutationOutcomeCreated, MutationOutcomeMerged, MutationOutcomeUpdated, MutationOutcomeDeleted, MutationOutcomeCascadeDeleted, MutationOutcomeNoop:
+		return true
+	}
+	return false
+}
+
+func (e MutationOutcome) String() string {
+	return string(e)
+}
+
+func (e *MutationOutcome) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MutationOutcome(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MutationOutcome", str)
+	}
+	return nil
+}
+
+func (e MutationOutcome) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type PartOfSpeech string
+
+const (
+	PartOfSpeechNoun         PartOfSpeech = "NOUN"
+	PartOfSpeechVerb         PartOfSpeech = "VERB"
+	PartOfSpeechAdjective    PartOfSpeech = "ADJECTIVE"
+	PartOfSpeechAdverb       PartOfSpeech = "ADVERB"
+	PartOfSpeechPronoun      PartOfSpeech = "PRONOUN"
+	PartOfSpeechPreposition  PartOfSpeech = "PREPOSITION"
+	PartOfSpeechConjunction  PartOfSpeech = "CONJUNCTION"
+	PartOfSpeechNumeral      PartOfSpeech = "NUMERAL"
+	PartOfSpeechParticle     PartOfSpeech = "PARTICLE"
+	PartOfSpeechInterjection PartOfSpeech = "INTERJECTION"
+	PartOfSpeechPhrase       PartOfSpeech = "PHRASE"
+)
+
+var AllPartOfSpeech = []PartOfSpeech{
+	PartOfSpeechNoun,
+	PartOfSpeechVerb,
+	PartOfSpeechAdjective,
+	PartOfSpeechAdverb,
+	PartOfSpeechPronoun,
+	PartOfSpeechPreposition,
+	PartOfSpeechConjunction,
+	PartOfSpeechNumeral,
+	PartOfSpeechParticle,
+	PartOfSpeechInterjection,
+	PartOfSpeechPhrase,
+}
+
+func (e PartOfSpeech) IsValid() bool {
+	switch e {
+	case PartOfSpeechNoun, PartOfSpeechVerb, PartOfSpeechAdjective, PartOfSpeechAdverb, PartOfSpeechPronoun, PartOfSpeechPreposition, PartOfSpeechConjunction, PartOfSpeechNumeral, PartOfSpeechParticle, PartOfSpeechInterjection, PartOfSpeechPhrase:
+		return true
+	}
+	return false
+}
+
+func (e PartOfSpeech) String() string {
+	return string(e)
+}
+
+func (e *PartOfSpeech) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PartOfSpeech(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PartOfSpeech", str)
+	}
+	return nil
+}
+
+func (e PartOfSpeech) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type QuestionKind string
+
+const (
+	QuestionKindMultipleChoice QuestionKind = "MULTIPLE_CHOICE"
+	QuestionKindTyping         QuestionKind = "TYPING"
+	// Sentence with the translation blanked out, the translation is the answer
+	QuestionKindCloze QuestionKind = "CLOZE"
+)
+
+var AllQuestionKind = []QuestionKind{
+	QuestionKindMultipleChoice,
+	QuestionKindTyping,
+	QuestionKindCloze,
+}
+
+func (e QuestionKind) IsValid() bool {
+	switch e {
+	case QuestionKindMultipleChoice, QuestionKindTyping, QuestionKindCloze:
+		return true
+	}
+	return false
+}
+
+func (e QuestionKind) String() string {
+	return string(e)
+}
+
+func (e *QuestionKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = QuestionKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid QuestionKind", str)
+	}
+	return nil
+}
+
+func (e QuestionKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// In other language pairs PL_EN stands for the source and EN_PL for the target language
+type QuizDirection string
+
+const (
+	// Polish word is shown, english translation is the answer
+	QuizDirectionPlEn QuizDirection = "PL_EN"
+	// English translation is shown, polish word is the answer
+	QuizDirectionEnPl QuizDirection = "EN_PL"
+)
+
+var AllQuizDirection = []QuizDirection{
+	QuizDirectionPlEn,
+	QuizDirectionEnPl,
+}
+
+func (e QuizDirection) IsValid() bool {
+	switch e {
+	case QuizDirectionPlEn, QuizDirectionEnPl:
+		return true
+	}
+	return false
+}
+
+func (e QuizDirection) String() string {
+	return string(e)
+}
+
+func (e *QuizDirection) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = QuizDirection(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid QuizDirection", str)
+	}
+	return nil
+}
+
+func (e QuizDirection) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// SYNONYM, ANTONYM and FALSE_FRIEND are symmetric. SEE_ALSO and DERIVED_FROM point from one end at the other
+type RelationKind string
+
+const (
+	RelationKindSynonym     RelationKind = "SYNONYM"
+	RelationKindAntonym     RelationKind = "ANTONYM"
+	RelationKindSeeAlso     RelationKind = "SEE_ALSO"
+	RelationKindFalseFriend RelationKind = "FALSE_FRIEND"
+	RelationKindDerivedFrom RelationKind = "DERIVED_FROM"
+)
+
+var AllRelationKind = []RelationKind{
+	RelationKindSynonym,
+	RelationKindAntonym,
+	RelationKindSeeAlso,
+	RelationKindFalseFriend,
+	RelationKindDerivedFrom,
+}
+
+func (e RelationKind) IsValid() bool {
+	switch e {
+	case RelationKindSynonym, RelationKindAntonym, RelationKindSeeAlso, RelationKindFalseFriend, RelationKindDerivedFrom:
+		return true
+	}
+	return false
+}
+
+func (e RelationKind) String() string {
+	return string(e)
+}
+
+func (e *RelationKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RelationKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RelationKind", str)
+	}
+	return nil
+}
+
+func (e RelationKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SearchScope string
+
+const (
+	SearchScopeAll          SearchScope = "ALL"
+	SearchScopeWords        SearchScope = "WORDS"
+	SearchScopeTranslations SearchScope = "TRANSLATIONS"
+	SearchScopeSentences    SearchScope = "SENTENCES"
+)
+
+var AllSearchScope = []SearchScope{
+	SearchScopeAll,
+	SearchScopeWords,
+	SearchScopeTranslations,
+	SearchScopeSentences,
+}
+
+func (e SearchScope) IsValid() bool {
+	switch e {
+	case SearchScopeAll, SearchScopeWords, SearchScopeTranslations, SearchScopeSentences:
+		return true
+	}
+	return false
+}
+
+func (e SearchScope) String() string {
+	return string(e)
+}
+
+func (e *SearchScope) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SearchScope(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SearchScope", str)
+	}
+	return nil
+}
+
+func (e SearchScope) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SortOrder string
+
+const (
+	SortOrderAsc  SortOrder = "ASC"
+	SortOrderDesc SortOrder = "DESC"
+)
+
+var AllSortOrder = []SortOrder{
+	SortOrderAsc,
+	SortOrderDesc,
+}
+
+func (e SortOrder) IsValid() bool {
+	switch e {
+	case SortOrderAsc, SortOrderDesc:
+		return true
+	}
+	return false
+}
+
+func (e SortOrder) String() string {
+	return string(e)
+}
+
+func (e *SortOrder) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SortOrder(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SortOrder", str)
+	}
+	return nil
+}
+
+func (e SortOrder) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type UsageLabel string
+
+const (
+	UsageLabelFormal     UsageLabel = "FORMAL"
+	UsageLabelColloquial UsageLabel = "COLLOQUIAL"
+	UsageLabelVulgar     UsageLabel = "VULGAR"
+	UsageLabelArchaic    UsageLabel = "ARCHAIC"
+	UsageLabelRegional   UsageLabel = "REGIONAL"
+)
+
+var AllUsageLabel = []UsageLabel{
+	UsageLabelFormal,
+	UsageLabelColloquial,
+	UsageLabelVulgar,
+	UsageLabelArchaic,
+	UsageLabelRegional,
+}
+
+func (e UsageLabel) IsValid() bool {
+	switch e {
+	case UsageLabelFormal, UsageLabelColloquial, UsageLabelVulgar, UsageLabelArchaic, UsageLabelRegional:
+		return true
+	}
+	return false
+}
+
+func (e UsageLabel) String() string {
+	return string(e)
+}
+
+func (e *UsageLabel) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = UsageLabel(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid UsageLabel", str)
+	}
+	return nil
+}
+
+func (e UsageLabel) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
 }
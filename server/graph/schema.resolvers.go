@@ -7,53 +7,367 @@ package graph
 import (
 	"context"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/staszkiet/DictionaryGolang/server/graph/model"
 )
 
+// AddWord is the resolver for the addWord field.
+func (r *mutationResolver) AddWord(ctx context.Context, text string, translation model.TranslationInput, pair *model.LanguagePair) (*model.MutationResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.CreateWordOrAddTranslationOrSentence(text, model.NewTranslation{English: translation.Text, Sentences: translation.Sentences, SentencePairs: translation.SentencePairs, Labels: translation.Labels, Domain: translation.Domain, Note: translation.Note})
+}
+
+// AddTranslation is the resolver for the addTranslation field.
+func (r *mutationResolver) AddTranslation(ctx context.Context, text string, translation model.TranslationInput, pair *model.LanguagePair) (*model.MutationResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.CreateWordOrAddTranslationOrSentence(text, model.NewTranslation{English: translation.Text, Sentences: translation.Sentences, SentencePairs: translation.SentencePairs, Labels: translation.Labels, Domain: translation.Domain, Note: translation.Note})
+}
+
+// AddSentence is the resolver for the addSentence field.
+func (r *mutationResolver) AddSentence(ctx context.Context, text string, translation string, sentence string, original *string, attribution *string, license *string, pair *model.LanguagePair) (*model.MutationResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.CreateWordOrAddTranslationOrSentence(text, model.NewTranslation{English: translation, Sentences: []string{}, SentencePairs: []*model.SentenceInput{{Sentence: sentence, Original: original, Attribution: attribution, License: license}}})
+}
+
+// RemoveSentence is the resolver for the removeSentence field.
+func (r *mutationResolver) RemoveSentence(ctx context.Context, text string, translation string, sentence string, pair *model.LanguagePair) (*model.MutationResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.DeleteSentence(text, translation, sentence)
+}
+
+// RemoveTranslation is the resolver for the removeTranslation field.
+func (r *mutationResolver) RemoveTranslation(ctx context.Context, text string, translation string, pair *model.LanguagePair) (*model.MutationResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.DeleteTranslation(text, translation)
+}
+
+// RemoveWord is the resolver for the removeWord field.
+func (r *mutationResolver) RemoveWord(ctx context.Context, text string, pair *model.LanguagePair) (*model.MutationResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.DeleteWord(text)
+}
+
+// RenameWord is the resolver for the renameWord field.
+func (r *mutationResolver) RenameWord(ctx context.Context, text string, newText string, pair *model.LanguagePair) (*model.MutationResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.UpdateWord(text, newText)
+}
+
+// RenameTranslation is the resolver for the renameTranslation field.
+func (r *mutationResolver) RenameTranslation(ctx context.Context, text string, translation string, newTranslation string, pair *model.LanguagePair) (*model.MutationResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.UpdateTranslation(text, translation, newTranslation)
+}
+
+// EditSentence is the resolver for the editSentence field.
+func (r *mutationResolver) EditSentence(ctx context.Context, text string, translation string, sentence string, newSentence string, original *string, attribution *string, license *string, pair *model.LanguagePair) (*model.MutationResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.UpdateSentence(text, translation, sentence, model.SentenceInput{Sentence: newSentence, Original: original, Attribution: attribution, License: license})
+}
+
 // CreateWord is the resolver for the createWord field.
-func (r *mutationResolver) CreateWord(ctx context.Context, polish string, translation model.NewTranslation) (bool, error) {
+func (r *mutationResolver) CreateWord(ctx context.Context, polish string, translation model.NewTranslation) (*model.MutationResult, error) {
 	return r.DB.CreateWordOrAddTranslationOrSentence(polish, translation)
 }
 
 // CreateSentence is the resolver for the createSentence field.
-func (r *mutationResolver) CreateSentence(ctx context.Context, polish string, english string, sentence string) (bool, error) {
-	return r.DB.CreateWordOrAddTranslationOrSentence(polish, model.NewTranslation{English: english, Sentences: []string{sentence}})
+func (r *mutationResolver) CreateSentence(ctx context.Context, polish string, english string, sentence string, original *string, attribution *string, license *string) (*model.MutationResult, error) {
+	return r.DB.CreateWordOrAddTranslationOrSentence(polish, model.NewTranslation{English: english, Sentences: []string{}, SentencePairs: []*model.SentenceInput{{Sentence: sentence, Original: original, Attribution: attribution, License: license}}})
 }
 
 // CreateTranslation is the resolver for the createTranslation field.
-func (r *mutationResolver) CreateTranslation(ctx context.Context, polish string, translation model.NewTranslation) (bool, error) {
+func (r *mutationResolver) CreateTranslation(ctx context.Context, polish string, translation model.NewTranslation) (*model.MutationResult, error) {
 	return r.DB.CreateWordOrAddTranslationOrSentence(polish, translation)
 }
 
 // DeleteSentence is the resolver for the deleteSentence field.
-func (r *mutationResolver) DeleteSentence(ctx context.Context, polish string, english string, sentence string) (bool, error) {
+func (r *mutationResolver) DeleteSentence(ctx context.Context, polish string, english string, sentence string) (*model.MutationResult, error) {
 	return r.DB.DeleteSentence(polish, english, sentence)
 }
 
 // DeleteTranslation is the resolver for the deleteTranslation field.
-func (r *mutationResolver) DeleteTranslation(ctx context.Context, polish string, english string) (bool, error) {
+func (r *mutationResolver) DeleteTranslation(ctx context.Context, polish string, english string) (*model.MutationResult, error) {
 	return r.DB.DeleteTranslation(polish, english)
 }
 
 // DeleteWord is the resolver for the deleteWord field.
-func (r *mutationResolver) DeleteWord(ctx context.Context, polish string) (bool, error) {
-
+func (r *mutationResolver) DeleteWord(ctx context.Context, polish string) (*model.MutationResult, error) {
 	return r.DB.DeleteWord(polish)
 }
 
 // UpdateWord is the resolver for the updateWord field.
-func (r *mutationResolver) UpdateWord(ctx context.Context, polish string, newPolish string) (bool, error) {
+func (r *mutationResolver) UpdateWord(ctx context.Context, polish string, newPolish string) (*model.MutationResult, error) {
 	return r.DB.UpdateWord(polish, newPolish)
 }
 
 // UpdateTranslation is the resolver for the updateTranslation field.
-func (r *mutationResolver) UpdateTranslation(ctx context.Context, polish string, english string, newEnglish string) (bool, error) {
+func (r *mutationResolver) UpdateTranslation(ctx context.Context, polish string, english string, newEnglish string) (*model.MutationResult, error) {
 	return r.DB.UpdateTranslation(polish, english, newEnglish)
 }
 
 // UpdateSentence is the resolver for the updateSentence field.
-func (r *mutationResolver) UpdateSentence(ctx context.Context, polish string, english string, sentence string, newSentence string) (bool, error) {
-	return r.DB.UpdateSentence(polish, english, sentence, newSentence)
+func (r *mutationResolver) UpdateSentence(ctx context.Context, polish string, english string, sentence string, newSentence string, original *string, attribution *string, license *string) (*model.MutationResult, error) {
+	return r.DB.UpdateSentence(polish, english, sentence, model.SentenceInput{Sentence: newSentence, Original: original, Attribution: attribution, License: license})
+}
+
+// ImportWords is the resolver for the importWords field.
+func (r *mutationResolver) ImportWords(ctx context.Context, entries []*model.NewWordEntry, mode *model.ImportMode, pair *model.LanguagePair) ([]*model.ImportEntryResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.ImportWords(entries, mode)
+}
+
+// ImportFile is the resolver for the importFile field.
+func (r *mutationResolver) ImportFile(ctx context.Context, file graphql.Upload, options *model.FileImportOptions, pair *model.LanguagePair) (*model.ImportReport, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.ImportFile(file.File, options)
+}
+
+// SetGrammar is the resolver for the setGrammar field.
+func (r *mutationResolver) SetGrammar(ctx context.Context, polish string, grammar model.GrammarInput, pair *model.LanguagePair) (*model.MutationResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.SetGrammar(polish, grammar)
+}
+
+// SetCountability is the resolver for the setCountability field.
+func (r *mutationResolver) SetCountability(ctx context.Context, polish string, english string, countability *model.Countability, pair *model.LanguagePair) (*model.MutationResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.SetCountability(polish, english, countability)
+}
+
+// AddInflection is the resolver for the addInflection field.
+func (r *mutationResolver) AddInflection(ctx context.Context, polish string, form string, tags []string, pair *model.LanguagePair) (*model.MutationResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.AddInflection(polish, form, tags)
+}
+
+// DeleteInflection is the resolver for the deleteInflection field.
+func (r *mutationResolver) DeleteInflection(ctx context.Context, polish string, form string, tags []string, pair *model.LanguagePair) (*model.MutationResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.DeleteInflection(polish, form, tags)
+}
+
+// ImportInflections is the resolver for the importInflections field.
+func (r *mutationResolver) ImportInflections(ctx context.Context, entries []*model.NewInflection, mode *model.ImportMode, pair *model.LanguagePair) ([]*model.InflectionImportResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.ImportInflections(entries, mode)
+}
+
+// AddRelation is the resolver for the addRelation field.
+func (r *mutationResolver) AddRelation(ctx context.Context, kind model.RelationKind, from model.RelationEnd, to model.RelationEnd, pair *model.LanguagePair) (*model.MutationResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.AddRelation(kind, from, to)
+}
+
+// RemoveRelation is the resolver for the removeRelation field.
+func (r *mutationResolver) RemoveRelation(ctx context.Context, kind model.RelationKind, from model.RelationEnd, to model.RelationEnd, pair *model.LanguagePair) (*model.MutationResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.RemoveRelation(kind, from, to)
+}
+
+// TagWord is the resolver for the tagWord field.
+func (r *mutationResolver) TagWord(ctx context.Context, text string, tags []string, pair *model.LanguagePair) (*model.MutationResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.TagWord(text, tags)
+}
+
+// UntagWord is the resolver for the untagWord field.
+func (r *mutationResolver) UntagWord(ctx context.Context, text string, tags []string, pair *model.LanguagePair) (*model.MutationResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.UntagWord(text, tags)
+}
+
+// RenameTag is the resolver for the renameTag field.
+func (r *mutationResolver) RenameTag(ctx context.Context, name string, newName string) (*model.Tag, error) {
+	return r.DB.RenameTag(name, newName)
+}
+
+// DeleteTag is the resolver for the deleteTag field.
+func (r *mutationResolver) DeleteTag(ctx context.Context, name string) (*model.Tag, error) {
+	return r.DB.DeleteTag(name)
+}
+
+// CreateCollection is the resolver for the createCollection field.
+func (r *mutationResolver) CreateCollection(ctx context.Context, name string) (*model.Collection, error) {
+	return r.DB.CreateCollection(name)
+}
+
+// RenameCollection is the resolver for the renameCollection field.
+func (r *mutationResolver) RenameCollection(ctx context.Context, name string, newName string) (*model.Collection, error) {
+	return r.DB.RenameCollection(name, newName)
+}
+
+// DeleteCollection is the resolver for the deleteCollection field.
+func (r *mutationResolver) DeleteCollection(ctx context.Context, name string) (*model.Collection, error) {
+	return r.DB.DeleteCollection(name)
+}
+
+// AddToCollection is the resolver for the addToCollection field.
+func (r *mutationResolver) AddToCollection(ctx context.Context, name string, text string, translation string, pair *model.LanguagePair) (*model.Collection, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.AddToCollection(name, text, translation)
+}
+
+// RemoveFromCollection is the resolver for the removeFromCollection field.
+func (r *mutationResolver) RemoveFromCollection(ctx context.Context, name string, text string, translation string, pair *model.LanguagePair) (*model.Collection, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.RemoveFromCollection(name, text, translation)
+}
+
+// SetUsage is the resolver for the setUsage field.
+func (r *mutationResolver) SetUsage(ctx context.Context, text string, translation string, usage model.UsageInput, pair *model.LanguagePair) (*model.MutationResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.SetUsage(text, translation, usage)
+}
+
+// ReorderTranslations is the resolver for the reorderTranslations field.
+func (r *mutationResolver) ReorderTranslations(ctx context.Context, polish string, english []string, pair *model.LanguagePair) (*model.MutationResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.ReorderTranslations(polish, english)
+}
+
+// ReorderSentences is the resolver for the reorderSentences field.
+func (r *mutationResolver) ReorderSentences(ctx context.Context, polish string, english string, sentences []string, pair *model.LanguagePair) (*model.MutationResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.ReorderSentences(polish, english, sentences)
+}
+
+// SetPronunciation is the resolver for the setPronunciation field.
+func (r *mutationResolver) SetPronunciation(ctx context.Context, text string, translation *string, pronunciation model.PronunciationInput, pair *model.LanguagePair) (*model.MutationResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.SetPronunciation(text, translation, pronunciation)
+}
+
+// UploadAudio is the resolver for the uploadAudio field.
+func (r *mutationResolver) UploadAudio(ctx context.Context, text string, translation *string, file graphql.Upload, pair *model.LanguagePair) (*model.MutationResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.UploadAudio(text, translation, file.Filename, file.File)
+}
+
+// DeleteAudio is the resolver for the deleteAudio field.
+func (r *mutationResolver) DeleteAudio(ctx context.Context, text string, translation *string, pair *model.LanguagePair) (*model.MutationResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.DeleteAudio(text, translation)
+}
+
+// GradeCard is the resolver for the gradeCard field.
+func (r *mutationResolver) GradeCard(ctx context.Context, translationID string, grade int32) (*model.Card, error) {
+	return r.DB.GradeCard(translationID, grade)
+}
+
+// SubmitQuiz is the resolver for the submitQuiz field.
+func (r *mutationResolver) SubmitQuiz(ctx context.Context, answers []*model.QuizAnswer) (*model.QuizResult, error) {
+	return r.DB.SubmitQuiz(answers)
+}
+
+// Word is the resolver for the word field.
+func (r *queryResolver) Word(ctx context.Context, text string, pair *model.LanguagePair) (*model.Word, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.SelectWord(text)
+}
+
+// WordsByTranslation is the resolver for the wordsByTranslation field.
+func (r *queryResolver) WordsByTranslation(ctx context.Context, text string, pair *model.LanguagePair) ([]*model.Word, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.SelectByEnglish(text)
+}
+
+// Words is the resolver for the words field.
+func (r *queryResolver) Words(ctx context.Context, first *int32, after *string, prefix *string, order *model.SortOrder, grammar *model.GrammarFilter, tag *string, pair *model.LanguagePair) (*model.WordConnection, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.ListWords(first, after, prefix, order, grammar, tag)
 }
 
 // SelectWord is the resolver for the selectWord field.
@@ -61,11 +375,85 @@ func (r *queryResolver) SelectWord(ctx context.Context, polish string) (*model.W
 	return r.DB.SelectWord(polish)
 }
 
+// SelectByEnglish is the resolver for the selectByEnglish field.
+func (r *queryResolver) SelectByEnglish(ctx context.Context, english string) ([]*model.Word, error) {
+	return r.DB.SelectByEnglish(english)
+}
+
+// ListWords is the resolver for the listWords field.
+func (r *queryResolver) ListWords(ctx context.Context, first *int32, after *string, prefix *string, order *model.SortOrder, grammar *model.GrammarFilter, tag *string) (*model.WordConnection, error) {
+	return r.DB.ListWords(first, after, prefix, order, grammar, tag)
+}
+
+// Search is the resolver for the search field.
+func (r *queryResolver) Search(ctx context.Context, text string, scope *model.SearchScope, grammar *model.GrammarFilter, tag *string, pair *model.LanguagePair) ([]model.SearchResult, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.Search(text, scope, grammar, tag)
+}
+
+// Tags is the resolver for the tags field.
+func (r *queryResolver) Tags(ctx context.Context) ([]*model.Tag, error) {
+	return r.DB.Tags()
+}
+
+// Collections is the resolver for the collections field.
+func (r *queryResolver) Collections(ctx context.Context) ([]*model.Collection, error) {
+	return r.DB.Collections()
+}
+
+// Collection is the resolver for the collection field.
+func (r *queryResolver) Collection(ctx context.Context, name string) (*model.Collection, error) {
+	return r.DB.Collection(name)
+}
+
+// Autocomplete is the resolver for the autocomplete field.
+func (r *queryResolver) Autocomplete(ctx context.Context, prefix string, language *model.Language, limit *int32, pair *model.LanguagePair) ([]string, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.Autocomplete(prefix, language, limit)
+}
+
+// DueCards is the resolver for the dueCards field.
+func (r *queryResolver) DueCards(ctx context.Context, limit *int32, pair *model.LanguagePair) ([]*model.Card, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.DueCards(limit)
+}
+
+// GenerateQuiz is the resolver for the generateQuiz field.
+func (r *queryResolver) GenerateQuiz(ctx context.Context, size *int32, direction *model.QuizDirection, kinds []model.QuestionKind, pair *model.LanguagePair) ([]*model.QuizQuestion, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.GenerateQuiz(size, direction, kinds)
+}
+
+// WordChanged is the resolver for the wordChanged field.
+func (r *subscriptionResolver) WordChanged(ctx context.Context, polish *string, pair *model.LanguagePair) (<-chan *model.WordChangedEvent, error) {
+	dictionary, err := r.DB.InPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.WordChanged(ctx, polish)
+}
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
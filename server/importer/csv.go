@@ -0,0 +1,149 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+
+	customerrors "github.com/staszkiet/DictionaryGolang/server/errors"
+	"github.com/staszkiet/DictionaryGolang/server/graph/model"
+)
+
+// Describes the layout of an imported CSV/TSV file
+type Options struct {
+	Delimiter rune
+	// Whether the first row names the columns. Without it the columns are polish, english and example sentences
+	Header        bool
+	PolishColumn  string
+	EnglishColumn string
+	// Columns with example sentences. When empty, every column which name starts with "sentence" is used
+	SentenceColumns []string
+}
+
+func DefaultOptions() Options {
+	return Options{Delimiter: ',', Header: true, PolishColumn: "polish", EnglishColumn: "english"}
+}
+
+// A row of the imported file. Entry is nil when the row couldn't be read
+type Row struct {
+	Line  int
+	Entry *model.NewWordEntry
+	Err   error
+}
+
+type columns struct {
+	polish    int
+	english   int
+	sentences []int
+}
+
+// Reads rows of a CSV/TSV file. Malformed rows are returned with an error, so they can be reported
+// with their line numbers while the rest of the file is imported
+func Parse(r io.Reader, options Options) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = options.Delimiter
+	reader.FieldsPerRecord = -1
+	//tab separated files don't quote fields, so quotes are kept as a part of the text
+	reader.LazyQuotes = options.Delimiter == '\t'
+	//a tab counts as leading space too, so trimming it would swallow empty cells and shift the following columns.
+	//Cells are trimmed when the entry is built anyway
+	reader.TrimLeadingSpace = options.Delimiter != '\t'
+
+	cols := columns{polish: 0, english: 1}
+	positional := true
+
+	if options.Header {
+		header, err := reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return []Row{}, nil
+			}
+			return nil, err
+		}
+		if cols, err = headerColumns(header, options); err != nil {
+			return nil, err
+		}
+		positional = false
+	}
+
+	rows := []Row{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rows = append(rows, Row{Line: parseErr.StartLine, Err: customerrors.MalformedRowError{Line: parseErr.StartLine}})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		if positional {
+			cols.sentences = cols.sentences[:0]
+			for i := 2; i < len(record); i++ {
+				cols.sentences = append(cols.sentences, i)
+			}
+		}
+		rows = append(rows, Row{Line: line, Entry: cols.entry(record)})
+	}
+
+	return rows, nil
+}
+
+func headerColumns(header []string, options Options) (columns, error) {
+	cols := columns{polish: -1, english: -1}
+
+	wanted := map[string]bool{}
+	for _, name := range options.SentenceColumns {
+		wanted[strings.ToLower(name)] = true
+	}
+
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch {
+		case name == strings.ToLower(options.PolishColumn):
+			cols.polish = i
+		case name == strings.ToLower(options.EnglishColumn):
+			cols.english = i
+		case wanted[name], len(wanted) == 0 && strings.HasPrefix(name, "sentence"):
+			cols.sentences = append(cols.sentences, i)
+		}
+	}
+
+	if cols.polish < 0 {
+		return cols, customerrors.MissingColumnError{Column: options.PolishColumn}
+	}
+	if cols.english < 0 {
+		return cols, customerrors.MissingColumnError{Column: options.EnglishColumn}
+	}
+	return cols, nil
+}
+
+// Builds the entry from a record. Missing and empty cells of sentences are left out,
+// because spreadsheets pad rows with empty cells
+func (c columns) entry(record []string) *model.NewWordEntry {
+	cell := func(i int) string {
+		if i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	sentences := []string{}
+	for _, i := range c.sentences {
+		if s := cell(i); s != "" {
+			sentences = append(sentences, s)
+		}
+	}
+
+	return &model.NewWordEntry{
+		Polish:      cell(c.polish),
+		Translation: &model.NewTranslation{English: cell(c.english), Sentences: sentences},
+	}
+}
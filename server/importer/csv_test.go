@@ -0,0 +1,100 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	customerrors "github.com/staszkiet/DictionaryGolang/server/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_WithHeader_ShouldMapColumnsByName(t *testing.T) {
+	file := "english,polish,sentence 1,sentence 2,notes\n" +
+		"bike,rower,I like my bike,,green\n" +
+		"cat,kot,My cat is black,Cats sleep a lot,\n"
+
+	rows, err := Parse(strings.NewReader(file), DefaultOptions())
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, 2, rows[0].Line)
+	assert.Equal(t, "rower", rows[0].Entry.Polish)
+	assert.Equal(t, "bike", rows[0].Entry.Translation.English)
+	assert.Equal(t, []string{"I like my bike"}, rows[0].Entry.Translation.Sentences)
+	assert.Equal(t, 3, rows[1].Line)
+	assert.Equal(t, []string{"My cat is black", "Cats sleep a lot"}, rows[1].Entry.Translation.Sentences)
+}
+
+func TestParse_TSVWithoutHeader_ShouldUsePositionalColumns(t *testing.T) {
+	file := "rower\tbike\tI like my \"new\" bike\n" +
+		"\n" +
+		"kot\tcat\n"
+
+	options := DefaultOptions()
+	options.Delimiter = '\t'
+	options.Header = false
+
+	rows, err := Parse(strings.NewReader(file), options)
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, []string{`I like my "new" bike`}, rows[0].Entry.Translation.Sentences)
+	assert.Equal(t, 3, rows[1].Line)
+	assert.Equal(t, "kot", rows[1].Entry.Polish)
+	assert.Empty(t, rows[1].Entry.Translation.Sentences)
+}
+
+func TestParse_TSVWithEmptyCell_ShouldKeepColumns(t *testing.T) {
+	file := "polish\tenglish\tsentence\n" +
+		"rower\t\tI like my bike\n" +
+		"kot\t cat\t\n"
+
+	options := DefaultOptions()
+	options.Delimiter = '\t'
+
+	rows, err := Parse(strings.NewReader(file), options)
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "rower", rows[0].Entry.Polish)
+	assert.Equal(t, "", rows[0].Entry.Translation.English)
+	assert.Equal(t, []string{"I like my bike"}, rows[0].Entry.Translation.Sentences)
+	assert.Equal(t, "cat", rows[1].Entry.Translation.English)
+	assert.Empty(t, rows[1].Entry.Translation.Sentences)
+}
+
+func TestParse_CustomColumnNames(t *testing.T) {
+	file := "słowo;tłumaczenie;przykład\n" +
+		"rower;bike;I like my bike\n"
+
+	options := Options{Delimiter: ';', Header: true, PolishColumn: "słowo", EnglishColumn: "tłumaczenie", SentenceColumns: []string{"przykład"}}
+
+	rows, err := Parse(strings.NewReader(file), options)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "rower", rows[0].Entry.Polish)
+	assert.Equal(t, []string{"I like my bike"}, rows[0].Entry.Translation.Sentences)
+}
+
+func TestParse_MissingColumn_ShouldReturnError(t *testing.T) {
+	file := "polish,translation\nrower,bike\n"
+
+	rows, err := Parse(strings.NewReader(file), DefaultOptions())
+
+	assert.Nil(t, rows)
+	assert.Equal(t, customerrors.MissingColumnError{Column: "english"}, err)
+}
+
+func TestParse_MalformedRow_ShouldBeReturnedWithError(t *testing.T) {
+	file := "polish,english\n" +
+		"kot,c\"at\n" +
+		"rower,bike\n"
+
+	rows, err := Parse(strings.NewReader(file), DefaultOptions())
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, customerrors.MalformedRowError{Line: 2}, rows[0].Err)
+	assert.Nil(t, rows[0].Entry)
+	assert.Equal(t, "rower", rows[1].Entry.Polish)
+}
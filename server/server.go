@@ -4,22 +4,40 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/handler/extension"
 	"github.com/99designs/gqlgen/graphql/handler/lru"
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/staszkiet/DictionaryGolang/server/audio"
 	"github.com/staszkiet/DictionaryGolang/server/database"
+	"github.com/staszkiet/DictionaryGolang/server/exporter"
 	"github.com/staszkiet/DictionaryGolang/server/graph"
 	"github.com/vektah/gqlparser/v2/ast"
 )
 
-const defaultPort = "8080"
+const (
+	defaultPort = "8080"
+	// directory of the audio clips, relative to the working directory
+	defaultAudioDir = "clips"
+	// imported word lists are small text files and audio clips of single words are short
+	maxUploadSize = 10 << 20
+)
 
 func main() {
 
-	db := database.NewDatabaseService()
+	audioDir := os.Getenv("AUDIO_DIR")
+	if audioDir == "" {
+		audioDir = defaultAudioDir
+	}
+	clips, err := audio.NewFileStore(audioDir)
+	if err != nil {
+		log.Fatal("Failed to create audio directory:", err)
+	}
+
+	db := database.NewDatabaseService(clips)
 	resolver := &graph.Resolver{DB: db}
 
 	port := os.Getenv("PORT")
@@ -32,6 +50,15 @@ func main() {
 	srv.AddTransport(transport.Options{})
 	srv.AddTransport(transport.GET{})
 	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{
+		MaxUploadSize: maxUploadSize,
+		MaxMemory:     maxUploadSize,
+	})
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+	})
+
+	srv.SetErrorPresenter(graph.ErrorPresenter)
 
 	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
 
@@ -41,7 +68,9 @@ func main() {
 	})
 
 	http.Handle("/", playground.Handler("GraphQL playground", "/query"))
-	http.Handle("/query", srv)
+	http.Handle("/query", graph.AcceptLanguage(srv))
+	http.Handle("/export", graph.AcceptLanguage(exporter.Handler(db)))
+	http.Handle(audio.Route, audio.Handler(clips))
 
 	log.Printf("connect to http://localhost:%s/ for GraphQL playground", port)
 	log.Fatal(http.ListenAndServe(":"+port, nil))
@@ -0,0 +1,57 @@
+package study
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// Grades are the quality of recall, from 0 (complete blackout) to 5 (perfect answer)
+	MinGrade = 0
+	MaxGrade = 5
+	// Answers graded lower than this were forgotten and the card is learned again from the start
+	PassingGrade = 3
+
+	InitialEaseFactor = 2.5
+	MinEaseFactor     = 1.3
+)
+
+// Spaced repetition state of a single card
+type State struct {
+	EaseFactor  float64
+	Interval    int //days until the next review
+	Repetitions int //successful reviews in a row
+	Lapses      int //times the card was forgotten after it was recalled at least once
+	Due         time.Time
+}
+
+// Returns the state of a card which was never reviewed, due immediately
+func NewState(now time.Time) State {
+	return State{EaseFactor: InitialEaseFactor, Due: now}
+}
+
+// Schedules the next review of the card answered with given grade, according to the SM-2 algorithm
+func (s State) Grade(grade int, now time.Time) State {
+	if grade >= PassingGrade {
+		switch s.Repetitions {
+		case 0:
+			s.Interval = 1
+		case 1:
+			s.Interval = 6
+		default:
+			s.Interval = int(math.Round(float64(s.Interval) * s.EaseFactor))
+		}
+		s.Repetitions++
+	} else {
+		if s.Repetitions > 0 {
+			s.Lapses++
+		}
+		s.Repetitions = 0
+		s.Interval = 1
+	}
+
+	miss := float64(MaxGrade - grade)
+	s.EaseFactor = math.Max(MinEaseFactor, s.EaseFactor+0.1-miss*(0.08+miss*0.02))
+	s.Due = now.AddDate(0, 0, s.Interval)
+	return s
+}
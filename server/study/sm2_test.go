@@ -0,0 +1,53 @@
+package study
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestGrade_CorrectAnswers_ShouldGrowInterval(t *testing.T) {
+	state := NewState(now)
+
+	state = state.Grade(4, now)
+	assert.Equal(t, 1, state.Interval)
+	assert.Equal(t, now.AddDate(0, 0, 1), state.Due)
+
+	state = state.Grade(4, now)
+	assert.Equal(t, 6, state.Interval)
+
+	state = state.Grade(5, now)
+	assert.Equal(t, 15, state.Interval)
+	assert.Equal(t, 3, state.Repetitions)
+	assert.InDelta(t, 2.6, state.EaseFactor, 1e-9)
+}
+
+func TestGrade_ForgottenCard_ShouldStartOverAndCountLapse(t *testing.T) {
+	state := State{EaseFactor: 2.5, Interval: 15, Repetitions: 3, Due: now}
+
+	state = state.Grade(1, now)
+
+	assert.Equal(t, 1, state.Interval)
+	assert.Equal(t, 0, state.Repetitions)
+	assert.Equal(t, 1, state.Lapses)
+	assert.InDelta(t, 1.96, state.EaseFactor, 1e-9)
+}
+
+func TestGrade_NewCardForgotten_ShouldNotCountLapse(t *testing.T) {
+	state := NewState(now).Grade(0, now)
+
+	assert.Equal(t, 0, state.Lapses)
+	assert.Equal(t, 1, state.Interval)
+}
+
+func TestGrade_ShouldKeepMinimalEaseFactor(t *testing.T) {
+	state := State{EaseFactor: 1.4, Interval: 6, Repetitions: 2}
+
+	state = state.Grade(3, now)
+
+	assert.Equal(t, MinEaseFactor, state.EaseFactor)
+	assert.Equal(t, 8, state.Interval)
+}